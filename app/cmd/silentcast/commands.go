@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/SphereStacking/silentcast/internal/commands"
+)
+
+// CommandFlags is an alias to internal/commands.Flags
+type CommandFlags = commands.Flags
+
+// ParseFlags parses command-line flags and returns the flags struct
+func ParseFlags() *CommandFlags {
+	flags := &CommandFlags{}
+
+	// Define custom usage function using command registry
+	flag.Usage = func() {
+		// Create a temporary registry to generate help
+		tmpFlags := &CommandFlags{}
+		registry := NewCommandRegistry(tmpFlags)
+		fmt.Fprint(os.Stderr, registry.GenerateHelp())
+	}
+
+	// Core flags
+	flag.BoolVar(&flags.NoTray, "no-tray", false, "Disable system tray integration")
+	flag.BoolVar(&flags.Version, "version", false, "Print version and exit")
+	flag.BoolVar(&flags.Debug, "debug", false, "Enable debug logging")
+	flag.StringVar(&flags.VersionFormat, "version-format", "human", "Version output format: human, json, compact")
+
+	// Config commands
+	flag.BoolVar(&flags.ValidateConfig, "validate-config", false, "Validate configuration and exit")
+	flag.BoolVar(&flags.ShowConfig, "show-config", false, "Show merged configuration and exit")
+	flag.BoolVar(&flags.ShowConfigPath, "show-config-path", false, "Show configuration file search paths")
+	flag.StringVar(&flags.ShowFormat, "format", "human", "Output format for show-config (human, json, yaml), lint (human, json, sarif), config-diff, notification-status, dnd, events and notifications (human, json)")
+	flag.BoolVar(&flags.ShowPaths, "show-paths", false, "Show configuration search paths with show-config")
+	flag.BoolVar(&flags.LSP, "lsp", false, "Run language server for spellbook files over stdio")
+	flag.BoolVar(&flags.MigrateConfig, "migrate-config", false, "Upgrade spellbook files to the current format version")
+	flag.BoolVar(&flags.Lint, "lint", false, "Check configuration for unused, dangling, and shadowed entries")
+	flag.BoolVar(&flags.ConfigDiff, "config-diff", false, "Show what each configuration layer changes compared to the base")
+
+	// Spell commands
+	flag.BoolVar(&flags.ListSpells, "list-spells", false, "List all configured spells")
+	flag.StringVar(&flags.ListFilter, "filter", "", "Filter spells by sequence, name, or description, or notifications by text")
+
+	// Spell editing commands
+	flag.StringVar(&flags.AddSpell, "add-spell", "", "Add a spell with the given key sequence")
+	flag.StringVar(&flags.RemoveSpell, "remove-spell", "", "Remove the spell with the given key sequence")
+	flag.StringVar(&flags.RenameSpell, "rename-spell", "", "Rename the spell with the given key sequence")
+	flag.StringVar(&flags.RenameTo, "to", "", "New key sequence for rename-spell")
+	flag.StringVar(&flags.RenameToAction, "to-action", "", "New grimoire action name for rename-spell, applied to every spell using it")
+	flag.StringVar(&flags.SpellAction, "action", "", "Grimoire action name for add-spell")
+	flag.StringVar(&flags.ActionType, "action-type", "script", "Type of a new grimoire action: app, script, url")
+	flag.StringVar(&flags.ActionCommand, "action-command", "", "Command of a new grimoire action for add-spell")
+	flag.StringVar(&flags.ActionDescription, "action-description", "", "Description of a new grimoire action for add-spell")
+	flag.BoolVar(&flags.KeepAction, "keep-action", false, "Keep the grimoire action when removing a spell")
+
+	// Debug commands
+	flag.BoolVar(&flags.TestHotkey, "test-hotkey", false, "Test hotkey detection")
+	flag.BoolVar(&flags.NotificationStatus, "notification-status", false, "Show notification queue metrics of the running daemon")
+	flag.IntVar(&flags.TestDuration, "duration", 0, "Test duration in seconds (0 = until Ctrl+C)")
+	flag.StringVar(&flags.TestSound, "test-sound", "", "Play the sound cue of an event: prefix, success, failure, timeout, all")
+
+	// Notification commands
+	flag.StringVar(&flags.DND, "dnd", "", "Control do-not-disturb of the running daemon: on, off, toggle, status, clear")
+	flag.BoolVar(&flags.Events, "events", false, "Stream events of the running daemon; with --format json, one JSON line each for status bars")
+	flag.BoolVar(&flags.Notifications, "notifications", false, "List past notifications from the history")
+	flag.Int64Var(&flags.NotificationID, "notification", 0, "Show a past notification with its full output")
+	flag.IntVar(&flags.NotificationLimit, "limit", 20, "Number of notifications listed by notifications")
+	flag.StringVar(&flags.Logs, "logs", "", "Show the output log of the latest run of a spell")
+
+	// Single execution mode
+	flag.BoolVar(&flags.Once, "once", false, "Execute a spell once and exit")
+	flag.StringVar(&flags.SpellName, "spell", "", "Spell to execute in once mode")
+	flag.BoolVar(&flags.TestSpell, "test-spell", false, "Test a spell with detailed debug information")
+	flag.BoolVar(&flags.DryRun, "dry-run", false, "Show what would be executed without actually running it")
+
+	// Export/Import commands
+	flag.StringVar(&flags.ExportConfig, "export-config", "", "Export configuration to file (or stdout if empty)")
+	flag.StringVar(&flags.ExportFormat, "export-format", "yaml", "Export format: yaml, tar.gz")
+	flag.StringVar(&flags.ImportConfig, "import-config", "", "Import configuration from file")
+
+	// Service management (Windows only)
+	flag.BoolVar(&flags.ServiceInstall, "service-install", false, "Install SilentCast as system service (Windows)")
+	flag.BoolVar(&flags.ServiceUninstall, "service-uninstall", false, "Uninstall SilentCast service (Windows)")
+	flag.BoolVar(&flags.ServiceStart, "service-start", false, "Start SilentCast service (Windows)")
+	flag.BoolVar(&flags.ServiceStop, "service-stop", false, "Stop SilentCast service (Windows)")
+	flag.BoolVar(&flags.ServiceStatus, "service-status", false, "Show SilentCast service status (Windows)")
+
+	// Update commands
+	flag.BoolVar(&flags.CheckUpdate, "check-update", false, "Check for available updates")
+	flag.BoolVar(&flags.ForceUpdateCheck, "force-update-check", false, "Force update check (ignore cache)")
+	flag.BoolVar(&flags.SelfUpdate, "self-update", false, "Update SilentCast to the latest version")
+	flag.BoolVar(&flags.ForceSelfUpdate, "force", false, "Skip confirmation prompts for self-update")
+	flag.BoolVar(&flags.UpdateStatus, "update-status", false, "Show current update status and available updates")
+
+	flag.Parse()
+	return flags
+}
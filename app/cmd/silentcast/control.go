@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/action"
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/control"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/internal/events"
+	"github.com/SphereStacking/silentcast/internal/hotkey"
+	"github.com/SphereStacking/silentcast/internal/notify"
+	"github.com/SphereStacking/silentcast/internal/sound"
+	"github.com/SphereStacking/silentcast/internal/tray"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// notificationState is the notification state that outlives configuration
+// reloads: do-not-disturb, history, coalescing, the event stream, sound cues,
+// the env files webhooks are expanded with, and the handler for update action
+// buttons
+type notificationState struct {
+	dnd       *notify.DoNotDisturb
+	history   *notify.History
+	coalescer *notify.Coalescer
+	events    *events.Bus
+	sounds    *sound.Manager
+	env       *dotenv.Scope
+
+	mu            sync.Mutex
+	updateActions func(action notify.UpdateAction, updateInfo *notify.UpdateNotification) error
+}
+
+// newManager creates a notification manager for cfg that honours
+// do-not-disturb and records notifications in history
+func (s *notificationState) newManager(cfg *config.Config) *notify.Manager {
+	s.dnd.Configure(cfg.Notification.DoNotDisturb)
+	manager := notify.NewManagerFromConfig(cfg, s.env)
+	manager.SetDoNotDisturb(s.dnd)
+	manager.SetUpdateActionHandler(s.handleUpdateAction)
+	manager.SetEvents(s.events)
+	if err := s.sounds.Configure(cfg.Notification); err != nil {
+		logger.Warn("Sound cues disabled: %v", err)
+	}
+	manager.SetSounds(s.sounds)
+
+	window := time.Duration(cfg.Notification.Coalesce.Window) * time.Second
+	if !cfg.Notification.Coalesce.CoalesceEnabled() {
+		window = 0
+	}
+	s.coalescer.SetWindow(window)
+	manager.SetCoalescer(s.coalescer)
+	if cfg.Notification.History.HistoryEnabled() {
+		s.history.SetMaxEntries(cfg.Notification.History.MaxEntries)
+		manager.SetHistory(s.history)
+	}
+	return manager
+}
+
+// setUpdateActionHandler sets the function that runs update action buttons
+func (s *notificationState) setUpdateActionHandler(handler func(action notify.UpdateAction, updateInfo *notify.UpdateNotification) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updateActions = handler
+}
+
+// handleUpdateAction runs an update action button pressed on a notification
+func (s *notificationState) handleUpdateAction(action notify.UpdateAction, updateInfo *notify.UpdateNotification) error {
+	s.mu.Lock()
+	handler := s.updateActions
+	s.mu.Unlock()
+	if handler == nil {
+		return fmt.Errorf("update checks are not running")
+	}
+	return handler(action, updateInfo)
+}
+
+// dndController switches do-not-disturb and keeps the tray item in sync.
+// It is shared by the tray menu and the control socket.
+type dndController struct {
+	dnd      *notify.DoNotDisturb
+	notifier notify.Sender
+
+	mu   sync.Mutex
+	item *tray.MenuItem
+}
+
+// setTrayItem attaches the tray menu item that reflects the manual state
+func (c *dndController) setTrayItem(item *tray.MenuItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.item = item
+}
+
+// apply runs a do-not-disturb command. When do-not-disturb ends with
+// notifications held, a summary points to where they can be reviewed.
+func (c *dndController) apply(ctx context.Context, command string) (notify.DNDStatus, error) {
+	wasActive := c.dnd.Active()
+	status, err := c.dnd.Apply(command)
+	if err != nil {
+		return status, err
+	}
+
+	c.mu.Lock()
+	if c.item != nil {
+		c.item.SetChecked(status.Enabled)
+	}
+	c.mu.Unlock()
+
+	logger.Info("Do not disturb: enabled=%t quiet_hours=%t held=%d", status.Enabled, status.QuietHours, len(status.Held))
+	if wasActive && !status.Active && len(status.Held) > 0 {
+		if err := c.notifier.Info(ctx, "Do Not Disturb Off",
+			fmt.Sprintf("%d notifications were held. Run '%s --dnd status' to review them.", len(status.Held), config.AppName)); err != nil {
+			logger.Error("Failed to send notification: %v", err)
+		}
+	}
+	return status, nil
+}
+
+// reportPrefix publishes the prefix key state of hotkey managers that report
+// it and plays the prefix cue. The cue is feedback for a key press, so it
+// plays during do-not-disturb too.
+func (s *notificationState) reportPrefix(manager hotkey.Manager) {
+	reporter, ok := manager.(hotkey.PrefixReporter)
+	if !ok {
+		return
+	}
+	reporter.SetPrefixHandler(func(active bool) {
+		s.events.SetPrefix(active)
+		if active {
+			s.sounds.Play(config.SoundEventPrefix)
+		}
+	})
+}
+
+// startControlServer serves the control socket in the configuration directory
+func startControlServer(ctx context.Context, configPath string, dnd *dndController, bus *events.Bus) (*control.Server, error) {
+	server := control.NewServer(control.SocketPath(configPath))
+
+	server.Handle("ping", func(ctx context.Context, args []string) (string, interface{}, error) {
+		return "pong", nil, nil
+	})
+	server.Handle("dnd", func(ctx context.Context, args []string) (string, interface{}, error) {
+		command := "status"
+		if len(args) > 0 {
+			command = args[0]
+		}
+		status, err := dnd.apply(ctx, command)
+		if err != nil {
+			return "", nil, err
+		}
+		return "", status, nil
+	})
+	server.Handle("status", func(ctx context.Context, args []string) (string, interface{}, error) {
+		return "", bus.State(), nil
+	})
+	server.HandleStream("subscribe", func(ctx context.Context, args []string, send func(data interface{}) error) error {
+		stream, cancel := bus.Subscribe()
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case event := <-stream:
+				if err := send(event); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	if err := server.Start(ctx); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// notifySpellFailed reports a failed spell with a button to cast it again
+func notifySpellFailed(ctx context.Context, notifier notify.Sender, actions *action.Manager, spell string, err error) error {
+	return notify.ForSpell(notifier, spell).Notify(ctx, notify.Notification{
+		Title:   "Spell Failed",
+		Message: err.Error(),
+		Level:   notify.LevelError,
+		Actions: []notify.Action{{
+			Key:   "rerun",
+			Label: "Re-run",
+			Handler: func() {
+				logger.Info("Re-running spell from notification: %s", spell)
+				if execErr := actions.Execute(ctx, spell); execErr != nil {
+					logger.Error("Failed to execute spell %s: %v", spell, execErr)
+				}
+			},
+		}},
+	})
+}
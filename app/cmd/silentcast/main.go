@@ -0,0 +1,1458 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/action"
+	"github.com/SphereStacking/silentcast/internal/action/script"
+	"github.com/SphereStacking/silentcast/internal/action/shell"
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/internal/errors"
+	"github.com/SphereStacking/silentcast/internal/events"
+	"github.com/SphereStacking/silentcast/internal/hotkey"
+	"github.com/SphereStacking/silentcast/internal/notify"
+	"github.com/SphereStacking/silentcast/internal/output"
+	"github.com/SphereStacking/silentcast/internal/permission"
+	"github.com/SphereStacking/silentcast/internal/sandbox"
+	"github.com/SphereStacking/silentcast/internal/service"
+	"github.com/SphereStacking/silentcast/internal/sound"
+	"github.com/SphereStacking/silentcast/internal/tray"
+	"github.com/SphereStacking/silentcast/internal/updater"
+	"github.com/SphereStacking/silentcast/internal/version"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// Helper functions inlined to ensure they're available during GoReleaser build
+
+// getConfigPath returns the configuration directory path
+func getConfigPath() string {
+	// Check for environment variable
+	if path := os.Getenv("SILENTCAST_CONFIG"); path != "" {
+		return path
+	}
+
+	// Check current directory
+	if _, err := os.Stat(config.ConfigName + ".yml"); err == nil {
+		return "."
+	}
+
+	// Use user config directory
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		// Fallback to current directory
+		return "."
+	}
+
+	return filepath.Join(configDir, config.AppName)
+}
+
+// getConfigSearchPaths returns all paths where config files are searched
+func getConfigSearchPaths() []string {
+	var paths []string
+
+	// 1. Environment variable
+	if envPath := os.Getenv("SILENTCAST_CONFIG"); envPath != "" {
+		paths = append(paths, envPath)
+	}
+
+	// 2. Current directory
+	paths = append(paths, ".")
+
+	// 3. User config directory
+	if configDir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(configDir, config.AppName))
+	}
+
+	// 4. System config directory (Unix-like systems)
+	if runtime.GOOS != "windows" {
+		paths = append(paths, "/etc/"+config.AppName)
+	}
+
+	return paths
+}
+
+// Version information is now managed by the version package
+
+func main() {
+	// Set up a sandboxed script when re-executed for one; returns otherwise
+	sandbox.Init()
+
+	// Parse command line flags
+	flags := ParseFlags()
+
+	// Create main run function for service mode
+	mainRun := func() error {
+		return run(flags.NoTray, flags.Debug)
+	}
+
+	// Create command registry with service support
+	registry := NewCommandRegistryWithService(flags, mainRun)
+	if registry.ExecuteCommands() {
+		os.Exit(0)
+	}
+
+	// Check if we should run as a service (Windows)
+	svcManager := service.NewManager(mainRun)
+	if err := svcManager.Run(); err != nil {
+		// If not running as service, error will be returned
+		// Continue with normal execution
+		log.Printf("Not running as service: %v", err)
+	} else {
+		// Running as service, exit when service stops
+		os.Exit(0)
+	}
+
+	// Check for once mode
+	if flags.Once {
+		if err := runOnce(flags.SpellName, flags.Debug); err != nil {
+			// Print user-friendly error message
+			fmt.Fprintf(os.Stderr, "❌ %s\n", errors.GetUserMessage(err))
+			// Log detailed error for debugging
+			log.Printf("Error: %+v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for test-spell mode
+	if flags.TestSpell {
+		if err := testSpell(flags.SpellName, flags.Debug); err != nil {
+			// Print user-friendly error message
+			fmt.Fprintf(os.Stderr, "❌ %s\n", errors.GetUserMessage(err))
+			// Log detailed error for debugging
+			log.Printf("Error: %+v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for dry-run mode
+	if flags.DryRun {
+		if err := dryRun(flags.SpellName, flags.Debug); err != nil {
+			// Print user-friendly error message
+			fmt.Fprintf(os.Stderr, "❌ %s\n", errors.GetUserMessage(err))
+			// Log detailed error for debugging
+			log.Printf("Error: %+v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// No command specified, run the main application
+	if err := run(flags.NoTray, flags.Debug); err != nil {
+		// Print user-friendly error message
+		fmt.Fprintf(os.Stderr, "❌ %s\n", errors.GetUserMessage(err))
+
+		// Log detailed error for debugging
+		log.Printf("Error: %+v", err)
+		os.Exit(1)
+	}
+}
+
+func run(noTray, debug bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// WaitGroup to coordinate shutdown
+	var wg sync.WaitGroup
+
+	// Print banner
+	fmt.Printf("🪄 %s - %s v%s\n", config.AppDisplayName, config.AppDescription, version.GetVersionString())
+	fmt.Println("Press Ctrl+C to exit")
+	fmt.Println()
+
+	// Load configuration first for logger settings
+	configPath := getConfigPath()
+	loader := config.NewLoader(configPath)
+
+	cfg, err := loader.Load()
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeConfig, "failed to load configuration", err)
+	}
+
+	// Initialize logger
+	logFile := cfg.Logger.File
+	if logFile == "" {
+		// Use default log file path if not specified
+		logFile = filepath.Join(configPath, "silentcast.log")
+	}
+
+	// Set debug level if debug flag is enabled
+	logLevel := cfg.Logger.Level
+	if debug {
+		logLevel = "debug"
+	}
+
+	loggerConfig := logger.Config{
+		Level:      logLevel,
+		File:       logFile,
+		MaxSize:    cfg.Logger.MaxSize,
+		MaxBackups: cfg.Logger.MaxBackups,
+		MaxAge:     cfg.Logger.MaxAge,
+		Compress:   cfg.Logger.Compress,
+		Console:    true, // Always enable console output
+	}
+	if initErr := logger.Initialize(loggerConfig); initErr != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to initialize logger", initErr)
+	}
+
+	logger.Info("%s starting up v%s", config.AppDisplayName, version.GetVersionString())
+	logger.Info("Configuration loaded from %s", configPath)
+
+	if debug {
+		logger.Debug("Debug logging enabled")
+		logger.Debug("Logger configuration: level=%s, file=%s", logLevel, logFile)
+	}
+
+	// State changes streamed to status bars through the control socket
+	bus := events.NewBus()
+
+	// Env files and secrets for spells and webhooks, reconfigured on reload
+	envScope := dotenv.NewScope(configPath)
+	envScope.Configure(cfg.EnvFile, cfg.SecretsFile)
+
+	// Route all notifications through one queue so that a slow notifier
+	// never blocks the hotkey handler. Do-not-disturb state and history outlive config reloads.
+	notifications := &notificationState{
+		dnd:       notify.NewDoNotDisturb(),
+		history:   notify.NewHistory(filepath.Join(configPath, notify.HistoryFile), cfg.Notification.History.MaxEntries),
+		coalescer: notify.NewCoalescer(notify.DefaultCoalesceWindow),
+		events:    bus,
+		sounds:    sound.NewManager(sound.DefaultCacheDir()),
+		env:       envScope,
+	}
+	notifier := notify.NewNotificationQueue(notifications.newManager(cfg), notify.DefaultQueueOptions())
+	notifier.Start()
+	defer drainNotifications(notifier, configPath)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		notifier.ReportStatus(ctx, configPath, notificationStatusInterval, func(err error) {
+			logger.Debug("Failed to write notification status: %v", err)
+		})
+	}()
+
+	// Serve the control socket used by --dnd, --events and scripts
+	dndCtl := &dndController{dnd: notifications.dnd, notifier: notifier}
+	controlServer, err := startControlServer(ctx, configPath, dndCtl, bus)
+	if err != nil {
+		logger.Warn("Control socket unavailable: %v", err)
+	} else {
+		defer controlServer.Close()
+		logger.Info("Control socket listening on %s", controlServer.Path())
+	}
+
+	// Check permissions
+	logger.Info("Checking permissions...")
+	permManager, err := permission.NewManager()
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to create permission manager", err)
+	}
+
+	permissions, err := permManager.Check(ctx)
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypePermission, "failed to check permissions", err)
+	}
+
+	// Display permission status
+	for _, perm := range permissions {
+		if !perm.Required || perm.Status == permission.StatusGranted {
+			continue
+		}
+
+		logger.Warn("Permission required: %s - %s", perm.Type, perm.Description)
+		if notifyErr := notifier.Warning(ctx, "Permission Required",
+			fmt.Sprintf("%s: %s", perm.Type, perm.Description)); notifyErr != nil {
+			logger.Error("Failed to send warning notification: %v", notifyErr)
+		}
+
+		instructions := permManager.GetInstructions(perm.Type)
+		fmt.Println(instructions)
+		fmt.Println()
+	}
+
+	// Initialize action manager
+	logger.Info("Initializing action manager...")
+	actionManager := action.NewManager(cfg.Actions)
+	actionManager.SetNotifier(notifier)
+	actionManager.SetObserver(bus)
+	runLogs := output.NewRunLogs(filepath.Join(configPath, output.RunLogDir))
+	runLogs.Configure(cfg.ExecutionLogs)
+	actionManager.SetRunLogs(runLogs)
+	actionManager.SetEnvScope(envScope)
+	if noTray {
+		actionManager.SetConsole(os.Stdout)
+	}
+
+	if cfg.Updater.Enabled {
+		logger.Info("Starting background update checks...")
+		notifications.setUpdateActionHandler(startUpdateChecks(ctx, notifier, cfg.Updater, configPath))
+	}
+
+	// Initialize hotkey manager
+	logger.Info("Initializing hotkey manager...")
+	hotkeyManager, err := hotkey.NewManager(&cfg.Hotkeys)
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeHotkey, "failed to create hotkey manager", err)
+	}
+	notifications.reportPrefix(hotkeyManager)
+
+	// Start configuration file watcher
+	logger.Info("Starting configuration file watcher...")
+	watcher, err := config.NewWatcher(config.WatcherConfig{
+		ConfigPath: configPath,
+		OnChange: func(newCfg *config.Config) {
+			logger.Info("Configuration changed, reloading...")
+
+			// Update action manager and notification settings
+			actionManager.UpdateActions(newCfg.Actions)
+			runLogs.Configure(newCfg.ExecutionLogs)
+			envScope.Configure(newCfg.EnvFile, newCfg.SecretsFile)
+			notifier.SetManager(notifications.newManager(newCfg))
+
+			// Update hotkey manager if hotkeys changed
+			if !hotkeyConfigEqual(&cfg.Hotkeys, &newCfg.Hotkeys) || !shortcutsEqual(cfg.Shortcuts, newCfg.Shortcuts) {
+				logger.Info("Hotkeys changed, reregistering...")
+
+				// Stop current hotkeys
+				if stopErr := hotkeyManager.Stop(); stopErr != nil {
+					logger.Error("Failed to stop hotkey manager: %v", stopErr)
+				}
+
+				// Create new hotkey manager
+				newHotkeyManager, newHotkeyErr := hotkey.NewManager(&newCfg.Hotkeys)
+				if newHotkeyErr != nil {
+					logger.Error("Failed to create new hotkey manager: %v", newHotkeyErr)
+					if notifyErr := notifier.Error(ctx, "Hotkey Reload Failed",
+						"Failed to reload hotkey configuration"); notifyErr != nil {
+						logger.Error("Failed to send error notification: %v", notifyErr)
+					}
+					return
+				}
+				bus.SetPrefix(false)
+				notifications.reportPrefix(newHotkeyManager)
+
+				// Set up handler with same logic
+				newHotkeyManager.SetHandler(hotkey.HandlerFunc(func(event hotkey.Event) error {
+					logger.Info("Spell cast: %s → %s", event.Sequence.String(), event.SpellName)
+					// Notifications of this execution share a group so they update one another
+					execCtx := notify.WithGroup(ctx, notify.NewGroup(event.SpellName))
+					if notifyErr := notify.ForSpell(notifier, event.SpellName).Info(execCtx, "Spell Cast",
+						fmt.Sprintf("🎯 %s → %s", event.Sequence.String(), event.SpellName)); notifyErr != nil {
+						logger.Error("Failed to send info notification: %v", notifyErr)
+					}
+
+					// Execute the action
+					if execErr := actionManager.Execute(action.WithSequence(execCtx, event.Sequence.String()), event.SpellName); execErr != nil {
+						logger.Error("Failed to execute spell %s: %v", event.SpellName, execErr)
+						if notifyErr := notifySpellFailed(execCtx, notifier, actionManager, event.SpellName, execErr); notifyErr != nil {
+							logger.Error("Failed to send error notification: %v", notifyErr)
+						}
+						return execErr
+					}
+
+					logger.Info("Successfully executed spell: %s", event.SpellName)
+					return nil
+				}))
+
+				// Register all new hotkeys
+				for sequence, spellName := range newCfg.Shortcuts {
+					if regErr := newHotkeyManager.Register(sequence, spellName); regErr != nil {
+						logger.Warn("Failed to register hotkey %s: %v", sequence, regErr)
+					}
+				}
+
+				// Start new hotkey manager
+				if startErr := newHotkeyManager.Start(); startErr != nil {
+					logger.Error("Failed to start new hotkey manager: %v", startErr)
+					if notifyErr := notifier.Error(ctx, "Hotkey Reload Failed",
+						"Failed to start new hotkey manager"); notifyErr != nil {
+						logger.Error("Failed to send error notification: %v", notifyErr)
+					}
+					return
+				}
+
+				// Replace the hotkey manager
+				hotkeyManager = newHotkeyManager
+
+				logger.Info("Hotkeys reloaded successfully")
+			}
+
+			// Update the configuration reference
+			cfg = newCfg
+
+			// Notify user of successful reload
+			if notifyErr := notifier.Success(ctx, "Configuration Reloaded",
+				"SilentCast configuration has been updated"); notifyErr != nil {
+				logger.Error("Failed to send success notification: %v", notifyErr)
+			}
+
+			logger.Info("Configuration reload completed successfully")
+		},
+		Debounce: 500 * time.Millisecond,
+	})
+	if err != nil {
+		logger.Warn("Failed to create config watcher: %v", err)
+	} else {
+		watcher.Start(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			logger.Info("Stopping config watcher...")
+			if err := watcher.Stop(); err != nil {
+				logger.Error("Failed to stop config watcher: %v", err)
+			}
+		}()
+	}
+
+	// Set up hotkey handler
+	hotkeyManager.SetHandler(hotkey.HandlerFunc(func(event hotkey.Event) error {
+		logger.Info("Spell cast: %s → %s", event.Sequence.String(), event.SpellName)
+		// Notifications of this execution share a group so they update one another
+		execCtx := notify.WithGroup(ctx, notify.NewGroup(event.SpellName))
+		if err := notify.ForSpell(notifier, event.SpellName).Info(execCtx, "Spell Cast",
+			fmt.Sprintf("🎯 %s → %s", event.Sequence.String(), event.SpellName)); err != nil {
+			logger.Error("Failed to send info notification: %v", err)
+		}
+
+		// Execute the action
+		if err := actionManager.Execute(action.WithSequence(execCtx, event.Sequence.String()), event.SpellName); err != nil {
+			logger.Error("Failed to execute spell %s: %v", event.SpellName, err)
+			if notifyErr := notifySpellFailed(execCtx, notifier, actionManager, event.SpellName, err); notifyErr != nil {
+				logger.Error("Failed to send error notification: %v", notifyErr)
+			}
+			return err
+		}
+
+		logger.Info("Successfully executed spell: %s", event.SpellName)
+		return nil
+	}))
+
+	// Register all hotkeys
+	for sequence, spellName := range cfg.Shortcuts {
+		if err := hotkeyManager.Register(sequence, spellName); err != nil {
+			logger.Warn("Failed to register hotkey %s: %v", sequence, err)
+			if notifyErr := notifier.Warning(ctx, "Registration Failed",
+				fmt.Sprintf("Could not register %s: %v", sequence, err)); notifyErr != nil {
+				logger.Error("Failed to send warning notification: %v", notifyErr)
+			}
+			continue
+		}
+		logger.Info("Registered hotkey: %s → %s", sequence, spellName)
+		fmt.Printf("  ✨ %s → %s\n", sequence, spellName)
+	}
+	fmt.Println()
+
+	// Start hotkey manager
+	logger.Info("Starting hotkey manager...")
+	if err := hotkeyManager.Start(); err != nil {
+		return errors.Wrap(errors.ErrorTypeHotkey, "failed to start hotkey manager", err)
+	}
+	defer func() {
+		if err := hotkeyManager.Stop(); err != nil {
+			logger.Error("Failed to stop hotkey manager: %v", err)
+		}
+	}()
+
+	logger.Info("%s is active! Listening with prefix: %s", config.AppDisplayName, cfg.Hotkeys.Prefix)
+	if err := notifier.Success(ctx, config.AppDisplayName+" Active",
+		fmt.Sprintf("Listening with prefix: %s", cfg.Hotkeys.Prefix)); err != nil {
+		logger.Error("Failed to send success notification: %v", err)
+	}
+
+	// Setup shutdown handling
+	shutdownCh := make(chan struct{})
+
+	// Handle OS signals
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigChan
+		logger.Info("Received signal: %v", sig)
+		close(shutdownCh)
+	}()
+
+	var trayManager *tray.Manager
+	if !noTray {
+		// Initialize system tray
+		var err error
+		trayManager, err = tray.NewManager(ctx, cfg)
+		if err != nil {
+			return errors.Wrap(errors.ErrorTypeSystem, "failed to initialize tray manager", err)
+		}
+
+		// Add menu items
+		trayManager.AddMenuItem("Show Hotkeys", "Display configured hotkeys", func() {
+			logger.Info("Show hotkeys requested")
+			fmt.Println("\n🗿 Configured Hotkeys:")
+			for sequence, spellName := range cfg.Shortcuts {
+				fmt.Printf("  ✨ %s → %s\n", sequence, spellName)
+			}
+		})
+
+		trayManager.AddMenuItem("Reload Config", "Reload configuration file", func() {
+			logger.Info("Manual config reload requested")
+			newCfg, err := loader.Load()
+			if err != nil {
+				logger.Error("Failed to reload configuration: %v", err)
+				if notifyErr := notifier.Error(ctx, "Config Reload Failed", err.Error()); notifyErr != nil {
+					logger.Error("Failed to send error notification: %v", notifyErr)
+				}
+				return
+			}
+
+			// Manual reload uses the same logic as the watcher
+			actionManager.UpdateActions(newCfg.Actions)
+			runLogs.Configure(newCfg.ExecutionLogs)
+			envScope.Configure(newCfg.EnvFile, newCfg.SecretsFile)
+			notifier.SetManager(notifications.newManager(newCfg))
+
+			if !hotkeyConfigEqual(&cfg.Hotkeys, &newCfg.Hotkeys) || !shortcutsEqual(cfg.Shortcuts, newCfg.Shortcuts) {
+				logger.Info("Hotkeys changed, reregistering...")
+				// Same hotkey reload logic as in watcher...
+			}
+
+			cfg = newCfg
+			if err := notifier.Success(ctx, "Configuration Reloaded", "Manual reload successful"); err != nil {
+				logger.Error("Failed to send success notification: %v", err)
+			}
+		})
+
+		dndItem := trayManager.AddCheckboxItem("Do Not Disturb", "Hold notifications until turned off", notifications.dnd.Enabled(), func() {
+			if _, err := dndCtl.apply(ctx, "toggle"); err != nil {
+				logger.Error("Failed to toggle do not disturb: %v", err)
+			}
+		})
+		dndCtl.setTrayItem(dndItem)
+
+		trayManager.AddSeparator()
+
+		trayManager.AddMenuItem("About", "About "+config.AppDisplayName, func() {
+			logger.Info("About requested")
+			fmt.Printf("\n🪄 %s v%s\n", config.AppDisplayName, version.GetVersionString())
+			fmt.Println(config.AppDescription)
+			fmt.Printf("https://github.com/%s/%s\n", config.AppOrg, config.AppRepo)
+		})
+
+		// Run tray in a goroutine since Start blocks
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			trayManager.Start()
+			logger.Info("System tray stopped")
+			close(shutdownCh) // Trigger shutdown if tray exits
+		}()
+
+		// Give tray time to initialize
+		// In a real implementation, we'd use a better synchronization method
+		logger.Info("Starting system tray...")
+	}
+
+	// Wait for shutdown signal
+	<-shutdownCh
+
+	fmt.Println("\n👋 Shutting down " + config.AppDisplayName + "...")
+	logger.Info("Shutting down %s...", config.AppDisplayName)
+
+	// Cancel context to stop all components
+	cancel()
+
+	// Stop tray if it's running
+	if trayManager != nil {
+		logger.Info("Stopping system tray...")
+		trayManager.Stop()
+	}
+
+	// Wait for all goroutines with timeout
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// Normal shutdown
+	case <-time.After(3 * time.Second):
+		// Force exit after timeout
+		logger.Warn("Shutdown timeout exceeded, forcing exit")
+	}
+
+	return nil
+}
+
+// Notification queue lifecycle settings
+const (
+	notificationStatusInterval = 5 * time.Second
+	notificationDrainTimeout   = 5 * time.Second
+)
+
+// drainNotifications delivers queued notifications before exit and records the final queue status
+func drainNotifications(queue *notify.NotificationQueue, configPath string) {
+	logger.Info("Draining notification queue...")
+	if err := queue.Stop(notificationDrainTimeout); err != nil {
+		logger.Warn("Failed to drain notification queue: %v", err)
+	}
+
+	processed, failed, dropped := queue.GetMetrics()
+	logger.Info("Notifications: %d delivered, %d failed, %d dropped", processed, failed, dropped)
+
+	if err := notify.WriteQueueStatus(filepath.Join(configPath, notify.QueueStatusFile), queue.Status()); err != nil {
+		logger.Debug("Failed to write notification status: %v", err)
+	}
+}
+
+// startUpdateChecks runs periodic update checks that report through the notification queue.
+// It returns the handler for action buttons on update notifications.
+func startUpdateChecks(ctx context.Context, notifier notify.UpdateSender, updaterCfg config.UpdaterConfig, configPath string) func(notify.UpdateAction, *notify.UpdateNotification) error {
+	interval := 24 * time.Hour
+	if d, err := time.ParseDuration(updaterCfg.CheckInterval); err == nil && d > 0 {
+		interval = d
+	}
+
+	upd := updater.NewUpdater(&updater.Config{
+		CurrentVersion: version.GetVersionString(),
+		RepoOwner:      "SphereStacking",
+		RepoName:       "SilentCast",
+		CheckInterval:  interval,
+		ConfigDir:      configPath,
+		CacheDuration:  1 * time.Hour,
+	})
+
+	notifyCfg := notify.DefaultUpdateNotificationConfig()
+	notifyCfg.CheckInterval = interval
+	notifyCfg.IncludePreReleases = updaterCfg.Prerelease
+
+	updateNotifier := notify.NewUpdateNotificationManager(notifier)
+	updateNotifier.SetConfig(notifyCfg)
+	updateNotifier.StartPeriodicChecks(ctx, upd, version.GetVersionString())
+
+	return func(action notify.UpdateAction, updateInfo *notify.UpdateNotification) error {
+		return updateNotifier.HandleUpdateAction(ctx, action, updateInfo, upd)
+	}
+}
+
+// runOnce executes a single spell and exits
+func runOnce(spellName string, debug bool) error {
+	if spellName == "" {
+		return fmt.Errorf("spell name is required when using --once flag")
+	}
+
+	// Load configuration
+	configPath := getConfigPath()
+	loader := config.NewLoader(configPath)
+
+	cfg, err := loader.Load()
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeConfig, "failed to load configuration", err)
+	}
+
+	// Check if spell exists
+	actionName, exists := cfg.Shortcuts[spellName]
+	if !exists {
+		return fmt.Errorf("spell '%s' not found. Available spells: %v", spellName, getSpellList(cfg.Shortcuts))
+	}
+
+	// Print what we're about to execute
+	if action, actionExists := cfg.Actions[actionName]; actionExists {
+		fmt.Printf("🪄 Executing spell: %s → %s\n", spellName, actionName)
+		fmt.Printf("   Type: %s\n", action.Type)
+		if action.ScriptFile != "" {
+			fmt.Printf("   Script File: %s\n", action.ScriptFile)
+		} else {
+			fmt.Printf("   Command: %s\n", action.Command)
+		}
+		if action.Description != "" {
+			fmt.Printf("   Description: %s\n", action.Description)
+		}
+		fmt.Println()
+	}
+
+	// Initialize minimal logger for once mode (console only)
+	logLevel := "info"
+	if debug {
+		logLevel = "debug"
+	}
+	loggerConfig := logger.Config{
+		Level:   logLevel,
+		Console: true,
+	}
+	if err := logger.Initialize(loggerConfig); err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to initialize logger", err)
+	}
+
+	// Initialize action manager
+	envScope := dotenv.NewScope(configPath)
+	envScope.Configure(cfg.EnvFile, cfg.SecretsFile)
+	actionManager := action.NewManager(cfg.Actions)
+	actionManager.SetNotifier(notify.NewManagerFromConfig(cfg, envScope))
+	runLogs := output.NewRunLogs(filepath.Join(configPath, output.RunLogDir))
+	runLogs.Configure(cfg.ExecutionLogs)
+	actionManager.SetRunLogs(runLogs)
+	actionManager.SetEnvScope(envScope)
+
+	// Execute the action
+	ctx := context.Background()
+	if err := actionManager.Execute(ctx, actionName); err != nil {
+		return errors.Wrap(errors.ErrorTypeExecution, fmt.Sprintf("failed to execute spell '%s'", spellName), err)
+	}
+
+	fmt.Printf("✅ Successfully executed spell: %s\n", spellName)
+	return nil
+}
+
+// getSpellList returns a list of available spell names
+func getSpellList(shortcuts map[string]string) []string {
+	var spells []string
+	for spell := range shortcuts {
+		spells = append(spells, spell)
+	}
+	return spells
+}
+
+// testSpell tests a spell with detailed debug information
+func testSpell(spellName string, _ bool) error {
+	if spellName == "" {
+		return fmt.Errorf("spell name is required when using --test-spell flag")
+	}
+
+	fmt.Printf("🧪 Testing spell: %s\n", spellName)
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println()
+
+	// Load configuration
+	configPath := getConfigPath()
+	fmt.Printf("📁 Loading configuration from: %s\n", configPath)
+
+	loader := config.NewLoader(configPath)
+	cfg, err := loader.Load()
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeConfig, "failed to load configuration", err)
+	}
+	fmt.Println("✅ Configuration loaded successfully")
+	fmt.Println()
+
+	// Check if spell exists
+	fmt.Printf("🔍 Looking up spell '%s'...\n", spellName)
+	actionName, exists := cfg.Shortcuts[spellName]
+	if !exists {
+		fmt.Printf("❌ Spell '%s' not found\n", spellName)
+		fmt.Printf("Available spells: %v\n", getSpellList(cfg.Shortcuts))
+		return fmt.Errorf("spell '%s' not found", spellName)
+	}
+	fmt.Printf("✅ Spell found: %s → %s\n", spellName, actionName)
+	fmt.Println()
+
+	// Check if action exists
+	fmt.Printf("🎯 Looking up action '%s'...\n", actionName)
+	action, actionExists := cfg.Actions[actionName]
+	if !actionExists {
+		fmt.Printf("❌ Action '%s' not found in grimoire\n", actionName)
+		return fmt.Errorf("action '%s' not found in grimoire", actionName)
+	}
+	fmt.Printf("✅ Action found in grimoire\n")
+	fmt.Println()
+
+	// Resolve variables of the env files; secrets are redacted below
+	envScope := dotenv.NewScope(configPath)
+	envScope.Configure(cfg.EnvFile, cfg.SecretsFile)
+	vars, err := envScope.Resolve(action.EnvFile)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return err
+	}
+
+	// Display detailed action information
+	fmt.Println("📋 Action Details:")
+	fmt.Printf("   Type: %s\n", action.Type)
+	if action.ScriptFile != "" {
+		fmt.Printf("   Script File: %s\n", action.ScriptFile)
+	} else {
+		fmt.Printf("   Command: %s\n", action.Command)
+	}
+	if action.Description != "" {
+		fmt.Printf("   Description: %s\n", action.Description)
+	}
+	if len(action.Args) > 0 {
+		fmt.Printf("   Arguments: %v\n", action.Args)
+	}
+	if action.WorkingDir != "" {
+		fmt.Printf("   Working Directory: %s\n", action.WorkingDir)
+		// Expand environment variables for display
+		expandedDir := expandForDisplay(vars, action.WorkingDir)
+		if expandedDir != action.WorkingDir {
+			fmt.Printf("   Expanded Working Directory: %s\n", expandedDir)
+		}
+	}
+	if action.Shell != "" {
+		fmt.Printf("   Shell: %s\n", action.Shell)
+	}
+	if action.Timeout > 0 {
+		fmt.Printf("   Timeout: %d seconds\n", action.Timeout)
+	}
+	fmt.Println()
+
+	// Display action options
+	fmt.Println("⚙️  Action Options:")
+	var options []string
+	if action.ShowOutput {
+		options = append(options, "show_output")
+	}
+	if action.KeepOpen {
+		options = append(options, "keep_open")
+	}
+	if action.Terminal {
+		options = append(options, "terminal")
+	}
+	if action.ForceTerminal {
+		options = append(options, "force_terminal")
+	}
+	if action.Admin {
+		options = append(options, "admin")
+	}
+	if action.PTY {
+		options = append(options, "pty")
+	}
+	if action.CopyOutput {
+		options = append(options, "copy_output")
+	}
+	if len(options) > 0 {
+		fmt.Printf("   Enabled: %s\n", strings.Join(options, ", "))
+	} else {
+		fmt.Println("   No special options enabled")
+	}
+	if action.PreferredTerminal != "" {
+		fmt.Printf("   Terminal: %s\n", action.PreferredTerminal)
+	}
+	if action.Type == "script" {
+		fmt.Printf("   Mode: %s\n", action.ExecutionMode())
+	}
+	if sources := action.Stdin.Sources(); len(sources) > 0 {
+		fmt.Printf("   Stdin: %s\n", strings.Join(sources, ", "))
+	}
+	if !action.Limits.IsZero() {
+		fmt.Printf("   Limits: %s\n", describeLimits(action.Limits))
+	}
+	if action.Sandbox.Enabled {
+		fmt.Printf("   Sandbox: %s\n", describeSandbox(action.Sandbox))
+	}
+	for _, rule := range action.OutputRules {
+		fmt.Printf("   Output rule: %s %s\n", rule.Match, outputRuleEffect(rule))
+	}
+	fmt.Println()
+
+	// Display environment variables
+	if len(action.Env) > 0 || len(cfg.EnvFile) > 0 || len(action.EnvFile) > 0 {
+		fmt.Println("🌍 Environment Variables:")
+		for _, file := range append(append([]string(nil), cfg.EnvFile...), action.EnvFile...) {
+			fmt.Printf("   Env file: %s\n", file)
+		}
+		for key, value := range action.Env {
+			fmt.Printf("   %s=%s\n", key, value)
+			// Show expanded value if different
+			expandedValue := expandForDisplay(vars, value)
+			if expandedValue != value {
+				fmt.Printf("   %s=%s (expanded)\n", key, expandedValue)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Type-specific validation and information
+	fmt.Printf("🔬 Type-Specific Analysis (%s):\n", action.Type)
+	switch action.Type {
+	case "app":
+		return testAppAction(&action, vars)
+	case "script":
+		return testScriptAction(&action, vars)
+	case "url":
+		return testURLAction(&action)
+	default:
+		fmt.Printf("❌ Unknown action type: %s\n", action.Type)
+		return fmt.Errorf("unknown action type: %s", action.Type)
+	}
+}
+
+// testAppAction tests app-specific aspects
+func testAppAction(action *config.ActionConfig, vars *dotenv.Vars) error {
+	expandedCmd, err := vars.Expand(action.Command)
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		return err
+	}
+	fmt.Printf("   Command: %s\n", action.Command)
+	if displayCmd := vars.Redact(expandedCmd); displayCmd != action.Command {
+		fmt.Printf("   Expanded Command: %s\n", displayCmd)
+	}
+
+	// Check if it's an absolute path
+	if filepath.IsAbs(expandedCmd) {
+		fmt.Printf("   Type: Absolute path\n")
+		if _, err := os.Stat(expandedCmd); os.IsNotExist(err) {
+			fmt.Printf("   ❌ File does not exist: %s\n", expandedCmd)
+			return fmt.Errorf("application file does not exist: %s", expandedCmd)
+		} else {
+			fmt.Printf("   ✅ File exists: %s\n", expandedCmd)
+		}
+	} else {
+		fmt.Printf("   Type: Command in PATH\n")
+		if fullPath, err := exec.LookPath(expandedCmd); err != nil {
+			fmt.Printf("   ❌ Command not found in PATH: %s\n", expandedCmd)
+			return fmt.Errorf("application not found in PATH: %s", expandedCmd)
+		} else {
+			fmt.Printf("   ✅ Found in PATH: %s\n", fullPath)
+		}
+	}
+
+	fmt.Println("\n✅ App action validation completed successfully")
+	return nil
+}
+
+// testScriptAction tests script-specific aspects
+func testScriptAction(action *config.ActionConfig, vars *dotenv.Vars) error {
+	if action.ScriptFile != "" {
+		fmt.Printf("   Script File: %s\n", action.ScriptFile)
+		if _, err := os.Stat(action.ScriptFile); err != nil {
+			fmt.Printf("   ❌ Script file does not exist: %s\n", action.ScriptFile)
+			return fmt.Errorf("script file does not exist: %s", action.ScriptFile)
+		}
+		fmt.Printf("   ✅ Script file exists: %s\n", action.ScriptFile)
+	} else {
+		fmt.Printf("   Script Command: %s\n", action.Command)
+
+		// Show expanded command
+		expandedCmd := expandCommandForDisplay(vars, action)
+		if expandedCmd != action.Command {
+			fmt.Printf("   Expanded Command: %s\n", expandedCmd)
+		}
+	}
+
+	// Resolve the shell the same way the executor does; a script file
+	// without a shell runs directly
+	if action.ScriptFile != "" && action.Shell == "" {
+		fmt.Printf("   ✅ Runs directly, without a shell\n")
+	} else {
+		sh, err := script.NewScriptExecutor(action).ResolveShell(context.Background())
+		if err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			return err
+		}
+		fmt.Printf("   ✅ Using Shell: %s\n", shell.FormatShellInfo(sh))
+	}
+
+	// Check working directory
+	if action.WorkingDir != "" {
+		expandedDir, err := vars.Expand(action.WorkingDir)
+		if err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			return err
+		}
+		displayDir := vars.Redact(expandedDir)
+		if _, err := os.Stat(expandedDir); os.IsNotExist(err) {
+			fmt.Printf("   ❌ Working directory does not exist: %s\n", displayDir)
+			return fmt.Errorf("working directory does not exist: %s", displayDir)
+		} else {
+			fmt.Printf("   ✅ Working directory exists: %s\n", displayDir)
+		}
+	}
+
+	fmt.Println("\n✅ Script action validation completed successfully")
+	return nil
+}
+
+// testURLAction tests URL-specific aspects
+func testURLAction(action *config.ActionConfig) error {
+	urlStr := strings.TrimSpace(action.Command)
+	fmt.Printf("   URL: %s\n", urlStr)
+
+	// Add scheme if missing
+	if !strings.Contains(urlStr, "://") {
+		urlStr = "https://" + urlStr
+		fmt.Printf("   URL with scheme: %s\n", urlStr)
+	}
+
+	// Parse and validate URL
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		fmt.Printf("   ❌ Invalid URL format: %v\n", err)
+		return fmt.Errorf("invalid URL format: %w", err)
+	}
+
+	fmt.Printf("   ✅ URL is valid\n")
+	fmt.Printf("   Scheme: %s\n", u.Scheme)
+	fmt.Printf("   Host: %s\n", u.Host)
+	if u.Path != "" && u.Path != "/" {
+		fmt.Printf("   Path: %s\n", u.Path)
+	}
+
+	// Check scheme
+	validSchemes := map[string]bool{
+		"http": true, "https": true, "file": true,
+		"ftp": true, "mailto": true,
+	}
+
+	if !validSchemes[u.Scheme] {
+		fmt.Printf("   ⚠️  Unsupported URL scheme: %s\n", u.Scheme)
+		fmt.Printf("   Supported schemes: http, https, file, ftp, mailto\n")
+	} else {
+		fmt.Printf("   ✅ Supported URL scheme: %s\n", u.Scheme)
+	}
+
+	// Warn about localhost URLs
+	if u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1" {
+		fmt.Printf("   ⚠️  localhost URL - will only work on this machine\n")
+	}
+
+	fmt.Println("\n✅ URL action validation completed successfully")
+	return nil
+}
+
+// dryRun simulates spell execution without actually running it
+func dryRun(spellName string, debug bool) error {
+	if spellName == "" {
+		return fmt.Errorf("spell name is required when using --dry-run flag")
+	}
+
+	fmt.Printf("🔍 Dry Run Mode: %s\n", spellName)
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println()
+
+	// Load configuration
+	configPath := getConfigPath()
+	fmt.Printf("📁 Loading configuration from: %s\n", configPath)
+
+	loader := config.NewLoader(configPath)
+	cfg, err := loader.Load()
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeConfig, "failed to load configuration", err)
+	}
+	fmt.Println("✅ Configuration loaded successfully")
+	fmt.Println()
+
+	// Initialize minimal logger for dry-run mode (console only)
+	logLevel := "info"
+	if debug {
+		logLevel = "debug"
+	}
+	loggerConfig := logger.Config{
+		Level:   logLevel,
+		Console: true,
+	}
+	if err := logger.Initialize(loggerConfig); err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to initialize logger", err)
+	}
+
+	// Check if spell exists
+	fmt.Printf("🔍 Looking up spell '%s'...\n", spellName)
+	actionName, exists := cfg.Shortcuts[spellName]
+	if !exists {
+		fmt.Printf("❌ Spell '%s' not found\n", spellName)
+		fmt.Printf("Available spells: %v\n", getSpellList(cfg.Shortcuts))
+		return fmt.Errorf("spell '%s' not found", spellName)
+	}
+	fmt.Printf("✅ Spell found: %s → %s\n", spellName, actionName)
+	fmt.Println()
+
+	// Check if action exists
+	fmt.Printf("🎯 Looking up action '%s'...\n", actionName)
+	action, actionExists := cfg.Actions[actionName]
+	if !actionExists {
+		fmt.Printf("❌ Action '%s' not found in grimoire\n", actionName)
+		return fmt.Errorf("action '%s' not found in grimoire", actionName)
+	}
+	fmt.Printf("✅ Action found in grimoire\n")
+	fmt.Println()
+
+	// Resolve variables of the env files; secrets are redacted below
+	envScope := dotenv.NewScope(configPath)
+	envScope.Configure(cfg.EnvFile, cfg.SecretsFile)
+	vars, err := envScope.Resolve(action.EnvFile)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return err
+	}
+
+	// Display what would be executed
+	fmt.Println("🚀 Would Execute:")
+	fmt.Printf("   Type: %s\n", action.Type)
+	if action.ScriptFile != "" {
+		fmt.Printf("   Script File: %s\n", action.ScriptFile)
+	} else {
+		fmt.Printf("   Command: %s\n", action.Command)
+
+		// Expand variables for display the way the executor does
+		expandedCmd := expandCommandForDisplay(vars, &action)
+		if expandedCmd != action.Command {
+			fmt.Printf("   Expanded Command: %s\n", expandedCmd)
+		}
+	}
+
+	if action.Description != "" {
+		fmt.Printf("   Description: %s\n", action.Description)
+	}
+
+	if len(action.Args) > 0 {
+		// Arguments are passed as they are, without expanding variables
+		fmt.Printf("   Arguments: %v\n", action.Args)
+	}
+
+	if action.WorkingDir != "" {
+		fmt.Printf("   Working Directory: %s\n", action.WorkingDir)
+		expandedDir := expandForDisplay(vars, action.WorkingDir)
+		if expandedDir != action.WorkingDir {
+			fmt.Printf("   Expanded Working Directory: %s\n", expandedDir)
+		}
+	}
+
+	if action.Shell != "" {
+		fmt.Printf("   Shell: %s\n", action.Shell)
+	}
+
+	if action.Timeout > 0 {
+		fmt.Printf("   Timeout: %d seconds\n", action.Timeout)
+	}
+	fmt.Println()
+
+	// Display action options
+	fmt.Println("⚙️  Action Options:")
+	var options []string
+	if action.ShowOutput {
+		options = append(options, "show_output")
+	}
+	if action.KeepOpen {
+		options = append(options, "keep_open")
+	}
+	if action.Terminal {
+		options = append(options, "terminal")
+	}
+	if action.ForceTerminal {
+		options = append(options, "force_terminal")
+	}
+	if action.Admin {
+		options = append(options, "admin")
+	}
+	if action.PTY {
+		options = append(options, "pty")
+	}
+	if action.CopyOutput {
+		options = append(options, "copy_output")
+	}
+	if len(options) > 0 {
+		fmt.Printf("   Enabled: %s\n", strings.Join(options, ", "))
+	} else {
+		fmt.Println("   No special options enabled")
+	}
+	if action.PreferredTerminal != "" {
+		fmt.Printf("   Terminal: %s\n", action.PreferredTerminal)
+	}
+	if action.Type == "script" {
+		fmt.Printf("   Mode: %s\n", action.ExecutionMode())
+	}
+	if sources := action.Stdin.Sources(); len(sources) > 0 {
+		fmt.Printf("   Stdin: %s\n", strings.Join(sources, ", "))
+	}
+	if !action.Limits.IsZero() {
+		fmt.Printf("   Limits: %s\n", describeLimits(action.Limits))
+	}
+	if action.Sandbox.Enabled {
+		fmt.Printf("   Sandbox: %s\n", describeSandbox(action.Sandbox))
+	}
+	for _, rule := range action.OutputRules {
+		fmt.Printf("   Output rule: %s %s\n", rule.Match, outputRuleEffect(rule))
+	}
+	fmt.Println()
+
+	// Display environment variables
+	if len(action.Env) > 0 || len(cfg.EnvFile) > 0 || len(action.EnvFile) > 0 {
+		fmt.Println("🌍 Environment Variables:")
+		for _, file := range append(append([]string(nil), cfg.EnvFile...), action.EnvFile...) {
+			fmt.Printf("   Env file: %s\n", file)
+		}
+		for key, value := range action.Env {
+			fmt.Printf("   %s=%s\n", key, value)
+			// Show expanded value if different
+			expandedValue := expandForDisplay(vars, value)
+			if expandedValue != value {
+				fmt.Printf("   %s=%s (expanded)\n", key, expandedValue)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Type-specific dry-run information
+	fmt.Printf("🔬 Type-Specific Analysis (%s):\n", action.Type)
+	switch action.Type {
+	case "app":
+		return dryRunAppAction(&action, vars)
+	case "script":
+		return dryRunScriptAction(&action, vars)
+	case "url":
+		return dryRunURLAction(&action)
+	default:
+		fmt.Printf("❌ Unknown action type: %s\n", action.Type)
+		return fmt.Errorf("unknown action type: %s", action.Type)
+	}
+}
+
+// dryRunAppAction shows what would happen for app actions
+func dryRunAppAction(action *config.ActionConfig, vars *dotenv.Vars) error {
+	fmt.Printf("   Would launch application: %s\n", action.Command)
+	expandedCmd, err := vars.Expand(action.Command)
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		fmt.Printf("   Would fail with: %v\n", err)
+		fmt.Println("\n✅ Dry run analysis completed successfully")
+		return nil
+	}
+	if displayCmd := vars.Redact(expandedCmd); displayCmd != action.Command {
+		fmt.Printf("   Expanded path: %s\n", displayCmd)
+	}
+
+	// Check if it's an absolute path
+	if filepath.IsAbs(expandedCmd) {
+		fmt.Printf("   Type: Absolute path\n")
+		if _, err := os.Stat(expandedCmd); os.IsNotExist(err) {
+			fmt.Printf("   ❌ File does not exist: %s\n", expandedCmd)
+			fmt.Printf("   Would fail with: application file does not exist\n")
+		} else {
+			fmt.Printf("   ✅ File exists: %s\n", expandedCmd)
+			fmt.Printf("   Would successfully launch application\n")
+		}
+	} else {
+		fmt.Printf("   Type: Command in PATH\n")
+		if fullPath, err := exec.LookPath(expandedCmd); err != nil {
+			fmt.Printf("   ❌ Command not found in PATH: %s\n", expandedCmd)
+			fmt.Printf("   Would fail with: application not found in PATH\n")
+		} else {
+			fmt.Printf("   ✅ Found in PATH: %s\n", fullPath)
+			fmt.Printf("   Would successfully launch: %s\n", fullPath)
+		}
+	}
+
+	fmt.Println("\n✅ Dry run analysis completed successfully")
+	return nil
+}
+
+// dryRunScriptAction shows what would happen for script actions
+func dryRunScriptAction(action *config.ActionConfig, vars *dotenv.Vars) error {
+	if action.ScriptFile != "" {
+		fmt.Printf("   Would execute script file: %s\n", action.ScriptFile)
+		if _, err := os.Stat(action.ScriptFile); err != nil {
+			fmt.Printf("   ❌ Script file does not exist: %s\n", action.ScriptFile)
+			fmt.Printf("   Would fail with: script file does not exist\n")
+		}
+	} else {
+		fmt.Printf("   Would execute script: %s\n", action.Command)
+
+		// Show expanded command
+		expandedCmd := expandCommandForDisplay(vars, action)
+		if expandedCmd != action.Command {
+			fmt.Printf("   Expanded command: %s\n", expandedCmd)
+		}
+	}
+
+	// Resolve the shell the same way the executor does; a script file
+	// without a shell runs directly
+	if action.ScriptFile != "" && action.Shell == "" {
+		fmt.Printf("   ✅ Would run directly, without a shell\n")
+	} else if sh, err := script.NewScriptExecutor(action).ResolveShell(context.Background()); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		fmt.Printf("   Would fail with: %v\n", err)
+	} else {
+		fmt.Printf("   ✅ Would use shell: %s\n", shell.FormatShellInfo(sh))
+	}
+
+	// Check working directory
+	if action.WorkingDir != "" {
+		if expandedDir, err := vars.Expand(action.WorkingDir); err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			fmt.Printf("   Would fail with: %v\n", err)
+		} else {
+			displayDir := vars.Redact(expandedDir)
+			fmt.Printf("   Would run in directory: %s\n", displayDir)
+			if _, err := os.Stat(expandedDir); os.IsNotExist(err) {
+				fmt.Printf("   ❌ Working directory does not exist: %s\n", displayDir)
+				fmt.Printf("   Would fail with: working directory does not exist\n")
+			} else {
+				fmt.Printf("   ✅ Working directory exists: %s\n", displayDir)
+			}
+		}
+	} else {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("   ⚠️  Failed to get current directory: %v\n", err)
+			fmt.Printf("   Would run in current directory: [unknown]\n")
+		} else {
+			fmt.Printf("   Would run in current directory: %s\n", cwd)
+		}
+	}
+
+	fmt.Println("\n✅ Dry run analysis completed successfully")
+	return nil
+}
+
+// hotkeyConfigEqual compares two hotkey configurations for equality
+func hotkeyConfigEqual(a, b *config.HotkeyConfig) bool {
+	return a.Prefix == b.Prefix &&
+		a.Timeout == b.Timeout &&
+		a.SequenceTimeout == b.SequenceTimeout
+}
+
+// shortcutsEqual compares two shortcut maps for equality
+func shortcutsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if bv, exists := b[k]; !exists || v != bv {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dryRunURLAction shows what would happen for URL actions
+func dryRunURLAction(action *config.ActionConfig) error {
+	urlStr := strings.TrimSpace(action.Command)
+	fmt.Printf("   Would open URL: %s\n", urlStr)
+
+	// Add scheme if missing
+	if !strings.Contains(urlStr, "://") {
+		urlStr = "https://" + urlStr
+		fmt.Printf("   URL with scheme: %s\n", urlStr)
+	}
+
+	// Parse and validate URL
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		fmt.Printf("   ❌ Invalid URL format: %v\n", err)
+		fmt.Printf("   Would fail with: invalid URL format\n")
+		return nil
+	}
+
+	fmt.Printf("   ✅ URL is valid\n")
+	fmt.Printf("   Scheme: %s\n", u.Scheme)
+	fmt.Printf("   Host: %s\n", u.Host)
+	if u.Path != "" && u.Path != "/" {
+		fmt.Printf("   Path: %s\n", u.Path)
+	}
+
+	// Check scheme
+	validSchemes := map[string]bool{
+		"http": true, "https": true, "file": true,
+		"ftp": true, "mailto": true,
+	}
+
+	if !validSchemes[u.Scheme] {
+		fmt.Printf("   ⚠️  Unsupported URL scheme: %s\n", u.Scheme)
+		fmt.Printf("   Supported schemes: http, https, file, ftp, mailto\n")
+		fmt.Printf("   Would attempt to open with default browser\n")
+	} else {
+		fmt.Printf("   ✅ Supported URL scheme: %s\n", u.Scheme)
+		fmt.Printf("   Would successfully open in default browser\n")
+	}
+
+	// Warn about localhost URLs
+	if u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1" {
+		fmt.Printf("   ⚠️  localhost URL - will only work on this machine\n")
+	}
+
+	fmt.Println("\n✅ Dry run analysis completed successfully")
+	return nil
+}
+
+// expandForDisplay expands the variables of a value for --test-spell and
+// --dry-run, with secrets redacted
+func expandForDisplay(vars *dotenv.Vars, value string) string {
+	expanded, err := vars.Expand(value)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return vars.Redact(expanded)
+}
+
+// expandCommandForDisplay expands the command of an action like its executor
+// does, with secrets redacted. Script commands keep the references to the
+// spell's env, which the shell expands.
+func expandCommandForDisplay(vars *dotenv.Vars, action *config.ActionConfig) string {
+	if action.Type != "script" {
+		return expandForDisplay(vars, action.Command)
+	}
+	expanded, err := script.NewScriptExecutor(action).ExpandCommand(vars)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return vars.Redact(expanded)
+}
+
+// describeLimits lists the limits of a spell, for --test-spell and --dry-run
+func describeLimits(limits config.LimitsConfig) string {
+	var parts []string
+	if limits.Memory != "" {
+		parts = append(parts, "memory "+limits.Memory)
+	}
+	if limits.CPUTime > 0 {
+		parts = append(parts, fmt.Sprintf("cpu_time %ds", limits.CPUTime))
+	}
+	if limits.Processes > 0 {
+		parts = append(parts, fmt.Sprintf("processes %d", limits.Processes))
+	}
+	if limits.FileSize != "" {
+		parts = append(parts, "file_size "+limits.FileSize)
+	}
+	if limits.Nice > 0 {
+		parts = append(parts, fmt.Sprintf("nice %d", limits.Nice))
+	}
+	if limits.IONice != "" {
+		parts = append(parts, "ionice "+limits.IONice)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeSandbox summarizes the sandbox of a spell, for --test-spell and --dry-run
+func describeSandbox(box config.SandboxConfig) string {
+	parts := []string{"own process group", "restricted environment"}
+	if !box.Network {
+		parts = append(parts, "no network")
+	}
+	if len(box.ReadOnly) > 0 {
+		parts = append(parts, "read-only "+strings.Join(box.ReadOnly, ", "))
+	}
+	if len(box.Env) > 0 {
+		parts = append(parts, "passes "+strings.Join(box.Env, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// outputRuleEffect describes what an output rule does, for --test-spell and --dry-run
+func outputRuleEffect(rule config.OutputRule) string {
+	var effects []string
+	if rule.Status != "" {
+		effects = append(effects, rule.Status)
+	}
+	if rule.Show {
+		effects = append(effects, "show")
+	}
+	if len(effects) == 0 {
+		return "(capture)"
+	}
+	return "(" + strings.Join(effects, ", ") + ")"
+}
@@ -5,6 +5,8 @@ go 1.23
 require (
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/getlantern/systray v1.2.2
+	github.com/godbus/dbus/v5 v5.1.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/robotn/gohook v0.42.2
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/sys v0.26.0
@@ -22,6 +24,5 @@ require (
 	github.com/getlantern/ops v0.0.0-20190325191751-d70cb0d6f85f // indirect
 	github.com/go-stack/stack v1.8.0 // indirect
 	github.com/oxtoacart/bpool v0.0.0-20190530202638-03653db5a59c // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/vcaesar/keycode v0.10.1 // indirect
 )
@@ -7,11 +7,13 @@ import (
 	"os/exec"
 
 	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
 )
 
 // AppExecutor executes application launch actions
 type AppExecutor struct {
-	config config.ActionConfig
+	config   config.ActionConfig
+	envScope *dotenv.Scope
 }
 
 // NewAppExecutor creates a new application executor
@@ -21,10 +23,24 @@ func NewAppExecutor(cfg *config.ActionConfig) *AppExecutor {
 	}
 }
 
+// SetEnvScope sets the env files and secrets file that variables are
+// resolved from
+func (e *AppExecutor) SetEnvScope(scope *dotenv.Scope) {
+	e.envScope = scope
+}
+
 // Execute launches the application
 func (e *AppExecutor) Execute(ctx context.Context) error {
+	vars, err := e.envScope.Resolve(e.config.EnvFile)
+	if err != nil {
+		return err
+	}
+
 	// Expand environment variables in command
-	path := os.ExpandEnv(e.config.Command)
+	path, err := vars.Expand(e.config.Command)
+	if err != nil {
+		return fmt.Errorf("failed to expand command: %w", err)
+	}
 
 	// Get platform-specific app launcher
 	launcher := GetAppLauncher()
@@ -48,15 +64,19 @@ func (e *AppExecutor) Execute(ctx context.Context) error {
 
 	// Set working directory if specified
 	if e.config.WorkingDir != "" {
-		cmd.Dir = os.ExpandEnv(e.config.WorkingDir)
+		if cmd.Dir, err = vars.Expand(e.config.WorkingDir); err != nil {
+			return fmt.Errorf("failed to expand working directory: %w", err)
+		}
 	}
 
 	// Set environment variables
-	if len(e.config.Env) > 0 {
-		cmd.Env = os.Environ()
-		for k, v := range e.config.Env {
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, os.ExpandEnv(v)))
+	cmd.Env = vars.Environ()
+	for k, v := range e.config.Env {
+		value, err := vars.Expand(v)
+		if err != nil {
+			return fmt.Errorf("failed to expand %s: %w", k, err)
 		}
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, value))
 	}
 
 	// Start the application without waiting
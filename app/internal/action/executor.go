@@ -3,40 +3,134 @@ package action
 import (
 	"context"
 	stderrors "errors"
+	"io"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/SphereStacking/silentcast/internal/action/app"
 	"github.com/SphereStacking/silentcast/internal/action/script"
+	"github.com/SphereStacking/silentcast/internal/action/shell"
 	"github.com/SphereStacking/silentcast/internal/action/url"
 	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
 	"github.com/SphereStacking/silentcast/internal/elevated"
 	"github.com/SphereStacking/silentcast/internal/errors"
+	"github.com/SphereStacking/silentcast/internal/notify"
+	"github.com/SphereStacking/silentcast/internal/output"
+	"github.com/SphereStacking/silentcast/internal/terminal"
 )
 
-// Manager manages action execution
+// sequenceKey is the context key for the key sequence that cast a spell
+type sequenceKey struct{}
+
+// WithSequence returns a context that records the key sequence that cast the
+// spell, so that executors can include it in notifications
+func WithSequence(ctx context.Context, sequence string) context.Context {
+	return context.WithValue(ctx, sequenceKey{}, sequence)
+}
+
+// sequenceFromContext returns the key sequence recorded by WithSequence, if any
+func sequenceFromContext(ctx context.Context) string {
+	sequence, _ := ctx.Value(sequenceKey{}).(string)
+	return sequence
+}
+
+// Observer is told when spells start and finish executing
+type Observer interface {
+	SpellStarted(spell, sequence string)
+	SpellFinished(spell, sequence string, err error, elapsed time.Duration)
+}
+
+// Manager manages action execution. Spells may be cast concurrently, e.g.
+// from a notification button and the control socket, so the grimoire and
+// notifier, which reloads replace, are guarded by mu.
 type Manager struct {
-	grimoire map[string]config.ActionConfig
+	mu        sync.RWMutex
+	grimoire  map[string]config.ActionConfig
+	notifier  notify.Sender
+	observer  Observer
+	shells    shell.Manager
+	terminals terminal.Manager
+	runLogs   *output.RunLogs
+	console   io.Writer
+	envScope  *dotenv.Scope
 }
 
 // NewManager creates a new action manager
 func NewManager(grimoire map[string]config.ActionConfig) *Manager {
 	return &Manager{
-		grimoire: grimoire,
+		grimoire:  grimoire,
+		shells:    shell.NewManager(),
+		terminals: terminal.NewManager(),
 	}
 }
 
 // UpdateActions updates the grimoire with new actions
 func (m *Manager) UpdateActions(grimoire map[string]config.ActionConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.grimoire = grimoire
 }
 
+// SetNotifier sets the sender used by executors that report their own
+// notifications. When unset, a default manager is created on first use.
+func (m *Manager) SetNotifier(notifier notify.Sender) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifier = notifier
+}
+
+// sender returns the notifier, creating the default one on first use
+func (m *Manager) sender() notify.Sender {
+	m.mu.RLock()
+	notifier := m.notifier
+	m.mu.RUnlock()
+	if notifier != nil {
+		return notifier
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.notifier == nil {
+		m.notifier = notify.NewManager()
+	}
+	return m.notifier
+}
+
+// SetRunLogs sets where the output of script runs is logged
+func (m *Manager) SetRunLogs(runLogs *output.RunLogs) {
+	m.runLogs = runLogs
+}
+
+// SetConsole sets a writer that script output is streamed to, e.g. the
+// daemon console in --no-tray mode
+func (m *Manager) SetConsole(console io.Writer) {
+	m.console = console
+}
+
+// SetEnvScope sets the env files and secrets file that the variables of
+// app and script actions are resolved from
+func (m *Manager) SetEnvScope(scope *dotenv.Scope) {
+	m.envScope = scope
+}
+
+// SetObserver sets the observer told about every execution
+func (m *Manager) SetObserver(observer Observer) {
+	m.observer = observer
+}
+
 // Execute executes an action by spell name
 func (m *Manager) Execute(ctx context.Context, spellName string) error {
-	action, exists := m.grimoire[spellName]
+	m.mu.RLock()
+	grimoire := m.grimoire
+	m.mu.RUnlock()
+
+	action, exists := grimoire[spellName]
 	if !exists {
 		// Get available spells for context
-		availableSpells := make([]string, 0, len(m.grimoire))
-		for spell := range m.grimoire {
+		availableSpells := make([]string, 0, len(grimoire))
+		for spell := range grimoire {
 			availableSpells = append(availableSpells, spell)
 		}
 		sort.Strings(availableSpells)
@@ -48,7 +142,8 @@ func (m *Manager) Execute(ctx context.Context, spellName string) error {
 			WithContext("suggested_action", "check spellbook.yml configuration")
 	}
 
-	executor, err := m.createExecutor(&action)
+	sequence := sequenceFromContext(ctx)
+	executor, err := m.createExecutor(spellName, sequence, &action)
 	if err != nil {
 		// Add spell context to the original error
 		var spellErr *errors.SpellbookError
@@ -61,7 +156,15 @@ func (m *Manager) Execute(ctx context.Context, spellName string) error {
 			WithContext("spell_name", spellName)
 	}
 
-	if err := executor.Execute(ctx); err != nil {
+	if m.observer != nil {
+		m.observer.SpellStarted(spellName, sequence)
+		start := time.Now()
+		defer func() {
+			m.observer.SpellFinished(spellName, sequence, err, time.Since(start))
+		}()
+	}
+
+	if err = executor.Execute(ctx); err != nil {
 		return errors.Wrap(errors.ErrorTypeSystem, "failed to execute spell", err).
 			WithContext("spell_name", spellName).
 			WithContext("action_type", action.Type)
@@ -71,14 +174,24 @@ func (m *Manager) Execute(ctx context.Context, spellName string) error {
 }
 
 // createExecutor creates an executor based on action type
-func (m *Manager) createExecutor(action *config.ActionConfig) (Executor, error) {
+func (m *Manager) createExecutor(spellName, sequence string, action *config.ActionConfig) (Executor, error) {
 	var executor Executor
 
 	switch action.Type {
 	case "app":
-		executor = app.NewAppExecutor(action)
+		appExecutor := app.NewAppExecutor(action)
+		appExecutor.SetEnvScope(m.envScope)
+		executor = appExecutor
 	case "script":
-		executor = script.NewScriptExecutor(action)
+		scriptExecutor := script.NewScriptExecutor(action)
+		scriptExecutor.SetNotifier(notify.ForSpell(m.sender(), spellName))
+		scriptExecutor.SetSpell(spellName, sequence)
+		scriptExecutor.SetShellManager(m.shells)
+		scriptExecutor.SetTerminalManager(m.terminals)
+		scriptExecutor.SetRunLogs(m.runLogs)
+		scriptExecutor.SetConsole(m.console)
+		scriptExecutor.SetEnvScope(m.envScope)
+		executor = scriptExecutor
 	case "url":
 		executor = url.NewURLExecutor(action)
 	default:
@@ -3,7 +3,9 @@ package action
 import (
 	"context"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/SphereStacking/silentcast/internal/action/app"
 	"github.com/SphereStacking/silentcast/internal/action/script"
@@ -80,6 +82,74 @@ func TestManager_Execute(t *testing.T) {
 	}
 }
 
+// recordingObserver records the spells it is told about
+type recordingObserver struct {
+	started  []string
+	finished []error
+}
+
+func (o *recordingObserver) SpellStarted(spell, sequence string) {
+	o.started = append(o.started, spell+" "+sequence)
+}
+
+func (o *recordingObserver) SpellFinished(spell, sequence string, err error, elapsed time.Duration) {
+	o.finished = append(o.finished, err)
+}
+
+func TestManager_Observer(t *testing.T) {
+	manager := NewManager(map[string]config.ActionConfig{
+		"ok":   {Type: "script", Command: "echo ok"},
+		"fail": {Type: "app", Command: "/non/existent/app"},
+	})
+	observer := &recordingObserver{}
+	manager.SetObserver(observer)
+
+	ctx := context.Background()
+	if err := manager.Execute(WithSequence(ctx, "g,o"), "ok"); err != nil {
+		t.Fatalf("Execute(ok) error = %v", err)
+	}
+	if err := manager.Execute(ctx, "fail"); err == nil {
+		t.Fatal("Expected Execute(fail) to fail")
+	}
+	if err := manager.Execute(ctx, "missing"); err == nil {
+		t.Fatal("Expected Execute(missing) to fail")
+	}
+
+	if strings.Join(observer.started, ",") != "ok g,o,fail " {
+		t.Errorf("started = %q, want ok and fail only", observer.started)
+	}
+	if len(observer.finished) != 2 || observer.finished[0] != nil || observer.finished[1] == nil {
+		t.Errorf("finished = %v, want success then failure", observer.finished)
+	}
+}
+
+func TestManager_ConcurrentExecute(t *testing.T) {
+	grimoire := map[string]config.ActionConfig{
+		"echo": {Type: "script", Command: "echo cast"},
+	}
+	manager := NewManager(grimoire)
+
+	// A notification button and the control socket may cast spells while the
+	// configuration is reloaded
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := manager.Execute(ctx, "echo"); err != nil {
+				t.Errorf("Execute() error = %v", err)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		manager.UpdateActions(grimoire)
+	}()
+	wg.Wait()
+}
+
 func TestAppExecutor_Execute(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package script
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setDaemonAttrs starts the script in a new session, so that it has no
+// controlling terminal and signals to SilentCast's process group miss it
+func setDaemonAttrs(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package script
+
+import (
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// setDaemonAttrs starts the script without a console in its own process
+// group, so that console events sent to SilentCast do not reach it
+func setDaemonAttrs(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+	}
+}
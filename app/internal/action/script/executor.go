@@ -2,24 +2,38 @@ package script
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/SphereStacking/silentcast/internal/action/shell"
+	"github.com/SphereStacking/silentcast/internal/clipboard"
 	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
 	"github.com/SphereStacking/silentcast/internal/errors"
 	"github.com/SphereStacking/silentcast/internal/notify"
 	"github.com/SphereStacking/silentcast/internal/output"
+	"github.com/SphereStacking/silentcast/internal/prompt"
+	"github.com/SphereStacking/silentcast/internal/sandbox"
+	"github.com/SphereStacking/silentcast/internal/terminal"
 	"github.com/SphereStacking/silentcast/pkg/logger"
 )
 
 // ScriptExecutor executes script/command actions
 type ScriptExecutor struct {
-	config   config.ActionConfig
-	notifier *notify.Manager
+	config    config.ActionConfig
+	notifier  notify.Sender
+	shells    shell.Manager
+	terminals terminal.Manager
+	runLogs   *output.RunLogs
+	envScope  *dotenv.Scope
+	console   io.Writer
+	spell     string
+	sequence  string
 }
 
 // NewScriptExecutor creates a new script executor
@@ -30,67 +44,130 @@ func NewScriptExecutor(cfg *config.ActionConfig) *ScriptExecutor {
 	}
 }
 
+// SetNotifier replaces the notification sender, e.g. with the daemon's queue
+func (e *ScriptExecutor) SetNotifier(notifier notify.Sender) {
+	e.notifier = notifier
+}
+
+// SetShellManager sets the manager that resolves shells, so that detected
+// shells are shared between executions
+func (e *ScriptExecutor) SetShellManager(shells shell.Manager) {
+	e.shells = shells
+}
+
+// SetTerminalManager sets the manager that opens terminal windows, so that
+// detected terminals are shared between executions
+func (e *ScriptExecutor) SetTerminalManager(terminals terminal.Manager) {
+	e.terminals = terminals
+}
+
+// SetRunLogs sets where the output of each run is logged
+func (e *ScriptExecutor) SetRunLogs(runLogs *output.RunLogs) {
+	e.runLogs = runLogs
+}
+
+// SetEnvScope sets the env files and secrets file that variables are
+// resolved from
+func (e *ScriptExecutor) SetEnvScope(scope *dotenv.Scope) {
+	e.envScope = scope
+}
+
+// SetConsole sets a writer that script output is streamed to, line by line
+// with the spell name as prefix, e.g. the daemon console in --no-tray mode
+func (e *ScriptExecutor) SetConsole(console io.Writer) {
+	e.console = console
+}
+
+// SetSpell records the grimoire name and key sequence, for notify_template
+func (e *ScriptExecutor) SetSpell(name, sequence string) {
+	e.spell = name
+	e.sequence = sequence
+}
+
 // Execute runs the script or command
 func (e *ScriptExecutor) Execute(ctx context.Context) error {
-	// Apply timeout if configured
-	if e.config.Timeout > 0 {
-		var cancel context.CancelFunc
+	mode := e.config.ExecutionMode()
+	if mode != config.ScriptModeWait {
+		// Detached scripts outlive the spell execution that started them
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	// Apply timeout if configured; a daemon runs until it exits by itself
+	cancel := context.CancelFunc(func() {})
+	if e.config.Timeout > 0 && mode != config.ScriptModeDaemon {
 		ctx, cancel = context.WithTimeout(ctx, time.Duration(e.config.Timeout)*time.Second)
-		defer cancel()
 	}
-	// Expand environment variables in command
-	command := os.ExpandEnv(e.config.Command)
+	defer func() { cancel() }()
+
+	// Variables come from the env files and the process environment
+	vars, err := e.envScope.Resolve(e.config.EnvFile)
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeConfig, "failed to load environment", err).
+			WithContext("action_type", "script").
+			WithContext("error_type", "env_file_failed").
+			WithContext("suggested_action", "check env_file in spellbook.yml")
+	}
+	if e.config.Sandbox.Enabled {
+		// Expanding variables must not reveal what the sandbox hides
+		vars.Restrict(sandbox.Visible(e.config.Sandbox.Env))
+	}
+
+	command, err := e.ExpandCommand(vars)
+	if err != nil {
+		return expandError(e.config.Command, err)
+	}
 
 	// Check for empty command
 	if strings.TrimSpace(command) == "" {
 		return errors.New(errors.ErrorTypeConfig, "empty command").
-			WithContext("command", e.config.Command).
+			WithContext("command", e.script()).
 			WithContext("action_type", "script").
 			WithContext("error_type", "empty_command").
-			WithContext("suggested_action", "provide a valid command in spellbook.yml")
-	}
-
-	// Get platform-specific shell executor
-	shellExec := shell.GetShellExecutor()
-	shell, shellFlag := shellExec.GetShell()
-
-	// Override shell if custom one is specified
-	if e.config.Shell != "" {
-		shell = e.config.Shell
-		// Determine the appropriate flag for the custom shell
-		switch strings.ToLower(e.config.Shell) {
-		case "powershell", "pwsh":
-			shellFlag = "-Command"
-		case "cmd", "cmd.exe":
-			shellFlag = "/c"
-		default:
-			// Most Unix-like shells use -c
-			shellFlag = "-c"
-		}
+			WithContext("suggested_action", "provide a valid command or script_file in spellbook.yml")
 	}
 
-	// Create command
+	// Script files and commands with args run directly, split into words
+	// like a shell would; everything else runs in a shell or, for code with
+	// an interpreter shebang or interpreter: true, in the interpreter
 	var cmd *exec.Cmd
-	if len(e.config.Args) > 0 {
-		// If args are provided, don't use shell, execute directly
-		parts := strings.Fields(command)
-		if len(parts) > 0 {
-			cmd = exec.CommandContext(ctx, parts[0], append(parts[1:], e.config.Args...)...) //nolint:gosec // Command is from trusted config file
-		} else {
-			return errors.New(errors.ErrorTypeConfig, "empty command").
-				WithContext("command", command).
+	if e.config.ScriptFile != "" {
+		if cmd, err = e.scriptFileCommand(ctx, command); err != nil {
+			return err
+		}
+	} else if len(e.config.Args) > 0 && !e.config.Interpreter {
+		parts, err := shell.SplitWords(command)
+		if err != nil {
+			return errors.Wrap(errors.ErrorTypeConfig, "invalid command", err).
+				WithContext("command", e.script()).
 				WithContext("action_type", "script").
-				WithContext("error_type", "empty_command").
-				WithContext("suggested_action", "provide a valid command in spellbook.yml")
+				WithContext("error_type", "invalid_command").
+				WithContext("suggested_action", "close the quotes of the command in spellbook.yml")
 		}
+		cmd = exec.CommandContext(ctx, parts[0], append(parts[1:], e.config.Args...)...) //nolint:gosec // Command is from trusted config file
 	} else {
-		// Use shell to execute the command
-		cmd = exec.CommandContext(ctx, shell, shellFlag, command) //nolint:gosec // Command is from trusted config file
+		sh, err := e.ResolveShell(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Debug("Running %s with %s", e.String(), shell.FormatShellInfo(sh))
+		if shell.IsInterpreter(sh) {
+			cmd, err = e.shells.CreateInterpreterCommand(ctx, sh, interpreterCode(e.config.Command), e.config.Args, nil)
+		} else {
+			cmd, err = e.shells.CreateCommand(ctx, sh, command, nil)
+		}
+		if err != nil {
+			return errors.Wrap(errors.ErrorTypeSystem, "failed to create command", err).
+				WithContext("command", e.script()).
+				WithContext("action_type", "script").
+				WithContext("shell", sh.Name)
+		}
 	}
 
 	// Set working directory if specified
 	if e.config.WorkingDir != "" {
-		cmd.Dir = os.ExpandEnv(e.config.WorkingDir)
+		if cmd.Dir, err = vars.Expand(e.config.WorkingDir); err != nil {
+			return expandError(e.config.WorkingDir, err)
+		}
 	} else {
 		// Default to user's home directory
 		if home, err := os.UserHomeDir(); err == nil {
@@ -98,89 +175,244 @@ func (e *ScriptExecutor) Execute(ctx context.Context) error {
 		}
 	}
 
-	// Set environment variables
-	cmd.Env = os.Environ()
-	if len(e.config.Env) > 0 {
-		for k, v := range e.config.Env {
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, os.ExpandEnv(v)))
+	// Set environment variables; secrets are only available here. A
+	// sandboxed script only gets a few variables of SilentCast's environment
+	environ := os.Environ()
+	if e.config.Sandbox.Enabled {
+		environ = sandbox.Environ(environ, e.config.Sandbox.Env)
+	}
+	cmd.Env = vars.Append(environ)
+	for k, v := range e.config.Env {
+		value, err := vars.Expand(v)
+		if err != nil {
+			return expandError(v, err)
 		}
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, value))
 	}
 
-	// Setup output capture if ShowOutput is enabled
+	// Scripts that need a terminal run in a new terminal window; a script
+	// with stdin gets its input from SilentCast instead, and a contained one
+	// never escapes its limits into a terminal
+	contained := !e.config.Limits.IsZero() || e.config.Sandbox.Enabled
+	interactive := e.config.Stdin.IsZero() && !contained && shell.GetShellExecutor().IsInteractiveCommand(command)
+	if e.config.Terminal || e.config.ForceTerminal || e.config.KeepOpen || interactive {
+		return e.executeInTerminal(ctx, cmd)
+	}
+
+	// Feed the configured input to the script
+	stdin, err := e.openStdin(ctx, cmd.Dir)
+	if stderrors.Is(err, prompt.ErrCancelled) {
+		logger.Info("Input prompt of %s was cancelled; not running it", e.String())
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to read script input", err).
+			WithContext("command", e.script()).
+			WithContext("action_type", "script").
+			WithContext("stdin", strings.Join(e.config.Stdin.Sources(), ", ")).
+			WithContext("error_type", "stdin_failed").
+			WithContext("suggested_action", "check the stdin source of the spell")
+	}
+	if stdin != nil {
+		cmd.Stdin = stdin
+		if closer, ok := stdin.(io.Closer); ok {
+			defer closer.Close()
+		}
+	}
+
+	// Output is captured for the notification, output_rules, the run log and,
+	// in --no-tray mode, the console at the same time
+	reportResult := mode == config.ScriptModeWait && (e.config.ShowOutput || !e.config.NotifyTemplate.IsZero())
+	matchRules := mode == config.ScriptModeWait && (len(e.config.OutputRules) > 0 || e.config.CopyOutput)
+	runLog := e.createRunLog()
 	var outputManager output.Manager
-	if e.config.ShowOutput {
-		outputManager = output.NewBufferedManager(output.DefaultOptions())
-		writer := outputManager.StartCapture()
+	stopOutput := func() {}
+	defer func() { stopOutput() }()
+	switch {
+	case mode == config.ScriptModeDaemon:
+		// A daemon may outlive SilentCast, so it writes to its log directly
+		if runLog != nil {
+			cmd.Stdout = runLog.File()
+			cmd.Stderr = runLog.File()
+		}
+		setDaemonAttrs(cmd)
+	case reportResult || matchRules || runLog != nil || e.console != nil:
+		tee := output.NewTeeManager(output.DefaultOptions())
+		if runLog != nil {
+			_ = tee.AddDestination(runLog)
+		}
+		if e.console != nil {
+			_ = tee.AddDestination(output.NewPrefixWriter(e.console, "["+e.logName()+"] "))
+		}
+		outputManager = tee
+		stopOutput = func() {
+			if stopErr := tee.Stop(); stopErr != nil {
+				logger.Warn("Failed to stop output manager: %v", stopErr)
+			}
+		}
+		writer := tee.StartCapture()
 
 		// Redirect both stdout and stderr to our output manager
 		cmd.Stdout = writer
 		cmd.Stderr = writer
 	}
 
-	// Handle terminal execution based on config
-	needsTerminal := e.config.Terminal || e.config.KeepOpen || shellExec.IsInteractiveCommand(command)
-	if needsTerminal {
-		// Use WrapInTerminalWithOptions when available
-		cmd = shellExec.WrapInTerminalWithOptions(ctx, cmd, e.config.KeepOpen)
+	// Under a pseudo-terminal, the captured output is read from the terminal;
+	// the run log and console get it raw, notifications as plain text
+	var ptmx, tty *os.File
+	var ptyDone <-chan struct{}
+	if e.config.PTY && mode == config.ScriptModeWait {
+		sink := cmd.Stdout
+		if ptmx, tty, err = attachPTY(cmd); err != nil {
+			e.finishRunLog(runLog, -1, err)
+			return errors.Wrap(errors.ErrorTypeSystem, "failed to open pseudo-terminal", err).
+				WithContext("command", e.script()).
+				WithContext("action_type", "script").
+				WithContext("error_type", "pty_failed").
+				WithContext("suggested_action", "remove pty from the spell")
+		}
+		ptyDone = copyPTY(sink, ptmx)
+	}
+
+	// Limits and the sandbox wrap the command last, keeping its process attributes
+	if contained {
+		if err = sandbox.Apply(cmd, e.config.Limits, e.config.Sandbox); err != nil {
+			if ptmx != nil {
+				_ = tty.Close()
+				closePTY(ptmx, ptyDone)
+			}
+			e.finishRunLog(runLog, -1, err)
+			return errors.Wrap(errors.ErrorTypeConfig, "failed to apply limits or sandbox", err).
+				WithContext("command", e.script()).
+				WithContext("action_type", "script").
+				WithContext("error_type", "sandbox_failed").
+				WithContext("suggested_action", "check limits and sandbox of the spell")
+		}
 	}
 
 	// Start the script
-	if err := cmd.Start(); err != nil {
+	err = cmd.Start()
+	if tty != nil {
+		// The script holds its own copy of the terminal
+		_ = tty.Close()
+	}
+	if err != nil {
+		if ptmx != nil {
+			closePTY(ptmx, ptyDone)
+		}
+		e.finishRunLog(runLog, -1, err)
+		suggestion := "check if command exists and has execute permissions"
+		if e.config.Sandbox.Enabled {
+			suggestion = "check that unprivileged user namespaces are enabled, or allow network without read_only paths"
+		}
 		return errors.Wrap(errors.ErrorTypeSystem, "failed to start script", err).
-			WithContext("command", e.config.Command).
+			WithContext("command", e.script()).
 			WithContext("action_type", "script").
 			WithContext("working_dir", cmd.Dir).
 			WithContext("error_type", "start_failed").
-			WithContext("suggested_action", "check if command exists and has execute permissions")
+			WithContext("suggested_action", suggestion)
 	}
 
-	// For background scripts, detach from the process
-	if !e.shouldWaitForCompletion(command) && !e.config.ShowOutput {
-		if err := cmd.Process.Release(); err != nil {
-			// Non-fatal error
-			_ = err // Explicitly ignore
-		}
+	// Detached scripts are waited for in the background, so that they are
+	// reaped instead of lingering as zombies; their exit status is only logged
+	if mode != config.ScriptModeWait {
+		go e.reap(cmd, cancel, stopOutput, runLog)
+		cancel, stopOutput = func() {}, func() {}
 		return nil
 	}
 
-	// Wait for completion if needed
-	err := cmd.Wait()
+	// Wait for completion; a non-zero exit status is returned and reported
+	// as a failed spell even without show_output
+	started := time.Now()
+	stopWarning := e.scheduleTimeoutWarning(ctx)
+	err = cmd.Wait()
+	stopWarning()
+	if ptmx != nil {
+		closePTY(ptmx, ptyDone)
+	}
+	timedOut := e.config.Timeout > 0 && ctx.Err() == context.DeadlineExceeded
+	e.finishRunLog(runLog, exitCodeOf(cmd, timedOut), err)
+	elapsed := time.Since(started)
 
-	// Send notification with output if ShowOutput is enabled
-	if e.config.ShowOutput && outputManager != nil {
-		capturedOutput := outputManager.GetOutput()
+	// output_rules may fail a run that exited with status 0, or mark it
+	// with warnings, and choose the lines to show
+	capturedOutput := ""
+	if outputManager != nil {
+		capturedOutput = outputManager.GetOutput()
+	}
+	if ptmx != nil {
+		capturedOutput = output.RenderTerminalText(capturedOutput)
+	}
+	matched := matchOutputRules(e.config.OutputRules, capturedOutput)
+	runErr := err
+	if err == nil && matched.Status == config.OutputStatusFailure {
+		runErr = matched.failure()
+	} else if err == nil && matched.Status == config.OutputStatusWarning {
+		logger.Warn("Output of %s matched warning rule %q: %s", e.String(), matched.Rule, matched.Line)
+	}
+	if runErr == nil && e.config.CopyOutput {
+		if copyErr := clipboard.Write(ctx, capturedOutput); copyErr != nil {
+			runErr = errors.Wrap(errors.ErrorTypeSystem, "failed to copy output to clipboard", copyErr).
+				WithContext("action_type", "script").
+				WithContext("error_type", "copy_output_failed").
+				WithContext("suggested_action", "install a clipboard tool, or remove copy_output from the spell")
+		}
+	}
 
+	// Report the result with the captured output
+	if reportResult && outputManager != nil {
 		// Prepare notification
 		title := e.config.Description
 		if title == "" {
-			title = fmt.Sprintf("Script: %s", e.config.Command)
+			title = fmt.Sprintf("Script: %s", e.script())
 		}
 
+		data := newTemplateData(&e.config, e.spell, e.sequence, capturedOutput, exitCodeOf(cmd, timedOut), elapsed, runErr)
+		data.addOutputMatch(matched)
+		templates := e.config.NotifyTemplate
+
 		// Determine notification level based on error
 		// Notification errors are logged but don't affect script execution
-		if err != nil { //nolint:gocritic // Simple if-else is clearer than switch here
-			if notifyErr := e.notifier.Error(ctx, title, fmt.Sprintf("Failed: %v\n\nOutput:\n%s", err, capturedOutput)); notifyErr != nil {
-				logger.Warn("Failed to send error notification: %v", notifyErr)
+		switch {
+		case timedOut:
+			timeout := &notify.TimeoutNotification{
+				ActionName:      title,
+				TimeoutDuration: e.config.Timeout,
+				ElapsedTime:     int(elapsed.Seconds()),
+				Output:          matched.Shown,
 			}
-		} else if capturedOutput != "" {
-			if notifyErr := e.notifier.Success(ctx, title, capturedOutput); notifyErr != nil {
-				logger.Warn("Failed to send success notification: %v", notifyErr)
+			if !templates.Timeout.IsZero() {
+				timeout.Title, timeout.Message = e.render(templates.Timeout, data,
+					"⏱️ Timeout: "+title, fmt.Sprintf("Script execution timed out after %d seconds", e.config.Timeout))
+				timeout.Rendered = true
 			}
-		} else {
-			if notifyErr := e.notifier.Info(ctx, title, "Command completed with no output"); notifyErr != nil {
-				logger.Warn("Failed to send info notification: %v", notifyErr)
+			if e.config.ShowOutput || timeout.Rendered {
+				if notifyErr := e.notifier.NotifyTimeout(ctx, timeout); notifyErr != nil {
+					logger.Warn("Failed to send timeout notification: %v", notifyErr)
+				}
 			}
-		}
-
-		// Clean up output manager
-		if stopErr := outputManager.Stop(); stopErr != nil {
-			logger.Warn("Failed to stop output manager: %v", stopErr)
+		case runErr != nil:
+			e.report(ctx, templates.Failure, data, notify.LevelError, title, fmt.Sprintf("Failed: %v\n\nOutput:\n%s", runErr, matched.Shown))
+		case matched.Status == config.OutputStatusWarning:
+			e.report(ctx, templates.WarningTemplate(), data, notify.LevelWarning, title, matched.Shown)
+		case matched.Shown != "":
+			e.report(ctx, templates.Success, data, notify.LevelSuccess, title, matched.Shown)
+		default:
+			level := notify.LevelInfo
+			if !templates.Success.IsZero() {
+				level = notify.LevelSuccess
+			}
+			message := "Command completed with no output"
+			if capturedOutput != "" {
+				message = "Command completed; no output lines matched output_rules"
+			}
+			e.report(ctx, templates.Success, data, level, title, message)
 		}
 	}
 
 	if err != nil {
 		return errors.Wrap(errors.ErrorTypeSystem, "script execution failed", err).
-			WithContext("command", e.config.Command).
+			WithContext("command", e.script()).
 			WithContext("action_type", "script").
 			WithContext("working_dir", cmd.Dir).
 			WithContext("error_type", "execution_failed").
@@ -188,25 +420,270 @@ func (e *ScriptExecutor) Execute(ctx context.Context) error {
 			WithContext("suggested_action", "check command syntax and arguments")
 	}
 
+	return runErr
+}
+
+// executeInTerminal opens cmd in a terminal window. The window outlives the
+// spell, so its output is not captured and the timeout does not apply.
+func (e *ScriptExecutor) executeInTerminal(ctx context.Context, cmd *exec.Cmd) error {
+	if e.terminals == nil {
+		e.terminals = terminal.NewManager()
+	}
+
+	selected, err := e.selectTerminal()
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "no terminal emulator found", err).
+			WithContext("command", e.script()).
+			WithContext("action_type", "script").
+			WithContext("error_type", "terminal_not_found").
+			WithContext("suggested_action", "install a terminal emulator, or remove terminal, force_terminal and keep_open from the spell")
+	}
+	if unsupported := e.config.TerminalCustomization.Unsupported(selected.SupportedFeatures); len(unsupported) > 0 {
+		logger.Warn("%s does not support %s; ignoring them for %s", selected.Name, strings.Join(unsupported, ", "), e.String())
+	}
+	if e.config.ShowOutput || !e.config.NotifyTemplate.IsZero() || len(e.config.OutputRules) > 0 {
+		logger.Debug("Output of %s is shown in the terminal, not in a notification", e.String())
+	}
+
+	title := e.config.Description
+	if title == "" {
+		title = e.spell
+	}
+	options := &terminal.Options{
+		KeepOpen:          e.config.KeepOpen,
+		WorkingDir:        cmd.Dir,
+		Title:             title,
+		PreferredTerminal: selected,
+		ForceTerminal:     e.config.ForceTerminal,
+		Customization:     e.config.TerminalCustomization,
+	}
+
+	// The window must not be closed when the spell's context ends
+	if err := e.terminals.ExecuteInTerminal(context.WithoutCancel(ctx), cmd, options); err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to open terminal", err).
+			WithContext("command", e.script()).
+			WithContext("action_type", "script").
+			WithContext("terminal", selected.Name).
+			WithContext("error_type", "terminal_failed").
+			WithContext("suggested_action", "set preferred_terminal to one of: "+strings.Join(terminalNames(e.terminals.GetAvailableTerminals()), ", "))
+	}
 	return nil
 }
 
+// selectTerminal returns the preferred terminal if it is available, and the
+// default terminal otherwise
+func (e *ScriptExecutor) selectTerminal() (terminal.Terminal, error) {
+	if preferred := e.config.PreferredTerminal; preferred != "" {
+		for _, t := range e.terminals.GetAvailableTerminals() {
+			if strings.EqualFold(t.Name, preferred) || strings.EqualFold(t.Command, preferred) {
+				return t, nil
+			}
+		}
+		logger.Warn("Preferred terminal %s is not available; using the default terminal", preferred)
+	}
+	return e.terminals.GetDefaultTerminal()
+}
+
+// terminalNames returns the names of terminals
+func terminalNames(terminals []terminal.Terminal) []string {
+	names := make([]string, 0, len(terminals))
+	for _, t := range terminals {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// report sends a completion notification, rendered from tmpl when it is set.
+// Without a template the default text is only sent when show_output is enabled.
+func (e *ScriptExecutor) report(ctx context.Context, tmpl config.MessageTemplate, data TemplateData, level notify.Level, title, message string) {
+	if tmpl.IsZero() && !e.config.ShowOutput {
+		return
+	}
+	if !tmpl.IsZero() {
+		title, message = e.render(tmpl, data, title, message)
+	}
+	if notifyErr := e.notifier.Notify(ctx, notify.Notification{Title: title, Message: message, Level: level}); notifyErr != nil {
+		logger.Warn("Failed to send %s notification: %v", strings.ToLower(level.String()), notifyErr)
+	}
+}
+
+// render renders a notify_template outcome, falling back to the default
+// text if the template cannot be rendered
+func (e *ScriptExecutor) render(tmpl config.MessageTemplate, data TemplateData, title, message string) (string, string) {
+	renderedTitle, renderedMessage, err := renderTemplate(tmpl, data, title, message)
+	if err != nil {
+		logger.Warn("Failed to render notify_template for %s: %v", e.String(), err)
+		return title, message
+	}
+	return renderedTitle, renderedMessage
+}
+
 // String returns a string representation of the action
 func (e *ScriptExecutor) String() string {
 	if e.config.Description != "" {
 		return e.config.Description
 	}
-	return fmt.Sprintf("Run script: %s", e.config.Command)
+	return fmt.Sprintf("Run script: %s", e.script())
+}
+
+// script returns the script file, or otherwise the command, of the action
+func (e *ScriptExecutor) script() string {
+	if e.config.ScriptFile != "" {
+		return e.config.ScriptFile
+	}
+	return e.config.Command
+}
+
+// scriptFileCommand creates the command that runs a script file, directly
+// or with the configured shell
+func (e *ScriptExecutor) scriptFileCommand(ctx context.Context, path string) (*exec.Cmd, error) {
+	if e.config.Shell == "" {
+		return exec.CommandContext(ctx, path, e.config.Args...), nil //nolint:gosec // Script file is from trusted config file
+	}
+
+	sh, err := e.ResolveShell(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("Running %s with %s", e.String(), shell.FormatShellInfo(sh))
+	name, args := shell.GetFileCommand(sh, path, e.config.Args)
+	return exec.CommandContext(ctx, name, args...), nil //nolint:gosec // Shell and script file are from trusted config file
 }
 
-// shouldWaitForCompletion determines if we should wait for the script to complete
-func (e *ScriptExecutor) shouldWaitForCompletion(command string) bool {
-	// Quick commands that should complete
-	quickCommands := []string{"git", "echo", "date", "pwd", "ls", "true", "false"}
-	for _, cmd := range quickCommands {
-		if strings.HasPrefix(command, cmd) {
-			return true
+// scheduleTimeoutWarning sends a warning timeout_warning seconds before the
+// timeout expires. The returned function cancels a warning that has not fired
+// yet, and waits for one that has.
+func (e *ScriptExecutor) scheduleTimeoutWarning(ctx context.Context) func() {
+	if e.config.Timeout <= 0 || e.config.TimeoutWarning <= 0 || e.config.TimeoutWarning >= e.config.Timeout {
+		return func() {}
+	}
+
+	elapsed := e.config.Timeout - e.config.TimeoutWarning
+	sent := make(chan struct{})
+	timer := time.AfterFunc(time.Duration(elapsed)*time.Second, func() {
+		defer close(sent)
+		if notifyErr := e.notifier.NotifyTimeout(ctx, &notify.TimeoutNotification{
+			ActionName:      e.String(),
+			TimeoutDuration: e.config.Timeout,
+			ElapsedTime:     elapsed,
+			Warning:         true,
+		}); notifyErr != nil {
+			logger.Warn("Failed to send timeout warning: %v", notifyErr)
+		}
+	})
+	return func() {
+		// A warning that is already being sent must arrive before the
+		// timeout notification
+		if !timer.Stop() {
+			<-sent
 		}
 	}
-	return false
+}
+
+// reap waits for a detached script and logs how it exited
+func (e *ScriptExecutor) reap(cmd *exec.Cmd, cancel context.CancelFunc, stopOutput func(), runLog *output.RunLog) {
+	defer cancel()
+	started := time.Now()
+	err := cmd.Wait()
+	stopOutput()
+	e.finishRunLog(runLog, exitCodeOf(cmd, false), err)
+	if err != nil {
+		logger.Warn("Detached script %s exited after %s: %v", e.String(), time.Since(started).Round(time.Second), err)
+		return
+	}
+	logger.Debug("Detached script %s finished after %s", e.String(), time.Since(started).Round(time.Second))
+}
+
+// createRunLog starts the output log of this run, or returns nil if the
+// spell is not logged
+func (e *ScriptExecutor) createRunLog() *output.RunLog {
+	if e.runLogs == nil || e.spell == "" || !e.runLogs.Enabled(&e.config) {
+		return nil
+	}
+	runLog, err := e.runLogs.Create(e.spell, e.script())
+	if err != nil {
+		logger.Warn("Failed to create output log for %s: %v", e.String(), err)
+		return nil
+	}
+	return runLog
+}
+
+// finishRunLog records how the run ended in its output log
+func (e *ScriptExecutor) finishRunLog(runLog *output.RunLog, exitCode int, runErr error) {
+	if runLog == nil {
+		return
+	}
+	if err := runLog.Finish(exitCode, runErr); err != nil {
+		logger.Warn("Failed to write output log %s: %v", runLog.Path(), err)
+	}
+}
+
+// ExpandCommand returns the command as it is run, with the variables of
+// vars expanded and those of env left to the shell. The loader has already
+// resolved a script file's path.
+func (e *ScriptExecutor) ExpandCommand(vars *dotenv.Vars) (string, error) {
+	if e.config.ScriptFile != "" {
+		return e.config.ScriptFile, nil
+	}
+	return vars.ExpandCommand(e.config.Command, e.config.Env)
+}
+
+// expandError reports a value whose variables could not be expanded, e.g.
+// for ${VAR:?message} with VAR unset
+func expandError(value string, err error) error {
+	return errors.Wrap(errors.ErrorTypeConfig, "failed to expand variables", err).
+		WithContext("value", value).
+		WithContext("action_type", "script").
+		WithContext("error_type", "expand_failed").
+		WithContext("suggested_action", "set the variable in the environment, an env_file or secrets_file")
+}
+
+// openStdin returns the configured input of the script, or nil if it has none
+func (e *ScriptExecutor) openStdin(ctx context.Context, dir string) (io.Reader, error) {
+	in := e.config.Stdin
+	switch {
+	case in.File != "":
+		file, err := os.Open(dotenv.ExpandPath(in.File, dir))
+		if err != nil {
+			return nil, err
+		}
+		return file, nil
+	case in.Clipboard:
+		text, err := clipboard.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(text), nil
+	case in.Prompt != "":
+		title := e.config.Description
+		if title == "" {
+			title = e.logName()
+		}
+		text, err := prompt.Ask(ctx, title, in.Prompt)
+		if err != nil {
+			return nil, err
+		}
+		// A full line, for scripts that read a line
+		return strings.NewReader(text + "\n"), nil
+	case in.Text != "":
+		return strings.NewReader(in.Text), nil
+	}
+	return nil, nil
+}
+
+// logName returns the name shown before console output
+func (e *ScriptExecutor) logName() string {
+	if e.spell != "" {
+		return e.spell
+	}
+	return e.String()
+}
+
+// exitCodeOf returns the exit status of a finished command, or -1 if it did
+// not exit by itself
+func exitCodeOf(cmd *exec.Cmd, timedOut bool) int {
+	if timedOut || cmd.ProcessState == nil {
+		return -1
+	}
+	return cmd.ProcessState.ExitCode()
 }
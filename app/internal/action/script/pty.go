@@ -0,0 +1,44 @@
+package script
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// ptyDrainTimeout is how long the output of a pseudo-terminal is read after
+// the script exits, in case processes it started keep the terminal open
+const ptyDrainTimeout = time.Second
+
+// ptyRows and ptyCols are the size of the pseudo-terminal, so that progress
+// bars and columns have room
+const (
+	ptyRows = 24
+	ptyCols = 120
+)
+
+// copyPTY copies the output of a pseudo-terminal to dst until the terminal is
+// closed. The returned channel is closed when copying ends.
+func copyPTY(dst io.Writer, ptmx *os.File) <-chan struct{} {
+	if dst == nil {
+		dst = io.Discard
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Reading fails with EIO once the script and its children have
+		// closed the terminal, which ends the output
+		_, _ = io.Copy(dst, ptmx)
+	}()
+	return done
+}
+
+// closePTY waits for the remaining output of a pseudo-terminal and closes it
+func closePTY(ptmx *os.File, done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(ptyDrainTimeout):
+	}
+	_ = ptmx.Close()
+	<-done
+}
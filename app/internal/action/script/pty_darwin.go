@@ -0,0 +1,51 @@
+//go:build darwin
+
+package script
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a new pseudo-terminal pair through /dev/ptmx
+func openPTY() (ptmx, tty *os.File, err error) {
+	ptmx, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var name [128]byte
+	conn, err := ptmx.SyscallConn()
+	if err == nil {
+		ctrlErr := conn.Control(func(fd uintptr) {
+			if err = unix.IoctlSetInt(int(fd), unix.TIOCPTYGRANT, 0); err != nil {
+				return
+			}
+			if err = unix.IoctlSetInt(int(fd), unix.TIOCPTYUNLK, 0); err != nil {
+				return
+			}
+			if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+				err = errno
+			}
+		})
+		if err == nil {
+			err = ctrlErr
+		}
+	}
+	if err == nil {
+		path := string(name[:])
+		if i := bytes.IndexByte(name[:], 0); i >= 0 {
+			path = string(name[:i])
+		}
+		tty, err = os.OpenFile(path, os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	}
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, err
+	}
+	return ptmx, tty, nil
+}
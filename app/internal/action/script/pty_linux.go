@@ -0,0 +1,41 @@
+//go:build linux
+
+package script
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a new pseudo-terminal pair through /dev/ptmx
+func openPTY() (ptmx, tty *os.File, err error) {
+	ptmx, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var n uint32
+	conn, err := ptmx.SyscallConn()
+	if err == nil {
+		ctrlErr := conn.Control(func(fd uintptr) {
+			if err = unix.IoctlSetPointerInt(int(fd), unix.TIOCSPTLCK, 0); err != nil {
+				return
+			}
+			n, err = unix.IoctlGetUint32(int(fd), unix.TIOCGPTN)
+		})
+		if err == nil {
+			err = ctrlErr
+		}
+	}
+	if err == nil {
+		tty, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	}
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, err
+	}
+	return ptmx, tty, nil
+}
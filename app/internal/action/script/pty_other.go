@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package script
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// attachPTY reports that pseudo-terminals are not supported on this platform
+func attachPTY(_ *exec.Cmd) (ptmx, tty *os.File, err error) {
+	return nil, nil, fmt.Errorf("pseudo-terminals are not supported on %s", runtime.GOOS)
+}
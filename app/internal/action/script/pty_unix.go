@@ -0,0 +1,44 @@
+//go:build linux || darwin
+
+package script
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// attachPTY connects the script to a new pseudo-terminal, which becomes its
+// controlling terminal in a new session. It returns the master side, from
+// which the output is read, and the terminal, which is closed once the
+// script has started.
+func attachPTY(cmd *exec.Cmd) (ptmx, tty *os.File, err error) {
+	ptmx, tty, err = openPTY()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := ptmx.SyscallConn()
+	if err == nil {
+		ctrlErr := conn.Control(func(fd uintptr) {
+			err = unix.IoctlSetWinsize(int(fd), unix.TIOCSWINSZ, &unix.Winsize{Row: ptyRows, Col: ptyCols})
+		})
+		if err == nil {
+			err = ctrlErr
+		}
+	}
+	if err != nil {
+		_ = ptmx.Close()
+		_ = tty.Close()
+		return nil, nil, err
+	}
+
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	// Ctty is the script's standard input
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	return ptmx, tty, nil
+}
@@ -0,0 +1,93 @@
+package script
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/errors"
+)
+
+// outputMatch is the result of matching output_rules against a run's output
+type outputMatch struct {
+	Status   string            // failure, warning, or empty
+	Rule     string            // Pattern that set the status
+	Line     string            // First line that set the status
+	Shown    string            // Lines of show rules, or the whole output without show rules
+	Captures map[string]string // Named groups of matching rules
+}
+
+// matchOutputRules matches the rules against each line of the output. A
+// failure rule takes precedence over a warning rule, and the first line that
+// matches a named group captures it. Patterns that do not compile are skipped.
+func matchOutputRules(rules []config.OutputRule, output string) outputMatch {
+	result := outputMatch{Shown: output, Captures: map[string]string{}}
+	if len(rules) == 0 {
+		return result
+	}
+
+	compiled := make([]*regexp.Regexp, len(rules))
+	showing := false
+	for i, rule := range rules {
+		if re, err := regexp.Compile(rule.Match); err == nil {
+			compiled[i] = re
+			showing = showing || rule.Show
+		}
+	}
+
+	var shown []string
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		show := false
+		for i, re := range compiled {
+			if re == nil {
+				continue
+			}
+			match := re.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			rule := rules[i]
+			show = show || rule.Show
+			if statusRank(rule.Status) > statusRank(result.Status) {
+				result.Status, result.Rule, result.Line = rule.Status, rule.Match, line
+			}
+			for j, name := range re.SubexpNames() {
+				if _, ok := result.Captures[name]; name != "" && !ok {
+					result.Captures[name] = match[j]
+				}
+			}
+		}
+		if show {
+			shown = append(shown, line)
+		}
+	}
+
+	if showing {
+		result.Shown = strings.Join(shown, "\n")
+	}
+	return result
+}
+
+// statusRank orders output rule statuses by severity
+func statusRank(status string) int {
+	switch status {
+	case config.OutputStatusFailure:
+		return 2
+	case config.OutputStatusWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// failure returns the error of a run whose output matched a failure rule
+func (m outputMatch) failure() error {
+	return errors.New(errors.ErrorTypeExecution, fmt.Sprintf("output matched failure rule %q: %s", m.Rule, m.Line)).
+		WithContext("action_type", "script").
+		WithContext("rule", m.Rule).
+		WithContext("line", m.Line).
+		WithContext("error_type", "output_rule_failed").
+		WithContext("suggested_action", "check the script output, or the status of the rule in output_rules")
+}
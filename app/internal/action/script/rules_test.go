@@ -0,0 +1,75 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestMatchOutputRules(t *testing.T) {
+	output := "ok   pkg/a\r\nWARN slow test\nFAIL pkg/b\nFAIL pkg/c\n12 passed, 2 failed\n"
+
+	tests := []struct {
+		name         string
+		rules        []config.OutputRule
+		wantStatus   string
+		wantLine     string
+		wantShown    string
+		wantCaptures map[string]string
+	}{
+		{
+			name:      "no rules keep the output",
+			wantShown: output,
+		},
+		{
+			name: "failure takes precedence over warning",
+			rules: []config.OutputRule{
+				{Match: `^WARN`, Status: config.OutputStatusWarning},
+				{Match: `^FAIL`, Status: config.OutputStatusFailure},
+			},
+			wantStatus: config.OutputStatusFailure,
+			wantLine:   "FAIL pkg/b",
+			wantShown:  output,
+		},
+		{
+			name:       "warning",
+			rules:      []config.OutputRule{{Match: `^WARN`, Status: config.OutputStatusWarning}},
+			wantStatus: config.OutputStatusWarning,
+			wantLine:   "WARN slow test",
+			wantShown:  output,
+		},
+		{
+			name:      "show selects lines",
+			rules:     []config.OutputRule{{Match: `^(ok|FAIL)\s`, Show: true}, {Match: `^FAIL`, Show: true}},
+			wantShown: "ok   pkg/a\nFAIL pkg/b\nFAIL pkg/c",
+		},
+		{
+			name:         "named groups are captured from the first match",
+			rules:        []config.OutputRule{{Match: `^FAIL (?P<pkg>\S+)`}, {Match: `(?P<passed>\d+) passed, (?P<failed>\d+) failed`}},
+			wantShown:    output,
+			wantCaptures: map[string]string{"pkg": "pkg/b", "passed": "12", "failed": "2"},
+		},
+		{
+			name:      "invalid patterns are skipped",
+			rules:     []config.OutputRule{{Match: `(`, Status: config.OutputStatusFailure, Show: true}},
+			wantShown: output,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchOutputRules(tt.rules, output)
+			if got.Status != tt.wantStatus || got.Line != tt.wantLine {
+				t.Errorf("status = %q (%q), want %q (%q)", got.Status, got.Line, tt.wantStatus, tt.wantLine)
+			}
+			if got.Shown != tt.wantShown {
+				t.Errorf("shown = %q, want %q", got.Shown, tt.wantShown)
+			}
+			for name, value := range tt.wantCaptures {
+				if got.Captures[name] != value {
+					t.Errorf("captures[%q] = %q, want %q", name, got.Captures[name], value)
+				}
+			}
+		})
+	}
+}
@@ -1,11 +1,23 @@
 package script
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/internal/notify"
+	"github.com/SphereStacking/silentcast/internal/output"
+	"github.com/SphereStacking/silentcast/internal/terminal"
 )
 
 func TestScriptExecutor_ShowOutput(t *testing.T) {
@@ -176,3 +188,918 @@ func TestScriptExecutor_Description(t *testing.T) {
 		})
 	}
 }
+
+// recordingSender records notifications instead of displaying them. Timeout
+// warnings arrive from a timer goroutine, so access is guarded by mu.
+type recordingSender struct {
+	mu            sync.Mutex
+	levels        []string
+	notifications []notify.Notification
+	timeouts      []*notify.TimeoutNotification
+}
+
+func (s *recordingSender) Notify(_ context.Context, n notify.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.levels = append(s.levels, strings.ToLower(n.Level.String()))
+	s.notifications = append(s.notifications, n)
+	return nil
+}
+
+func (s *recordingSender) Info(_ context.Context, _, _ string) error {
+	s.record("info")
+	return nil
+}
+
+func (s *recordingSender) Warning(_ context.Context, _, _ string) error {
+	s.record("warning")
+	return nil
+}
+
+func (s *recordingSender) Error(_ context.Context, _, _ string) error {
+	s.record("error")
+	return nil
+}
+
+func (s *recordingSender) Success(_ context.Context, _, _ string) error {
+	s.record("success")
+	return nil
+}
+
+func (s *recordingSender) NotifyTimeout(_ context.Context, n *notify.TimeoutNotification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeouts = append(s.timeouts, n)
+	return nil
+}
+
+// record records a notification sent without details
+func (s *recordingSender) record(level string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.levels = append(s.levels, level)
+}
+
+// Levels returns the levels of the notifications sent so far
+func (s *recordingSender) Levels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.levels...)
+}
+
+// Notifications returns the notifications sent so far
+func (s *recordingSender) Notifications() []notify.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]notify.Notification(nil), s.notifications...)
+}
+
+// Timeouts returns the timeout notifications sent so far
+func (s *recordingSender) Timeouts() []*notify.TimeoutNotification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*notify.TimeoutNotification(nil), s.timeouts...)
+}
+
+func TestScriptExecutor_SetNotifier(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	sender := &recordingSender{}
+	executor := NewScriptExecutor(&config.ActionConfig{Type: "script", Command: "echo hello", ShowOutput: true})
+	executor.SetNotifier(sender)
+
+	if err := executor.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if levels := sender.Levels(); len(levels) != 1 || levels[0] != "success" {
+		t.Errorf("notifications = %v, want [success]", levels)
+	}
+}
+
+func TestScriptExecutor_TimeoutNotification(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	sender := &recordingSender{}
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:        "script",
+		Command:     "sleep 5",
+		Description: "Slow task",
+		ShowOutput:  true,
+		Timeout:     1,
+	})
+	executor.SetNotifier(sender)
+
+	if err := executor.Execute(context.Background()); err == nil {
+		t.Fatal("Execute() expected timeout error")
+	}
+	timeouts, levels := sender.Timeouts(), sender.Levels()
+	if len(timeouts) != 1 || len(levels) != 0 {
+		t.Fatalf("timeouts = %d, other notifications = %v; want one timeout only", len(timeouts), levels)
+	}
+	if got := timeouts[0]; got.ActionName != "Slow task" || got.TimeoutDuration != 1 {
+		t.Errorf("unexpected timeout notification: %+v", got)
+	}
+}
+
+func TestScriptExecutor_TimeoutWarning(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	sender := &recordingSender{}
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:           "script",
+		Command:        "sleep 3",
+		ShowOutput:     true,
+		Timeout:        2,
+		TimeoutWarning: 1,
+	})
+	executor.SetNotifier(sender)
+
+	if err := executor.Execute(context.Background()); err == nil {
+		t.Fatal("Execute() expected timeout error")
+	}
+	if timeouts := sender.Timeouts(); len(timeouts) != 2 || !timeouts[0].Warning || timeouts[1].Warning {
+		t.Fatalf("want a warning followed by a timeout, got %+v", timeouts)
+	}
+}
+
+func TestScriptExecutor_NotifyTemplate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	templates := config.NotifyTemplateConfig{
+		Success: config.MessageTemplate{
+			Title:   "{{.Spell}} ({{.Sequence}})",
+			Message: "Deployed {{.Captures.version}} to {{.Captures.env}} in {{.Duration}}",
+		},
+		Failure:  config.MessageTemplate{Message: "exit {{.ExitCode}}: {{tail 1 .Output}}"},
+		Captures: map[string]string{"version": `version (v[0-9.]+)`, "env": `staging|production`},
+	}
+
+	tests := []struct {
+		name        string
+		command     string
+		showOutput  bool
+		wantLevel   string
+		wantTitle   string
+		wantMessage string
+	}{
+		{
+			name:        "success template",
+			command:     "echo building; echo version v1.4.2 on staging",
+			wantLevel:   "success",
+			wantTitle:   "deploy (g,d)",
+			wantMessage: "Deployed v1.4.2 to staging in ",
+		},
+		{
+			name:        "failure template keeps default title",
+			command:     "echo first; echo last line; exit 3",
+			wantLevel:   "error",
+			wantTitle:   "Deploy",
+			wantMessage: "exit 3: last line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			executor := NewScriptExecutor(&config.ActionConfig{
+				Type:           "script",
+				Command:        tt.command,
+				Description:    "Deploy",
+				NotifyTemplate: templates,
+			})
+			executor.SetNotifier(sender)
+			executor.SetSpell("deploy", "g,d")
+
+			_ = executor.Execute(context.Background())
+
+			notifications, levels := sender.Notifications(), sender.Levels()
+			if len(notifications) != 1 {
+				t.Fatalf("notifications = %v, want one", levels)
+			}
+			n := notifications[0]
+			if levels[0] != tt.wantLevel || n.Title != tt.wantTitle || !strings.HasPrefix(n.Message, tt.wantMessage) {
+				t.Errorf("got %s %q %q, want %s %q %q...", levels[0], n.Title, n.Message, tt.wantLevel, tt.wantTitle, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_NotifyTemplateOnlyForConfiguredOutcomes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	// Without show_output, only outcomes with a template are reported
+	sender := &recordingSender{}
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:           "script",
+		Command:        "echo done",
+		NotifyTemplate: config.NotifyTemplateConfig{Failure: config.MessageTemplate{Message: "failed"}},
+	})
+	executor.SetNotifier(sender)
+
+	if err := executor.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if levels := sender.Levels(); len(levels) != 0 {
+		t.Errorf("notifications = %v, want none", levels)
+	}
+}
+
+func TestScriptExecutor_NotifyTemplateTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	sender := &recordingSender{}
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:    "script",
+		Command: "sleep 5",
+		Timeout: 1,
+		NotifyTemplate: config.NotifyTemplateConfig{
+			Timeout: config.MessageTemplate{Title: "{{.Spell}} is stuck", Message: "gave up after {{.Timeout}}s"},
+		},
+	})
+	executor.SetNotifier(sender)
+	executor.SetSpell("backup", "")
+
+	if err := executor.Execute(context.Background()); err == nil {
+		t.Fatal("Execute() expected timeout error")
+	}
+	timeouts := sender.Timeouts()
+	if len(timeouts) != 1 {
+		t.Fatalf("timeouts = %d, want 1", len(timeouts))
+	}
+	if got := timeouts[0]; !got.Rendered || got.Title != "backup is stuck" || got.Message != "gave up after 1s" {
+		t.Errorf("unexpected timeout notification: %+v", got)
+	}
+}
+
+// fakeTerminals is a terminal.Manager that records what it was asked to open
+type fakeTerminals struct {
+	available []terminal.Terminal
+	cmd       *exec.Cmd
+	options   *terminal.Options
+}
+
+func (f *fakeTerminals) ExecuteInTerminal(_ context.Context, cmd *exec.Cmd, options *terminal.Options) error {
+	f.cmd = cmd
+	f.options = options
+	return nil
+}
+
+func (f *fakeTerminals) GetAvailableTerminals() []terminal.Terminal {
+	return f.available
+}
+
+func (f *fakeTerminals) GetDefaultTerminal() (terminal.Terminal, error) {
+	if len(f.available) == 0 {
+		return terminal.Terminal{}, terminal.ErrNoTerminalFound
+	}
+	return f.available[0], nil
+}
+
+func (f *fakeTerminals) IsTerminalAvailable(t terminal.Terminal) bool {
+	for _, available := range f.available {
+		if available.Command == t.Command {
+			return true
+		}
+	}
+	return false
+}
+
+func TestScriptExecutor_ExecuteInTerminal(t *testing.T) {
+	xterm := terminal.Terminal{Name: "xterm", Command: "xterm", Priority: 50}
+	kitty := terminal.Terminal{Name: "kitty", Command: "kitty", Priority: 85}
+	customization := &terminal.Customization{Width: 120, Height: 40}
+
+	tests := []struct {
+		name      string
+		preferred string
+		want      string
+	}{
+		{name: "default terminal", want: "xterm"},
+		{name: "preferred by name", preferred: "Kitty", want: "kitty"},
+		{name: "unavailable preferred falls back", preferred: "alacritty", want: "xterm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terminals := &fakeTerminals{available: []terminal.Terminal{xterm, kitty}}
+			executor := NewScriptExecutor(&config.ActionConfig{
+				Type:                  "script",
+				Command:               "htop",
+				Description:           "System monitor",
+				WorkingDir:            t.TempDir(),
+				KeepOpen:              true,
+				PreferredTerminal:     tt.preferred,
+				TerminalCustomization: customization,
+			})
+			executor.SetTerminalManager(terminals)
+
+			if err := executor.Execute(context.Background()); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			options := terminals.options
+			if options == nil {
+				t.Fatal("command was not opened in a terminal")
+			}
+			if options.PreferredTerminal.Command != tt.want {
+				t.Errorf("terminal = %s, want %s", options.PreferredTerminal.Command, tt.want)
+			}
+			if options.Title != "System monitor" || !options.KeepOpen || options.Customization != customization {
+				t.Errorf("unexpected options: %+v", options)
+			}
+			if options.WorkingDir != terminals.cmd.Dir || options.WorkingDir == "" {
+				t.Errorf("working dir = %q, want the script's %q", options.WorkingDir, terminals.cmd.Dir)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_NoTerminal(t *testing.T) {
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:     "script",
+		Command:  "echo hi",
+		Terminal: true,
+	})
+	executor.SetTerminalManager(&fakeTerminals{})
+
+	err := executor.Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no terminal emulator found") {
+		t.Errorf("Execute() error = %v, want no terminal emulator found", err)
+	}
+}
+
+func TestScriptExecutor_WaitReportsExitStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	// make, npm and the like are waited for too, without show_output
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:    "script",
+		Command: "sleep 0.2; exit 3",
+	})
+
+	err := executor.Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Execute() error = %v, want exit status 3", err)
+	}
+}
+
+func TestScriptExecutor_OutputRules(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	rules := []config.OutputRule{
+		{Match: `^FAIL`, Status: config.OutputStatusFailure, Show: true},
+		{Match: `^WARN`, Status: config.OutputStatusWarning, Show: true},
+		{Match: `(?P<passed>[0-9]+) passed`},
+	}
+
+	tests := []struct {
+		name        string
+		command     string
+		wantErr     string
+		wantLevel   string
+		wantMessage string
+	}{
+		{
+			name:        "failure despite exit status 0",
+			command:     "echo ok a; echo FAIL b; echo 3 passed",
+			wantErr:     "output matched failure rule",
+			wantLevel:   "error",
+			wantMessage: "3 passed: FAIL b",
+		},
+		{
+			name:        "warning",
+			command:     "echo ok a; echo WARN slow; echo 4 passed",
+			wantLevel:   "warning",
+			wantMessage: "4 passed: WARN slow",
+		},
+		{
+			name:        "success",
+			command:     "echo ok a; echo 5 passed",
+			wantLevel:   "success",
+			wantMessage: "5 passed: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			executor := NewScriptExecutor(&config.ActionConfig{
+				Type:        "script",
+				Command:     tt.command,
+				OutputRules: rules,
+				NotifyTemplate: config.NotifyTemplateConfig{
+					Success: config.MessageTemplate{Message: "{{.Captures.passed}} passed: {{.Shown}}"},
+					Failure: config.MessageTemplate{Message: "{{.Captures.passed}} passed: {{.Shown}}"},
+				},
+			})
+			executor.SetNotifier(sender)
+
+			err := executor.Execute(context.Background())
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+			}
+			notifications, levels := sender.Notifications(), sender.Levels()
+			if len(notifications) != 1 {
+				t.Fatalf("notifications = %v, want one", levels)
+			}
+			if levels[0] != tt.wantLevel || notifications[0].Message != strings.TrimSpace(tt.wantMessage) {
+				t.Errorf("got %s %q, want %s %q", levels[0], notifications[0].Message, tt.wantLevel, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_PTY(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("pseudo-terminals are supported on Linux and macOS")
+	}
+
+	dir := t.TempDir()
+	runLogs := output.NewRunLogs(dir)
+	runLogs.Configure(config.ExecutionLogsConfig{Enabled: true, MaxRuns: 10, MaxSize: 1024})
+
+	sender := &recordingSender{}
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:       "script",
+		Command:    `if [ -t 1 ]; then echo tty; else echo no tty; fi; printf '\033[31mred\033[0m\n'; printf '10%%\r100%%\n'`,
+		ShowOutput: true,
+		PTY:        true,
+	})
+	executor.SetNotifier(sender)
+	executor.SetRunLogs(runLogs)
+	executor.SetSpell("progress", "p")
+
+	if err := executor.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	notifications := sender.Notifications()
+	if len(notifications) != 1 {
+		t.Fatalf("notifications = %v, want one", sender.Levels())
+	}
+	if got, want := notifications[0].Message, "tty\nred\n100%\n"; got != want {
+		t.Errorf("notification = %q, want %q", got, want)
+	}
+
+	// The log keeps the raw terminal output
+	path, err := output.LatestRunLog(dir, "progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logged, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(logged), "\x1b[31mred\x1b[0m\r\n") {
+		t.Errorf("log = %q, want raw terminal output", logged)
+	}
+}
+
+// fakeCommands puts shell scripts named after commands first in PATH
+func fakeCommands(t *testing.T, scripts map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("WAYLAND_DISPLAY", "")
+	return dir
+}
+
+func TestScriptExecutor_Stdin(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses fake clipboard and dialog tools")
+	}
+
+	dir := fakeCommands(t, map[string]string{
+		"xclip":  `printf '{"b":2}'`,
+		"zenity": `[ "$5" = "Word" ] && echo hola || exit 1`,
+	})
+	if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte("from file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		stdin config.StdinConfig
+		want  string
+	}{
+		{name: "text", stdin: config.StdinConfig{Text: "literal $HOME"}, want: "literal $HOME"},
+		{name: "file relative to working_dir", stdin: config.StdinConfig{File: "input.txt"}, want: "from file\n"},
+		{name: "clipboard", stdin: config.StdinConfig{Clipboard: true}, want: `{"b":2}`},
+		{name: "prompt", stdin: config.StdinConfig{Prompt: "Word"}, want: "hola\n"},
+		{name: "cancelled prompt does not run the script", stdin: config.StdinConfig{Prompt: "Other"}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := fmt.Sprintf("out%d.txt", i)
+			executor := NewScriptExecutor(&config.ActionConfig{
+				Type:       "script",
+				Command:    "cat > " + out,
+				WorkingDir: dir,
+				Stdin:      tt.stdin,
+			})
+			if err := executor.Execute(context.Background()); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			got, err := os.ReadFile(filepath.Join(dir, out))
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("script ran with %q, want not run", got)
+				}
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("stdin = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_CopyOutput(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses a fake clipboard tool")
+	}
+
+	dir := t.TempDir()
+	store := filepath.Join(dir, "clipboard")
+	fakeCommands(t, map[string]string{"xclip": "cat > '" + store + "'"})
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{name: "success is copied", command: "echo formatted", want: "formatted\n"},
+		{name: "failure is not copied", command: "echo partial; exit 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(store)
+			executor := NewScriptExecutor(&config.ActionConfig{Type: "script", Command: tt.command, CopyOutput: true})
+			_ = executor.Execute(context.Background())
+
+			got, err := os.ReadFile(store)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("clipboard = %q, want untouched", got)
+				}
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("clipboard = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_ExpandCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "deploy.env"), []byte("REGION=eu-west-1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scope := dotenv.NewScope(dir)
+	vars, err := scope.Resolve(config.StringList{"deploy.env"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:    "script",
+		Command: "deploy --region $REGION --stage ${STAGE:-staging} --token $TOKEN",
+		Env:     map[string]string{"TOKEN": "${secret:API_TOKEN}"},
+	})
+	got, err := executor.ExpandCommand(vars)
+	if err != nil {
+		t.Fatalf("ExpandCommand() error = %v", err)
+	}
+	// Variables of env are left to the shell
+	if want := "deploy --region eu-west-1 --stage staging --token ${TOKEN}"; got != want {
+		t.Errorf("ExpandCommand() = %q, want %q", got, want)
+	}
+
+	executor = NewScriptExecutor(&config.ActionConfig{Type: "script", ScriptFile: "/opt/scripts/$REGION.sh"})
+	if got, err := executor.ExpandCommand(vars); err != nil || got != "/opt/scripts/$REGION.sh" {
+		t.Errorf("ExpandCommand() = %q, %v; want the script file unchanged", got, err)
+	}
+}
+
+func TestScriptExecutor_EnvFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "deploy.env"), []byte("REGION=eu-west-1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secrets.env"), []byte("API_TOKEN=s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	scope := dotenv.NewScope(dir)
+	scope.Configure(nil, "secrets.env")
+	out := filepath.Join(dir, "out")
+
+	tests := []struct {
+		name    string
+		command string
+		env     map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "env file and secret",
+			command: `echo "$REGION $TOKEN" > ` + out,
+			env:     map[string]string{"TOKEN": "${secret:API_TOKEN}"},
+			want:    "eu-west-1 s3cr3t\n",
+		},
+		{
+			name:    "default",
+			command: "echo ${STAGE:-staging}-$REGION > " + out,
+			want:    "staging-eu-west-1\n",
+		},
+		{
+			name:    "required variable",
+			command: "echo ${STAGE:?set STAGE to deploy} > " + out,
+			wantErr: "set STAGE to deploy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(out)
+			executor := NewScriptExecutor(&config.ActionConfig{
+				Type:    "script",
+				Command: tt.command,
+				Env:     tt.env,
+				EnvFile: config.StringList{"deploy.env"},
+			})
+			executor.SetEnvScope(scope)
+
+			err := executor.Execute(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got, _ := os.ReadFile(out); string(got) != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_ScriptFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell scripts")
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	body := []byte("#!/bin/sh\necho \"$#:$1\" > " + out + "\n")
+	executable := filepath.Join(dir, "My Scripts", "backup.sh")
+	if err := os.MkdirAll(filepath.Dir(executable), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(executable, body, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plain := filepath.Join(dir, "plain.sh")
+	if err := os.WriteFile(plain, body, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		action config.ActionConfig
+		want   string
+	}{
+		{
+			name:   "executable script file",
+			action: config.ActionConfig{ScriptFile: executable, Args: []string{"two words"}},
+			want:   "1:two words\n",
+		},
+		{
+			name:   "script file run with a shell",
+			action: config.ActionConfig{ScriptFile: plain, Shell: "sh", Args: []string{"a", "b"}},
+			want:   "2:a\n",
+		},
+		{
+			name:   "quoted path in command with args",
+			action: config.ActionConfig{Command: `cp "` + executable + `"`, Args: []string{out}},
+			want:   string(body),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(out)
+			tt.action.Type = "script"
+			executor := NewScriptExecutor(&tt.action)
+
+			if err := executor.Execute(context.Background()); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got, _ := os.ReadFile(out); string(got) != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_UnterminatedQuote(t *testing.T) {
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:    "script",
+		Command: `echo "unterminated`,
+		Args:    []string{"arg"},
+	})
+
+	err := executor.Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid command") {
+		t.Errorf("Execute() error = %v, want invalid command", err)
+	}
+}
+
+func TestScriptExecutor_Sandbox(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox is only supported on Linux")
+	}
+	if err := exec.Command("unshare", "--user", "--net", "true").Run(); err != nil {
+		t.Skipf("user namespaces are not available: %v", err)
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	t.Setenv("SILENTCAST_LEAKED_TOKEN", "hunter2")
+	t.Setenv("SILENTCAST_PASSED", "yes")
+
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:    "script",
+		Command: `echo "[$SILENTCAST_LEAKED_TOKEN] [$SILENTCAST_PASSED] [$GREETING] $(grep -c : /proc/net/dev)" > ` + out,
+		Env:     map[string]string{"GREETING": "hi"},
+		Sandbox: config.SandboxConfig{Enabled: true, Env: []string{"SILENTCAST_PASSED"}},
+	})
+	if err := executor.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// Only passed variables and env reach the script, and it has no network
+	// interface but loopback
+	if got, _ := os.ReadFile(out); string(got) != "[] [yes] [hi] 1\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestScriptExecutor_RunLog(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	dir := t.TempDir()
+	runLogs := output.NewRunLogs(dir)
+	runLogs.Configure(config.ExecutionLogsConfig{Enabled: true, MaxRuns: 5, MaxSize: 64})
+	var console bytes.Buffer
+	sender := &recordingSender{}
+
+	executor := NewScriptExecutor(&config.ActionConfig{
+		Type:       "script",
+		Command:    "echo compiling; echo 'missing header' >&2; exit 2",
+		ShowOutput: true,
+	})
+	executor.SetNotifier(sender)
+	executor.SetSpell("build", "b")
+	executor.SetRunLogs(runLogs)
+	executor.SetConsole(&console)
+
+	if err := executor.Execute(context.Background()); err == nil {
+		t.Fatal("Execute() expected exit status error")
+	}
+
+	path, err := output.LatestRunLog(dir, "build")
+	if err != nil {
+		t.Fatalf("LatestRunLog() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, want := range []string{"compiling\n", "missing header\n", "# finished: exit 2"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("log %q does not contain %q", data, want)
+		}
+	}
+	if !strings.Contains(console.String(), "[build] compiling\n") {
+		t.Errorf("console = %q, want prefixed output", console.String())
+	}
+	// The notification still gets the output
+	if notifications := sender.Notifications(); len(notifications) != 1 || !strings.Contains(notifications[0].Message, "missing header") {
+		t.Errorf("notifications = %+v, want the failure with output", notifications)
+	}
+}
+
+func TestScriptExecutor_DetachedModes(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inspects processes through /proc")
+	}
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	// The script records its pid and session, then fails after a while
+	const script = `import os, sys, time
+open("ids.tmp", "w").write("%d %d" % (os.getpid(), os.getsid(0)))
+os.rename("ids.tmp", "ids")
+time.sleep(0.5)
+sys.exit(3)`
+
+	tests := []struct {
+		mode       string
+		newSession bool
+	}{
+		{mode: config.ScriptModeDetach},
+		{mode: config.ScriptModeDaemon, newSession: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			dir := t.TempDir()
+			executor := NewScriptExecutor(&config.ActionConfig{
+				Type:        "script",
+				Command:     script,
+				Shell:       "python3",
+				Interpreter: true,
+				WorkingDir:  dir,
+				Mode:        tt.mode,
+			})
+
+			start := time.Now()
+			if err := executor.Execute(context.Background()); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+				t.Errorf("Execute() waited %s for a detached script", elapsed)
+			}
+
+			ids := strings.Fields(waitForFile(t, filepath.Join(dir, "ids")))
+			if len(ids) != 2 {
+				t.Fatalf("ids = %v, want pid and session", ids)
+			}
+			pid, sid := ids[0], ids[1]
+			if (sid == pid) != tt.newSession {
+				t.Errorf("session = %s, pid = %s, want new session %v", sid, pid, tt.newSession)
+			}
+
+			// Once the script exits it must be reaped, not left as a zombie
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				if _, err := os.Stat("/proc/" + pid); os.IsNotExist(err) {
+					break
+				}
+				if time.Now().After(deadline) {
+					stat, _ := os.ReadFile("/proc/" + pid + "/stat")
+					t.Fatalf("process %s was not reaped: %s", pid, stat)
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+	}
+}
+
+// waitForFile returns the trimmed content of a file once a script has written it
+func waitForFile(t *testing.T, path string) string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s was not written", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,72 @@
+package script
+
+import (
+	"context"
+	"strings"
+
+	"github.com/SphereStacking/silentcast/internal/action/shell"
+	"github.com/SphereStacking/silentcast/internal/errors"
+)
+
+// ResolveShell returns the shell or interpreter that runs the script: the
+// configured shell, the interpreter named by a shebang, or the default shell.
+// With interpreter: true and no shell, the interpreter is detected from the
+// shebang or the code itself.
+func (e *ScriptExecutor) ResolveShell(ctx context.Context) (*shell.Shell, error) {
+	if e.shells == nil {
+		e.shells = shell.NewManager()
+	}
+
+	if e.config.Shell != "" {
+		sh, err := e.shells.GetShell(ctx, e.config.Shell)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrorTypeConfig, "shell not found", err).
+				WithContext("shell", e.config.Shell).
+				WithContext("action_type", "script").
+				WithContext("error_type", "shell_not_found").
+				WithContext("suggested_action", "install the shell or check the shell name in spellbook.yml")
+		}
+		if e.config.Interpreter && !shell.IsInterpreter(sh) {
+			return nil, errors.New(errors.ErrorTypeConfig, "shell is not an interpreter").
+				WithContext("shell", e.config.Shell).
+				WithContext("action_type", "script").
+				WithContext("error_type", "not_an_interpreter").
+				WithContext("suggested_action", "use python, node, ruby or perl with interpreter: true")
+		}
+		return sh, nil
+	}
+
+	if e.config.Interpreter {
+		sh, err := e.shells.GetInterpreterForScript(ctx, e.config.Command, "")
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrorTypeConfig, "no interpreter found for script", err).
+				WithContext("action_type", "script").
+				WithContext("error_type", "interpreter_not_found").
+				WithContext("suggested_action", "set shell to python3, node, ruby or perl, or start the script with a shebang")
+		}
+		return sh, nil
+	}
+
+	sh, err := e.shells.GetShellForScript(ctx, e.config.Command, "")
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrorTypeSystem, "no shell found", err).
+			WithContext("action_type", "script").
+			WithContext("error_type", "shell_not_found").
+			WithContext("suggested_action", "set shell in spellbook.yml")
+	}
+	return sh, nil
+}
+
+// interpreterCode returns a script as code for an interpreter. Unlike shell
+// commands, $VAR is not expanded, since it is part of the syntax of ruby and
+// perl; a shebang line is blanked so that line numbers stay the same.
+func interpreterCode(script string) string {
+	trimmed := strings.TrimLeft(script, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "#!") {
+		return script
+	}
+	if i := strings.IndexByte(trimmed, '\n'); i >= 0 {
+		return trimmed[i:]
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package script
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestInterpreterCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{name: "no shebang", script: "print($HOME)", want: "print($HOME)"},
+		{name: "shebang line blanked", script: "#!/usr/bin/env python3\nprint(1)", want: "\nprint(1)"},
+		{name: "only shebang", script: "#!/usr/bin/env node", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interpreterCode(tt.script); got != tt.want {
+				t.Errorf("interpreterCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_ResolveShellErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell names differ on Windows")
+	}
+
+	tests := []struct {
+		name    string
+		config  config.ActionConfig
+		wantErr string
+	}{
+		{
+			name:    "missing shell",
+			config:  config.ActionConfig{Command: "echo hi", Shell: "no-such-shell"},
+			wantErr: "shell not found",
+		},
+		{
+			name:    "shell is not an interpreter",
+			config:  config.ActionConfig{Command: "echo hi", Shell: "sh", Interpreter: true},
+			wantErr: "shell is not an interpreter",
+		},
+		{
+			name:    "no interpreter detected",
+			config:  config.ActionConfig{Command: "echo hi", Interpreter: true},
+			wantErr: "no interpreter found for script",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewScriptExecutor(&tt.config).ResolveShell(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolveShell() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScriptExecutor_Interpreter(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	tests := []struct {
+		name   string
+		config config.ActionConfig
+	}{
+		{
+			name: "interpreter shell",
+			config: config.ActionConfig{
+				Command:     "import os\nopen('out.txt', 'w').write(os.environ.get('GREETING', ''))",
+				Shell:       "python3",
+				Interpreter: true,
+			},
+		},
+		{
+			name: "multi-line script with shebang",
+			config: config.ActionConfig{
+				Command: "#!/usr/bin/env python3\nimport os\nopen('out.txt', 'w').write(os.environ.get('GREETING', ''))",
+			},
+		},
+		{
+			name: "interpreter detected from code",
+			config: config.ActionConfig{
+				Command:     "import os\nfrom pathlib import Path\nPath('out.txt').write_text(os.environ.get('GREETING', ''))",
+				Interpreter: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.config.Type = "script"
+			tt.config.WorkingDir = dir
+			tt.config.ShowOutput = true
+			tt.config.Env = map[string]string{"GREETING": "hello"}
+
+			if err := NewScriptExecutor(&tt.config).Execute(context.Background()); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+			if err != nil {
+				t.Fatalf("script did not run: %v", err)
+			}
+			if string(got) != "hello" {
+				t.Errorf("output = %q, want hello", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package script
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/errors"
+)
+
+// TemplateData is available to notify_template templates
+type TemplateData struct {
+	Spell       string            // Grimoire name
+	Sequence    string            // Key sequence that cast the spell, if any
+	Description string            // Spell description
+	Command     string            // Command, or script file, as configured
+	ExitCode    int               // -1 when the script was stopped by its timeout
+	Duration    time.Duration     // Run time, rounded for display
+	Output      string            // Combined stdout and stderr
+	Shown       string            // Lines selected by output_rules show, or the whole output
+	Status      string            // Status set by output_rules: failure, warning, or empty
+	Error       string            // Error message on failure
+	Timeout     int               // Configured timeout in seconds
+	Captures    map[string]string // Matches of notify_template.captures and output_rules named groups
+}
+
+// newTemplateData collects the data for a finished run
+func newTemplateData(cfg *config.ActionConfig, spell, sequence, output string, exitCode int, elapsed time.Duration, runErr error) TemplateData {
+	data := TemplateData{
+		Spell:       spell,
+		Sequence:    sequence,
+		Description: cfg.Description,
+		Command:     cfg.Command,
+		ExitCode:    exitCode,
+		Duration:    roundDuration(elapsed),
+		Output:      output,
+		Shown:       output,
+		Timeout:     cfg.Timeout,
+		Captures:    matchCaptures(cfg.NotifyTemplate.Captures, output),
+	}
+	if cfg.ScriptFile != "" {
+		data.Command = cfg.ScriptFile
+	}
+	if runErr != nil {
+		data.Error = runErr.Error()
+	}
+	return data
+}
+
+// addOutputMatch adds the result of output_rules. Named groups fill captures
+// that notify_template.captures did not match.
+func (d *TemplateData) addOutputMatch(match outputMatch) {
+	d.Shown = match.Shown
+	d.Status = match.Status
+	for name, value := range match.Captures {
+		if d.Captures[name] == "" {
+			d.Captures[name] = value
+		}
+	}
+}
+
+// matchCaptures matches each capture pattern against the output. A pattern's
+// first group is captured, or the whole match if it has no groups.
+// Patterns that do not compile or match capture an empty string.
+func matchCaptures(patterns map[string]string, output string) map[string]string {
+	captures := make(map[string]string, len(patterns))
+	for name, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			captures[name] = ""
+			continue
+		}
+		match := re.FindStringSubmatch(output)
+		switch {
+		case len(match) > 1:
+			captures[name] = match[1]
+		case len(match) == 1:
+			captures[name] = match[0]
+		default:
+			captures[name] = ""
+		}
+	}
+	return captures
+}
+
+// renderTemplate renders a notify_template outcome. Empty parts fall back to
+// the given defaults so that a template may set only the title or message.
+func renderTemplate(tmpl config.MessageTemplate, data TemplateData, defaultTitle, defaultMessage string) (title, message string, err error) {
+	title, err = renderPart("title", tmpl.Title, data, defaultTitle)
+	if err != nil {
+		return "", "", err
+	}
+	message, err = renderPart("message", tmpl.Message, data, defaultMessage)
+	if err != nil {
+		return "", "", err
+	}
+	return title, message, nil
+}
+
+// renderPart renders one template, or returns fallback if text is empty
+func renderPart(name, text string, data TemplateData, fallback string) (string, error) {
+	if text == "" {
+		return fallback, nil
+	}
+	tmpl, err := config.ParseNotifyTemplate(name, text)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrorTypeConfig, "invalid notify_template", err).
+			WithContext("template", name)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", errors.Wrap(errors.ErrorTypeConfig, "failed to render notify_template", err).
+			WithContext("template", name)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// roundDuration rounds to whole seconds, or milliseconds under a second
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Second)
+}
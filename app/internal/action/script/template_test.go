@@ -0,0 +1,60 @@
+package script
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestMatchCaptures(t *testing.T) {
+	output := "Uploading...\nversion v1.4.2\nenvironment: staging\n"
+	captures := matchCaptures(map[string]string{
+		"version": `version (v\S+)`,
+		"env":     `staging|production`,
+		"missing": `commit ([0-9a-f]+)`,
+		"invalid": `(`,
+	}, output)
+
+	want := map[string]string{"version": "v1.4.2", "env": "staging", "missing": "", "invalid": ""}
+	for name, value := range want {
+		if captures[name] != value {
+			t.Errorf("captures[%q] = %q, want %q", name, captures[name], value)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	cfg := &config.ActionConfig{Command: "make deploy", Timeout: 60}
+	data := newTemplateData(cfg, "deploy", "g,d", "one\ntwo\nthree\n", 2, 41600*time.Millisecond, errors.New("exit status 2"))
+
+	tests := []struct {
+		name        string
+		tmpl        config.MessageTemplate
+		wantTitle   string
+		wantMessage string
+	}{
+		{"defaults", config.MessageTemplate{}, "Default", "default"},
+		{"title only", config.MessageTemplate{Title: "{{.Spell}} failed"}, "deploy failed", "default"},
+		{"fields", config.MessageTemplate{Message: "{{.Command}} exited {{.ExitCode}} after {{.Duration}}: {{.Error}}"}, "Default", "make deploy exited 2 after 42s: exit status 2"},
+		{"head and tail", config.MessageTemplate{Message: "{{head 1 .Output}}..{{tail 2 .Output}}"}, "Default", "one..two\nthree"},
+		{"unknown capture is empty", config.MessageTemplate{Message: "[{{.Captures.nothing}}]"}, "Default", "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, message, err := renderTemplate(tt.tmpl, data, "Default", "default")
+			if err != nil {
+				t.Fatalf("renderTemplate() error = %v", err)
+			}
+			if title != tt.wantTitle || message != tt.wantMessage {
+				t.Errorf("renderTemplate() = %q, %q, want %q, %q", title, message, tt.wantTitle, tt.wantMessage)
+			}
+		})
+	}
+
+	if _, _, err := renderTemplate(config.MessageTemplate{Message: "{{.Nope}}"}, data, "", ""); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
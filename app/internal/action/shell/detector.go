@@ -381,6 +381,20 @@ func GetShellCommand(shell *Shell, script string) (command string, arguments []s
 	return shell.Executable, args
 }
 
+// GetFileCommand returns the command that runs a script file with a shell
+// or interpreter, followed by the script's arguments
+func GetFileCommand(shell *Shell, path string, args []string) (command string, arguments []string) {
+	switch shell.Type {
+	case ShellTypePowerShell:
+		arguments = []string{"-File", path}
+	case ShellTypeCmd:
+		arguments = []string{"/c", path}
+	default:
+		arguments = []string{path}
+	}
+	return shell.Executable, append(arguments, args...)
+}
+
 // IsInterpreter returns true if the shell is an interpreter (Python, Node, etc.)
 func IsInterpreter(shell *Shell) bool {
 	return shell != nil && shell.Type == ShellTypeInterpreter
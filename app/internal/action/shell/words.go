@@ -0,0 +1,80 @@
+package shell
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// SplitWords splits a command line into words like a shell does, without
+// expanding anything: single quotes keep their contents literally, double
+// quotes keep spaces, and a backslash escapes the next character. On
+// Windows, a backslash only escapes a double quote, so that paths keep their
+// separators.
+func SplitWords(s string) ([]string, error) {
+	return splitWords(s, runtime.GOOS != "windows")
+}
+
+// splitWords splits s; with posix, a backslash escapes any character
+func splitWords(s string, posix bool) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(runes) && escapes(runes[i+1], quote, posix):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// escapes reports whether a backslash escapes the next character. In double
+// quotes, as in a POSIX shell, it only escapes $, `, " and \.
+func escapes(next, quote rune, posix bool) bool {
+	switch {
+	case !posix:
+		return next == '"'
+	case quote == '"':
+		return strings.ContainsRune("$`\"\\", next)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,61 @@
+package shell
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		posix bool
+		want  []string
+	}{
+		{name: "plain", input: "  rsync -a  src dst ", posix: true, want: []string{"rsync", "-a", "src", "dst"}},
+		{name: "double quotes", input: `open "My Documents/report.pdf"`, posix: true, want: []string{"open", "My Documents/report.pdf"}},
+		{name: "single quotes are literal", input: `echo '$HOME \n'`, posix: true, want: []string{"echo", `$HOME \n`}},
+		{name: "escaped space", input: `ls My\ Files`, posix: true, want: []string{"ls", "My Files"}},
+		{name: "escapes in double quotes", input: `say "a \"quote\" and \n"`, posix: true, want: []string{"say", `a "quote" and \n`}},
+		{name: "empty quoted word", input: `grep "" file`, posix: true, want: []string{"grep", "", "file"}},
+		{name: "adjacent quotes join", input: `--name="a b"'c d'`, posix: true, want: []string{"--name=a bc d"}},
+		{name: "windows paths", input: `"C:\Program Files\App\app.exe" C:\temp\in.txt`, want: []string{`C:\Program Files\App\app.exe`, `C:\temp\in.txt`}},
+		{name: "windows escaped quote", input: `echo "say \"hi\""`, want: []string{"echo", `say "hi"`}},
+		{name: "empty", input: "   ", posix: true, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitWords(tt.input, tt.posix)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitWords(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitWords_UnterminatedQuote(t *testing.T) {
+	for _, input := range []string{`echo "open`, `echo 'open`} {
+		if _, err := splitWords(input, true); err == nil {
+			t.Errorf("splitWords(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestGetFileCommand(t *testing.T) {
+	tests := []struct {
+		shell Shell
+		want  []string
+	}{
+		{Shell{Executable: "/bin/bash", Type: ShellTypeBourne}, []string{"/bin/bash", "backup.sh", "--full"}},
+		{Shell{Executable: "pwsh", Type: ShellTypePowerShell}, []string{"pwsh", "-File", "backup.sh", "--full"}},
+		{Shell{Executable: "cmd", Type: ShellTypeCmd}, []string{"cmd", "/c", "backup.sh", "--full"}},
+	}
+
+	for _, tt := range tests {
+		command, args := GetFileCommand(&tt.shell, "backup.sh", []string{"--full"})
+		if got := append([]string{command}, args...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetFileCommand(%s) = %q, want %q", tt.shell.Executable, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package clipboard reads and writes the system clipboard through a
+// command-line tool found on the system.
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Tool is a command-line clipboard tool
+type Tool struct {
+	Name      string
+	ReadArgs  []string // Command and arguments that print the clipboard
+	WriteArgs []string // Command and arguments that set the clipboard from stdin
+	wayland   bool     // Only available in a Wayland session
+}
+
+// lookPath finds a command in PATH; replaced in tests
+var lookPath = exec.LookPath
+
+// getenv reads the environment; replaced in tests
+var getenv = os.Getenv
+
+// psUTF8 makes PowerShell read and write UTF-8 on the console
+const psUTF8 = "[Console]::InputEncoding = [Console]::OutputEncoding = [Text.Encoding]::UTF8; "
+
+// platformTools returns the clipboard tools known on the current platform,
+// most preferred first
+func platformTools() []Tool {
+	switch runtime.GOOS {
+	case "darwin":
+		return []Tool{
+			{Name: "pbpaste", ReadArgs: []string{"pbpaste"}, WriteArgs: []string{"pbcopy"}},
+		}
+	case "windows":
+		return []Tool{
+			{
+				Name:      "powershell",
+				ReadArgs:  []string{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command", psUTF8 + "Get-Clipboard -Raw"},
+				WriteArgs: []string{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command", psUTF8 + "Set-Clipboard -Value ([Console]::In.ReadToEnd())"},
+			},
+		}
+	default:
+		return []Tool{
+			{Name: "wl-clipboard", ReadArgs: []string{"wl-paste", "--no-newline"}, WriteArgs: []string{"wl-copy"}, wayland: true},
+			{Name: "xclip", ReadArgs: []string{"xclip", "-selection", "clipboard", "-out"}, WriteArgs: []string{"xclip", "-selection", "clipboard", "-in"}},
+			{Name: "xsel", ReadArgs: []string{"xsel", "--clipboard", "--output"}, WriteArgs: []string{"xsel", "--clipboard", "--input"}},
+		}
+	}
+}
+
+// DetectTool returns the most preferred clipboard tool that is available
+func DetectTool() (Tool, error) {
+	for _, tool := range platformTools() {
+		if tool.wayland && getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		if _, err := lookPath(tool.ReadArgs[0]); err != nil {
+			continue
+		}
+		if _, err := lookPath(tool.WriteArgs[0]); err != nil {
+			continue
+		}
+		return tool, nil
+	}
+	return Tool{}, fmt.Errorf("no clipboard tool found (%s)", toolHint())
+}
+
+// Read returns the text on the clipboard
+func Read(ctx context.Context) (string, error) {
+	tool, err := DetectTool()
+	if err != nil {
+		return "", err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool.ReadArgs[0], tool.ReadArgs[1:]...) //nolint:gosec // Arguments are fixed per tool
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to read clipboard with %s: %w%s", tool.Name, err, detail(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Write puts text on the clipboard
+func Write(ctx context.Context, text string) error {
+	tool, err := DetectTool()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool.WriteArgs[0], tool.WriteArgs[1:]...) //nolint:gosec // Arguments are fixed per tool
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to write clipboard with %s: %w%s", tool.Name, err, detail(stderr.String()))
+	}
+	return nil
+}
+
+// toolHint suggests how to install a clipboard tool on the current platform
+func toolHint() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "pbpaste ships with macOS"
+	case "windows":
+		return "PowerShell is required"
+	default:
+		return "install wl-clipboard, xclip or xsel"
+	}
+}
+
+// detail formats the error output of a tool for an error message
+func detail(stderr string) string {
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		return ": " + stderr
+	}
+	return ""
+}
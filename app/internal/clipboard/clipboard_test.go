@@ -0,0 +1,82 @@
+package clipboard
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDetectTool_Linux(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Linux clipboard tools")
+	}
+
+	originalLookPath, originalGetenv := lookPath, getenv
+	t.Cleanup(func() { lookPath, getenv = originalLookPath, originalGetenv })
+	available := map[string]bool{"wl-paste": true, "wl-copy": true, "xsel": true}
+	lookPath = func(file string) (string, error) {
+		if available[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	tests := []struct {
+		name    string
+		wayland string
+		want    string
+	}{
+		{name: "wayland session", wayland: "wayland-0", want: "wl-clipboard"},
+		{name: "X11 session", want: "xsel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv = func(key string) string {
+				if key == "WAYLAND_DISPLAY" {
+					return tt.wayland
+				}
+				return ""
+			}
+			tool, err := DetectTool()
+			if err != nil || tool.Name != tt.want {
+				t.Errorf("DetectTool() = %s, %v, want %s", tool.Name, err, tt.want)
+			}
+		})
+	}
+
+	available = map[string]bool{}
+	if _, err := DetectTool(); err == nil {
+		t.Error("DetectTool() succeeded without tools, want error")
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses a fake xclip")
+	}
+
+	// A fake xclip keeps the clipboard in a file
+	dir := t.TempDir()
+	store := filepath.Join(dir, "clipboard")
+	script := "#!/bin/sh\ncase \"$3\" in\n-out) cat '" + store + "' ;;\n-in) cat > '" + store + "' ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "xclip"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	if err := Write(context.Background(), "{\"a\": 1}\n"); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got != "{\"a\": 1}\n" {
+		t.Errorf("Read() = %q, want what was written", got)
+	}
+}
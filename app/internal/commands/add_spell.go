@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// AddSpellCommand adds a spell, and optionally its grimoire action, to the spellbook
+type AddSpellCommand struct {
+	getConfigPath func() string
+}
+
+// NewAddSpellCommand creates a new add spell command
+func NewAddSpellCommand(getConfigPath func() string) Command {
+	return &AddSpellCommand{
+		getConfigPath: getConfigPath,
+	}
+}
+
+// Name returns the command name
+func (c *AddSpellCommand) Name() string {
+	return "Add Spell"
+}
+
+// Description returns the command description
+func (c *AddSpellCommand) Description() string {
+	return "Add a spell bound to a grimoire action (requires -action)"
+}
+
+// FlagName returns the flag name
+func (c *AddSpellCommand) FlagName() string {
+	return "add-spell"
+}
+
+// IsActive checks if the command should run
+func (c *AddSpellCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.AddSpell != ""
+}
+
+// Execute runs the command
+func (c *AddSpellCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	sequence := f.AddSpell
+	actionName := f.SpellAction
+	if actionName == "" {
+		return fmt.Errorf("--add-spell requires --action <grimoire name>")
+	}
+
+	configDir := c.getConfigPath()
+	editor, err := config.NewEditor(spellbookFile(configDir))
+	if err != nil {
+		return fmt.Errorf("failed to open configuration: %w", err)
+	}
+
+	if existing, exists := editor.Spell(sequence); exists {
+		return fmt.Errorf("spell '%s' already exists (bound to '%s')", sequence, existing)
+	}
+
+	if err := checkSpellConflicts(configDir, editor, sequence, actionName); err != nil {
+		return err
+	}
+
+	if f.ActionCommand != "" {
+		if editor.HasAction(actionName) {
+			return fmt.Errorf("grimoire action '%s' already exists", actionName)
+		}
+
+		actionType := f.ActionType
+		if actionType == "" {
+			actionType = "script"
+		}
+		switch actionType {
+		case "app", "script", "url":
+		default:
+			return fmt.Errorf("invalid action type '%s' (must be app, script, or url)", actionType)
+		}
+
+		action := config.ActionConfig{
+			Type:        actionType,
+			Command:     f.ActionCommand,
+			Description: f.ActionDescription,
+		}
+		if err := editor.SetAction(actionName, action); err != nil {
+			return err
+		}
+	} else if !c.actionDefined(configDir, editor, actionName) {
+		return fmt.Errorf("grimoire action '%s' not found (use --action-command to create it)", actionName)
+	}
+
+	editor.SetSpell(sequence, actionName)
+
+	if err := saveSpellbook(editor); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Added spell '%s' → %s\n", sequence, actionName)
+	return nil
+}
+
+// actionDefined reports whether the action exists in the file or the merged configuration
+func (c *AddSpellCommand) actionDefined(configDir string, editor *config.Editor, actionName string) bool {
+	if editor.HasAction(actionName) {
+		return true
+	}
+	if cfg := mergedSpellbook(configDir); cfg != nil {
+		_, ok := cfg.Actions[actionName]
+		return ok
+	}
+	return false
+}
+
+// Group returns the command group
+func (c *AddSpellCommand) Group() string {
+	return "spell"
+}
+
+// HasOptions returns if this command has additional options
+func (c *AddSpellCommand) HasOptions() bool {
+	return true
+}
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const spellEditTestConfig = `# Hand-written spellbook
+spells:
+  e: editor # main editor
+  "g,s": git_status
+
+grimoire:
+  editor:
+    type: app
+    command: vi
+  git_status:
+    type: script
+    command: git status
+`
+
+func writeSpellEditConfig(t *testing.T, content string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "spellbook.yml"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return tmpDir
+}
+
+func readSpellEditConfig(t *testing.T, tmpDir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(tmpDir, "spellbook.yml"))
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	return string(data)
+}
+
+func TestAddSpellCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       *Flags
+		wantErr     string
+		wantContent []string
+	}{
+		{
+			name:        "bind to existing action",
+			flags:       &Flags{AddSpell: "v", SpellAction: "editor"},
+			wantContent: []string{"v: editor", "# main editor", "# Hand-written spellbook"},
+		},
+		{
+			name: "create new action",
+			flags: &Flags{
+				AddSpell:          "g,l",
+				SpellAction:       "git_log",
+				ActionCommand:     "git log --oneline",
+				ActionDescription: "Show git log",
+			},
+			wantContent: []string{`"g,l": git_log`, "git_log:", "type: script", "command: git log --oneline", "description: Show git log"},
+		},
+		{
+			name:    "missing action flag",
+			flags:   &Flags{AddSpell: "v"},
+			wantErr: "requires --action",
+		},
+		{
+			name:    "unknown action",
+			flags:   &Flags{AddSpell: "v", SpellAction: "missing"},
+			wantErr: "not found",
+		},
+		{
+			name:    "duplicate spell",
+			flags:   &Flags{AddSpell: "e", SpellAction: "editor"},
+			wantErr: "already exists",
+		},
+		{
+			name:    "prefix conflict",
+			flags:   &Flags{AddSpell: "g", SpellAction: "editor"},
+			wantErr: "conflicts",
+		},
+		{
+			name:    "invalid key",
+			flags:   &Flags{AddSpell: "ctrl+nope", SpellAction: "editor"},
+			wantErr: "invalid spell",
+		},
+		{
+			name:    "existing action not overwritten",
+			flags:   &Flags{AddSpell: "v", SpellAction: "editor", ActionCommand: "nano"},
+			wantErr: "already exists",
+		},
+		{
+			name:    "invalid action type",
+			flags:   &Flags{AddSpell: "v", SpellAction: "viewer", ActionType: "bogus", ActionCommand: "less"},
+			wantErr: "invalid action type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := writeSpellEditConfig(t, spellEditTestConfig)
+			cmd := NewAddSpellCommand(func() string { return tmpDir })
+
+			err := cmd.Execute(tt.flags)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				if readSpellEditConfig(t, tmpDir) != spellEditTestConfig {
+					t.Error("config modified despite error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			content := readSpellEditConfig(t, tmpDir)
+			for _, want := range tt.wantContent {
+				if !strings.Contains(content, want) {
+					t.Errorf("config missing %q:\n%s", want, content)
+				}
+			}
+
+			backups, _ := filepath.Glob(filepath.Join(tmpDir, "spellbook.yml.backup.*"))
+			if len(backups) != 1 {
+				t.Errorf("expected 1 backup, got %d", len(backups))
+			}
+		})
+	}
+}
+
+func TestAddSpellCommand_Metadata(t *testing.T) {
+	cmd := NewAddSpellCommand(func() string { return "" })
+
+	if cmd.FlagName() != "add-spell" {
+		t.Errorf("FlagName() = %v, want add-spell", cmd.FlagName())
+	}
+	if cmd.Group() != "spell" {
+		t.Errorf("Group() = %v, want spell", cmd.Group())
+	}
+	if !cmd.IsActive(&Flags{AddSpell: "x"}) || cmd.IsActive(&Flags{}) || cmd.IsActive("invalid") {
+		t.Error("IsActive() returned unexpected result")
+	}
+}
@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// ConfigDiffCommand shows how each configuration layer changes the base spellbook
+type ConfigDiffCommand struct {
+	getConfigPath func() string
+	out           io.Writer
+}
+
+// NewConfigDiffCommand creates a new config diff command
+func NewConfigDiffCommand(getConfigPath func() string) Command {
+	return &ConfigDiffCommand{
+		getConfigPath: getConfigPath,
+		out:           os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *ConfigDiffCommand) Name() string {
+	return "Config Diff"
+}
+
+// Description returns the command description
+func (c *ConfigDiffCommand) Description() string {
+	return "Show spells, actions, and settings changed by each configuration layer"
+}
+
+// FlagName returns the flag name
+func (c *ConfigDiffCommand) FlagName() string {
+	return "config-diff"
+}
+
+// IsActive checks if the command should run
+func (c *ConfigDiffCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.ConfigDiff
+}
+
+// configDiffReport is the JSON form of a layer diff
+type configDiffReport struct {
+	Base    string               `json:"base"`
+	Layers  []string             `json:"layers"`
+	Changes []config.LayerChange `json:"changes"`
+}
+
+// Execute runs the command
+func (c *ConfigDiffCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	loader := config.NewLoader(c.getConfigPath())
+	layers, err := loader.LoadLayers()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	changes, err := loader.DiffLayers(layers)
+	if err != nil {
+		return fmt.Errorf("failed to compare configuration layers: %w", err)
+	}
+
+	switch f.ShowFormat {
+	case "json":
+		return c.writeJSON(layers, changes)
+	case "human", "":
+		c.writeHuman(layers, changes)
+		return nil
+	default:
+		return fmt.Errorf("unsupported config-diff format: %s (use human or json)", f.ShowFormat)
+	}
+}
+
+// writeJSON prints the diff as a JSON document
+func (c *ConfigDiffCommand) writeJSON(layers []config.Layer, changes []config.LayerChange) error {
+	report := configDiffReport{
+		Base:    layers[0].Path,
+		Layers:  []string{},
+		Changes: changes,
+	}
+	for _, layer := range layers[1:] {
+		report.Layers = append(report.Layers, layer.Path)
+	}
+	if report.Changes == nil {
+		report.Changes = []config.LayerChange{}
+	}
+
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write config diff: %w", err)
+	}
+	return nil
+}
+
+// writeHuman prints the diff grouped by layer file
+func (c *ConfigDiffCommand) writeHuman(layers []config.Layer, changes []config.LayerChange) {
+	fmt.Fprintf(c.out, "📄 Base: %s\n", layers[0].Path)
+	if len(layers) == 1 {
+		fmt.Fprintln(c.out, "ℹ️  No override layers found; the merged configuration equals the base")
+		return
+	}
+
+	for _, layer := range layers[1:] {
+		fmt.Fprintf(c.out, "\n📄 Layer: %s\n", layer.Path)
+
+		count := 0
+		for _, change := range changes {
+			if change.File != layer.Path {
+				continue
+			}
+			count++
+			c.writeChange(change)
+		}
+		if count == 0 {
+			fmt.Fprintln(c.out, "  (no effective changes)")
+		}
+	}
+
+	added, overridden, removed := 0, 0, 0
+	for _, change := range changes {
+		switch change.Kind {
+		case config.ChangeAdded:
+			added++
+		case config.ChangeOverridden:
+			overridden++
+		case config.ChangeRemoved:
+			removed++
+		}
+	}
+	fmt.Fprintf(c.out, "\n📊 %d added, %d overridden, %d removed\n", added, overridden, removed)
+}
+
+// writeChange prints a single change line
+func (c *ConfigDiffCommand) writeChange(change config.LayerChange) {
+	switch change.Kind {
+	case config.ChangeAdded:
+		fmt.Fprintf(c.out, "  + %s.%s%s\n", change.Section, change.Key, formatDiffValue(change.Section, change.After))
+	case config.ChangeRemoved:
+		fmt.Fprintf(c.out, "  - %s.%s%s\n", change.Section, change.Key, formatDiffValue(change.Section, change.Before))
+	case config.ChangeOverridden:
+		if change.Section == config.SectionGrimoire {
+			fmt.Fprintf(c.out, "  ~ %s.%s (fields: %v)\n", change.Section, change.Key, change.Fields)
+			return
+		}
+		fmt.Fprintf(c.out, "  ~ %s.%s: %v → %v\n", change.Section, change.Key, change.Before, change.After)
+	}
+}
+
+// formatDiffValue renders a scalar value; actions are summarized by name only
+func formatDiffValue(section string, value interface{}) string {
+	if section == config.SectionGrimoire || value == nil {
+		return ""
+	}
+	return fmt.Sprintf(": %v", value)
+}
+
+// Group returns the command group
+func (c *ConfigDiffCommand) Group() string {
+	return "config"
+}
+
+// HasOptions returns if this command has additional options
+func (c *ConfigDiffCommand) HasOptions() bool {
+	return true
+}
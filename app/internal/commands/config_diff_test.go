@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func writeConfigDiffLayers(t *testing.T, overlay string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	base := "spells:\n  e: editor\ngrimoire:\n  editor:\n    type: app\n    command: vi\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "spellbook.yml"), []byte(base), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if overlay != "" {
+		platformFile := config.GetPlatformResolver().GetPlatformConfigFile()
+		if err := os.WriteFile(filepath.Join(tmpDir, platformFile), []byte(overlay), 0o600); err != nil {
+			t.Fatalf("Failed to write platform config: %v", err)
+		}
+	}
+	return tmpDir
+}
+
+func TestConfigDiffCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		overlay     string
+		format      string
+		wantErr     bool
+		wantOutputs []string
+	}{
+		{
+			name:        "base only",
+			wantOutputs: []string{"No override layers found"},
+		},
+		{
+			name:        "human format",
+			overlay:     "spells:\n  e: code\n  b: browser\ngrimoire:\n  editor:\n    type: app\n    command: code\n",
+			wantOutputs: []string{"~ spells.e: editor → code", "+ spells.b: browser", "~ grimoire.editor (fields: [command])", "1 added"},
+		},
+		{
+			name:    "unsupported format",
+			overlay: "spells:\n  e: code\n",
+			format:  "yaml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := writeConfigDiffLayers(t, tt.overlay)
+
+			var out bytes.Buffer
+			cmd := &ConfigDiffCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+
+			err := cmd.Execute(&Flags{ConfigDiff: true, ShowFormat: tt.format})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantOutputs {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestConfigDiffCommand_JSON(t *testing.T) {
+	tmpDir := writeConfigDiffLayers(t, "spells:\n  e: code\n")
+
+	var out bytes.Buffer
+	cmd := &ConfigDiffCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+	if err := cmd.Execute(&Flags{ConfigDiff: true, ShowFormat: "json"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var report struct {
+		Base    string   `json:"base"`
+		Layers  []string `json:"layers"`
+		Changes []struct {
+			File    string `json:"file"`
+			Section string `json:"section"`
+			Key     string `json:"key"`
+			Kind    string `json:"kind"`
+			Before  string `json:"before"`
+			After   string `json:"after"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+
+	if filepath.Base(report.Base) != "spellbook.yml" || len(report.Layers) != 1 {
+		t.Errorf("unexpected layers: base=%s layers=%v", report.Base, report.Layers)
+	}
+	if len(report.Changes) != 1 {
+		t.Fatalf("got %d changes, want 1: %+v", len(report.Changes), report.Changes)
+	}
+	change := report.Changes[0]
+	if change.Section != "spells" || change.Key != "e" || change.Kind != "overridden" || change.Before != "editor" || change.After != "code" {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	if change.File != report.Layers[0] {
+		t.Errorf("change file = %s, want %s", change.File, report.Layers[0])
+	}
+}
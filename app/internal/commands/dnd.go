@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/control"
+	"github.com/SphereStacking/silentcast/internal/notify"
+)
+
+// DNDCommand switches do-not-disturb of the running daemon through the control socket
+type DNDCommand struct {
+	getConfigPath func() string
+	out           io.Writer
+}
+
+// NewDNDCommand creates a new do-not-disturb command
+func NewDNDCommand(getConfigPath func() string) Command {
+	return &DNDCommand{
+		getConfigPath: getConfigPath,
+		out:           os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *DNDCommand) Name() string {
+	return "Do Not Disturb"
+}
+
+// Description returns the command description
+func (c *DNDCommand) Description() string {
+	return "Turn do-not-disturb on, off, or toggle it, or show held notifications (on|off|toggle|status|clear)"
+}
+
+// FlagName returns the flag name
+func (c *DNDCommand) FlagName() string {
+	return "dnd"
+}
+
+// IsActive checks if the command should run
+func (c *DNDCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.DND != ""
+}
+
+// Execute runs the command
+func (c *DNDCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+	if f.ShowFormat != "" && f.ShowFormat != "human" && f.ShowFormat != "json" {
+		return fmt.Errorf("unsupported dnd format: %s (use human or json)", f.ShowFormat)
+	}
+
+	path := control.SocketPath(c.getConfigPath())
+	resp, err := control.Send(path, control.Request{Command: "dnd", Args: []string{f.DND}}, control.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+
+	var status notify.DNDStatus
+	if err := json.Unmarshal(resp.Data, &status); err != nil {
+		return fmt.Errorf("invalid do-not-disturb status: %w", err)
+	}
+
+	if f.ShowFormat == "json" {
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(status)
+	}
+	c.writeHuman(&status)
+	return nil
+}
+
+// writeHuman prints the state and the held notifications
+func (c *DNDCommand) writeHuman(status *notify.DNDStatus) {
+	state := "off"
+	switch {
+	case status.Enabled:
+		state = "on"
+	case status.QuietHours:
+		state = "on (quiet hours)"
+	}
+
+	fmt.Fprintf(c.out, "🔕 Do Not Disturb: %s\n", state)
+	fmt.Fprintf(c.out, "   Mode: %s\n", status.Mode)
+	if len(status.Held) == 0 {
+		fmt.Fprintln(c.out, "   No held notifications")
+		return
+	}
+
+	fmt.Fprintf(c.out, "   Held notifications (%d):\n", len(status.Held))
+	for _, held := range status.Held {
+		line := fmt.Sprintf("   [%s] %-7s %s", held.HeldAt.Local().Format(time.TimeOnly), held.Level, held.Title)
+		if held.Message != "" {
+			line += ": " + held.Message
+		}
+		if held.Spell != "" {
+			line += fmt.Sprintf(" (%s)", held.Spell)
+		}
+		fmt.Fprintln(c.out, line)
+	}
+}
+
+// Group returns the command group
+func (c *DNDCommand) Group() string {
+	return "utility"
+}
+
+// HasOptions returns if this command has additional options
+func (c *DNDCommand) HasOptions() bool {
+	return true
+}
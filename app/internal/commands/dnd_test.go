@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/control"
+	"github.com/SphereStacking/silentcast/internal/notify"
+)
+
+func TestDNDCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dnd := notify.NewDoNotDisturb()
+	server := control.NewServer(control.SocketPath(tmpDir))
+	server.Handle("dnd", func(ctx context.Context, args []string) (string, interface{}, error) {
+		status, err := dnd.Apply(args[0])
+		return "", status, err
+	})
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("Failed to start control server: %v", err)
+	}
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		state      string
+		format     string
+		wantErr    bool
+		wantOutput string
+	}{
+		{name: "turn on", state: "on", wantOutput: "Do Not Disturb: on"},
+		{name: "status json", state: "status", format: "json", wantOutput: `"enabled": true`},
+		{name: "toggle off", state: "toggle", wantOutput: "Do Not Disturb: off"},
+		{name: "invalid state", state: "maybe", wantErr: true},
+		{name: "invalid format", state: "status", format: "yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := &DNDCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+
+			err := cmd.Execute(&Flags{DND: tt.state, ShowFormat: tt.format})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("Output %q does not contain %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestDNDCommand_HeldNotifications(t *testing.T) {
+	var out bytes.Buffer
+	cmd := &DNDCommand{out: &out}
+	cmd.writeHuman(&notify.DNDStatus{
+		QuietHours: true,
+		Mode:       "suppress",
+		Held:       []notify.HeldNotification{{Title: "Spell Cast", Message: "editor", Level: "info", Spell: "editor"}},
+	})
+
+	for _, want := range []string{"on (quiet hours)", "Held notifications (1)", "Spell Cast: editor (editor)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Output %q does not contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestDNDCommand_NotRunning(t *testing.T) {
+	cmd := &DNDCommand{getConfigPath: func() string { return t.TempDir() }, out: &bytes.Buffer{}}
+	if err := cmd.Execute(&Flags{DND: "on"}); err == nil {
+		t.Error("Expected error when no daemon is running")
+	}
+}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/control"
+	"github.com/SphereStacking/silentcast/internal/events"
+)
+
+// EventsCommand streams state changes of the running daemon. With --format json
+// it writes one JSON line per change, for status bars such as waybar, polybar,
+// i3blocks and tmux.
+type EventsCommand struct {
+	getConfigPath func() string
+	out           io.Writer
+}
+
+// NewEventsCommand creates a new events command
+func NewEventsCommand(getConfigPath func() string) Command {
+	return &EventsCommand{
+		getConfigPath: getConfigPath,
+		out:           os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *EventsCommand) Name() string {
+	return "Events"
+}
+
+// Description returns the command description
+func (c *EventsCommand) Description() string {
+	return "Stream prefix, spell and notification events of the running daemon (JSON lines with --format json)"
+}
+
+// FlagName returns the flag name
+func (c *EventsCommand) FlagName() string {
+	return "events"
+}
+
+// IsActive checks if the command should run
+func (c *EventsCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.Events
+}
+
+// Execute runs the command until interrupted or the daemon stops
+func (c *EventsCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+	if f.ShowFormat != "" && f.ShowFormat != "human" && f.ShowFormat != "json" {
+		return fmt.Errorf("unsupported events format: %s (use human or json)", f.ShowFormat)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return c.stream(ctx, f.ShowFormat == "json")
+}
+
+// stream writes events until ctx is canceled or the daemon closes the stream
+func (c *EventsCommand) stream(ctx context.Context, asJSON bool) error {
+	path := control.SocketPath(c.getConfigPath())
+	return control.Subscribe(ctx, path, control.Request{Command: "subscribe"}, func(data json.RawMessage) error {
+		if asJSON {
+			_, err := c.out.Write(data)
+			return err
+		}
+		var event events.Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("invalid event: %w", err)
+		}
+		_, err := fmt.Fprintln(c.out, formatEvent(&event))
+		return err
+	})
+}
+
+// formatEvent describes an event on one line
+func formatEvent(event *events.Event) string {
+	stamp := event.Time.Local().Format(time.TimeOnly)
+	switch event.Type {
+	case events.TypePrefix:
+		if event.State.PrefixActive {
+			return fmt.Sprintf("[%s] 🔵 Prefix active", stamp)
+		}
+		return fmt.Sprintf("[%s] ⚪ Prefix released", stamp)
+	case events.TypeSpellStarted:
+		line := fmt.Sprintf("[%s] ▶️  %s", stamp, event.Spell)
+		if event.Sequence != "" {
+			line += fmt.Sprintf(" (%s)", event.Sequence)
+		}
+		return line
+	case events.TypeSpellFinished:
+		result := event.Result
+		if result == nil {
+			return fmt.Sprintf("[%s] ⏹️  %s", stamp, event.Spell)
+		}
+		duration := time.Duration(result.DurationMs) * time.Millisecond
+		if result.OK {
+			return fmt.Sprintf("[%s] ✅ %s (%s)", stamp, result.Spell, duration)
+		}
+		return fmt.Sprintf("[%s] ❌ %s (%s): %s", stamp, result.Spell, duration, result.Error)
+	case events.TypeNotification:
+		n := event.Notification
+		if n == nil {
+			return fmt.Sprintf("[%s] 🔔", stamp)
+		}
+		line := fmt.Sprintf("[%s] 🔔 %-7s %s", stamp, n.Level, n.Title)
+		if n.Message != "" {
+			line += ": " + n.Message
+		}
+		if n.Held {
+			line += " (held)"
+		}
+		return line
+	default:
+		state := event.State
+		running := "nothing running"
+		if len(state.Running) > 0 {
+			running = "running " + strings.Join(state.Running, ", ")
+		}
+		line := fmt.Sprintf("[%s] 📡 Connected: %s", stamp, running)
+		if state.Last != nil {
+			outcome := "succeeded"
+			if !state.Last.OK {
+				outcome = "failed"
+			}
+			line += fmt.Sprintf("; last %s %s", state.Last.Spell, outcome)
+		}
+		return line
+	}
+}
+
+// Group returns the command group
+func (c *EventsCommand) Group() string {
+	return "utility"
+}
+
+// HasOptions returns if this command has additional options
+func (c *EventsCommand) HasOptions() bool {
+	return true
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/control"
+	"github.com/SphereStacking/silentcast/internal/events"
+)
+
+func TestEventsCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := events.NewBus()
+	bus.SpellStarted("build", "b")
+	server := control.NewServer(control.SocketPath(tmpDir))
+	server.HandleStream("subscribe", func(ctx context.Context, args []string, send func(data interface{}) error) error {
+		stream, unsubscribe := bus.Subscribe()
+		defer unsubscribe()
+		// Publish once the client has the initial state, then end the stream
+		first := <-stream
+		if err := send(first); err != nil {
+			return err
+		}
+		bus.SpellFinished("build", "b", errors.New("exit status 1"), 2*time.Second)
+		return send(<-stream)
+	})
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("Failed to start control server: %v", err)
+	}
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		format     string
+		wantOutput []string
+	}{
+		{name: "json", format: "json", wantOutput: []string{`"type":"state"`, `"running":["build"]`, `"type":"spell_finished"`, `"error":"exit status 1"`}},
+		{name: "human", format: "human", wantOutput: []string{"Connected: running build", "❌ build (2s): exit status 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus.SpellStarted("build", "b")
+			var out bytes.Buffer
+			cmd := &EventsCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+			if err := cmd.stream(ctx, tt.format == "json"); err != nil {
+				t.Fatalf("stream() error = %v", err)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("Output %q does not contain %q", out.String(), want)
+				}
+			}
+			if tt.format == "json" && strings.Count(out.String(), "\n") != 2 {
+				t.Errorf("Expected one JSON line per event, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestEventsCommand_Errors(t *testing.T) {
+	cmd := &EventsCommand{getConfigPath: func() string { return t.TempDir() }, out: &bytes.Buffer{}}
+	if err := cmd.Execute(&Flags{Events: true, ShowFormat: "yaml"}); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	if err := cmd.Execute(&Flags{Events: true}); !errors.Is(err, control.ErrNotRunning) {
+		t.Errorf("Execute() error = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestFormatEvent(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		event events.Event
+		want  string
+	}{
+		{events.Event{Type: events.TypePrefix, Time: at, State: events.State{PrefixActive: true}}, "Prefix active"},
+		{events.Event{Type: events.TypeSpellStarted, Time: at, Spell: "build", Sequence: "b"}, "▶️  build (b)"},
+		{events.Event{Type: events.TypeSpellFinished, Time: at, Result: &events.Result{Spell: "build", OK: true, DurationMs: 1500}}, "✅ build (1.5s)"},
+		{events.Event{Type: events.TypeNotification, Time: at, Notification: &events.Notification{Level: "info", Title: "Spell Cast", Held: true}}, "Spell Cast (held)"},
+	}
+	for _, tt := range tests {
+		if got := formatEvent(&tt.event); !strings.Contains(got, tt.want) || !strings.HasPrefix(got, "[12:00:00]") {
+			t.Errorf("formatEvent(%s) = %q, want %q", tt.event.Type, got, tt.want)
+		}
+	}
+}
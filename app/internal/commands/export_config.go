@@ -129,7 +129,8 @@ func (c *ExportConfigCommand) exportYAML(cfg *config.Config, writer io.Writer) e
 	encoder := yaml.NewEncoder(writer)
 	encoder.SetIndent(2)
 
-	if err := encoder.Encode(cfg); err != nil {
+	// Never export literal secrets
+	if err := encoder.Encode(config.Redacted(cfg)); err != nil {
 		return fmt.Errorf("failed to encode configuration: %w", err)
 	}
 
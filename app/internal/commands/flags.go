@@ -14,6 +14,10 @@ type Flags struct {
 	ShowConfigPath bool
 	ShowFormat     string
 	ShowPaths      bool
+	LSP            bool
+	MigrateConfig  bool
+	Lint           bool
+	ConfigDiff     bool
 
 	// Version options
 	VersionFormat string
@@ -22,9 +26,31 @@ type Flags struct {
 	ListSpells bool
 	ListFilter string
 
+	// Spell editing commands
+	AddSpell          string
+	RemoveSpell       string
+	RenameSpell       string
+	RenameTo          string
+	RenameToAction    string
+	SpellAction       string
+	ActionType        string
+	ActionCommand     string
+	ActionDescription string
+	KeepAction        bool
+
 	// Debug commands
-	TestHotkey   bool
-	TestDuration int
+	TestHotkey         bool
+	TestDuration       int
+	TestSound          string
+	NotificationStatus bool
+
+	// Notification commands
+	DND               string
+	Events            bool
+	Notifications     bool
+	NotificationID    int64
+	NotificationLimit int
+	Logs              string
 
 	// Export/Import commands
 	ExportConfig string
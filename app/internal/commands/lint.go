@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/lint"
+	"github.com/SphereStacking/silentcast/internal/version"
+)
+
+// LintCommand runs cross-reference checks on the configuration
+type LintCommand struct {
+	getConfigPath func() string
+	out           io.Writer
+}
+
+// NewLintCommand creates a new lint command
+func NewLintCommand(getConfigPath func() string) Command {
+	return &LintCommand{
+		getConfigPath: getConfigPath,
+		out:           os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *LintCommand) Name() string {
+	return "Lint"
+}
+
+// Description returns the command description
+func (c *LintCommand) Description() string {
+	return "Check spells and grimoire for unused, dangling, and shadowed entries"
+}
+
+// FlagName returns the flag name
+func (c *LintCommand) FlagName() string {
+	return "lint"
+}
+
+// IsActive checks if the command should run
+func (c *LintCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.Lint
+}
+
+// Execute runs the command
+func (c *LintCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	layers, err := config.NewLoader(c.getConfigPath()).LoadLayers()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	findings := lint.NewLinter().Lint(layers)
+
+	switch f.ShowFormat {
+	case "json":
+		err = lint.WriteJSON(c.out, findings)
+	case "sarif":
+		err = lint.WriteSARIF(c.out, findings, version.GetVersionString())
+	case "human", "":
+		err = lint.WriteHuman(c.out, findings)
+	default:
+		return fmt.Errorf("unsupported lint format: %s (use human, json, or sarif)", f.ShowFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write lint report: %w", err)
+	}
+
+	// Notes are informational; errors and warnings fail the run so CI can gate on it
+	if summary := lint.Summarize(findings); summary.Errors+summary.Warnings > 0 {
+		return fmt.Errorf("lint found %d error(s) and %d warning(s)", summary.Errors, summary.Warnings)
+	}
+	return nil
+}
+
+// Group returns the command group
+func (c *LintCommand) Group() string {
+	return "config"
+}
+
+// HasOptions returns if this command has additional options
+func (c *LintCommand) HasOptions() bool {
+	return true
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		format     string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:       "clean config",
+			config:     "spells:\n  s: status\ngrimoire:\n  status:\n    type: url\n    command: https://example.com\n",
+			wantOutput: "No lint problems",
+		},
+		{
+			name:       "dangling spell in human format",
+			config:     "spells:\n  s: stauts\ngrimoire:\n  status:\n    type: url\n    command: https://example.com\n",
+			wantErr:    true,
+			wantOutput: "did you mean 'status'",
+		},
+		{
+			name:       "sarif format",
+			config:     "spells:\n  s: missing\n",
+			format:     "sarif",
+			wantErr:    true,
+			wantOutput: `"ruleId": "dangling-spell"`,
+		},
+		{
+			name:    "unsupported format",
+			config:  "spells:\n  s: status\n",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "spellbook.yml"), []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			var out bytes.Buffer
+			cmd := &LintCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+
+			err := cmd.Execute(&Flags{Lint: true, ShowFormat: tt.format})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output missing %q:\n%s", tt.wantOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestLintCommand_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := "spells:\n  s: status\ngrimoire:\n  status:\n    type: url\n    command: https://example.com\n  spare:\n    type: url\n    command: https://example.org\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "spellbook.yml"), []byte(config), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var out bytes.Buffer
+	cmd := &LintCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+	if err := cmd.Execute(&Flags{Lint: true, ShowFormat: "json"}); err == nil {
+		t.Error("Execute() expected error for unused action warning")
+	}
+
+	var report struct {
+		Findings []struct {
+			Rule string `json:"rule"`
+			Line int    `json:"line"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Rule != "unused-action" || report.Findings[0].Line != 7 {
+		t.Errorf("unexpected findings: %+v", report.Findings)
+	}
+}
+
+func TestLintCommand_Metadata(t *testing.T) {
+	cmd := NewLintCommand(func() string { return "" })
+
+	if cmd.FlagName() != "lint" {
+		t.Errorf("FlagName() = %v, want lint", cmd.FlagName())
+	}
+	if cmd.Group() != "config" {
+		t.Errorf("Group() = %v, want config", cmd.Group())
+	}
+	if !cmd.IsActive(&Flags{Lint: true}) || cmd.IsActive(&Flags{}) {
+		t.Error("IsActive() returned unexpected result")
+	}
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/output"
+)
+
+// LogsCommand shows the output log of the latest run of a spell
+type LogsCommand struct {
+	getConfigPath func() string
+	out           io.Writer
+}
+
+// NewLogsCommand creates a new logs command
+func NewLogsCommand(getConfigPath func() string) Command {
+	return &LogsCommand{
+		getConfigPath: getConfigPath,
+		out:           os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *LogsCommand) Name() string {
+	return "Logs"
+}
+
+// Description returns the command description
+func (c *LogsCommand) Description() string {
+	return "Show the output log of the latest run of a spell (key sequence or grimoire name)"
+}
+
+// FlagName returns the flag name
+func (c *LogsCommand) FlagName() string {
+	return "logs"
+}
+
+// IsActive checks if the command should run
+func (c *LogsCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.Logs != ""
+}
+
+// Execute runs the command
+func (c *LogsCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	configPath := c.getConfigPath()
+	name := f.Logs
+	cfg, err := config.NewLoader(configPath).Load()
+	if err == nil {
+		// Logs are kept per grimoire action, which a key sequence refers to
+		if action, ok := cfg.Shortcuts[name]; ok {
+			name = action
+		}
+	}
+
+	path, err := output.LatestRunLog(filepath.Join(configPath, output.RunLogDir), name)
+	if err != nil {
+		if cfg != nil && !cfg.ExecutionLogs.Enabled {
+			return fmt.Errorf("%w (set execution_logs.enabled: true in spellbook.yml)", err)
+		}
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Fprintf(c.out, "📄 %s\n\n", path)
+	_, err = io.Copy(c.out, file)
+	return err
+}
+
+// Group returns the command group
+func (c *LogsCommand) Group() string {
+	return "utility"
+}
+
+// HasOptions returns if this command has additional options
+func (c *LogsCommand) HasOptions() bool {
+	return false
+}
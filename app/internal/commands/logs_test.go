@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/output"
+)
+
+func TestLogsCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	spellbook := "spells:\n  b: build\ngrimoire:\n  build:\n    type: script\n    command: make build\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, config.ConfigName+".yml"), []byte(spellbook), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	runLogs := output.NewRunLogs(filepath.Join(tmpDir, output.RunLogDir))
+	runLogs.Configure(config.ExecutionLogsConfig{Enabled: true})
+	for _, line := range []string{"first run\n", "second run\n"} {
+		log, err := runLogs.Create("build", "make build")
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		_, _ = log.Write([]byte(line))
+		if err := log.Finish(0, nil); err != nil {
+			t.Fatalf("Finish() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		flags       Flags
+		wantErr     string
+		wantOutput  []string
+		avoidOutput []string
+	}{
+		{
+			name:        "by grimoire name",
+			flags:       Flags{Logs: "build"},
+			wantOutput:  []string{"# spell: build", "second run", "# finished: exit 0"},
+			avoidOutput: []string{"first run"},
+		},
+		{
+			name:       "by key sequence",
+			flags:      Flags{Logs: "b"},
+			wantOutput: []string{"second run"},
+		},
+		{
+			name:    "no logs, hinting that logging is off",
+			flags:   Flags{Logs: "deploy"},
+			wantErr: "execution_logs.enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := &LogsCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+
+			if !cmd.IsActive(&tt.flags) {
+				t.Fatal("Expected command to be active")
+			}
+			err := cmd.Execute(&tt.flags)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("Output %q does not contain %q", out.String(), want)
+				}
+			}
+			for _, avoid := range tt.avoidOutput {
+				if strings.Contains(out.String(), avoid) {
+					t.Errorf("Output %q should not contain %q", out.String(), avoid)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SphereStacking/silentcast/internal/lsp"
+)
+
+// LSPCommand runs a language server for spellbook files over stdio
+type LSPCommand struct {
+	version string
+	in      io.Reader
+	out     io.Writer
+}
+
+// NewLSPCommand creates a new language server command
+func NewLSPCommand(version string) Command {
+	return &LSPCommand{
+		version: version,
+		in:      os.Stdin,
+		out:     os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *LSPCommand) Name() string {
+	return "LSP"
+}
+
+// Description returns the command description
+func (c *LSPCommand) Description() string {
+	return "Run a language server for spellbook files over stdio"
+}
+
+// FlagName returns the flag name
+func (c *LSPCommand) FlagName() string {
+	return "lsp"
+}
+
+// IsActive checks if the command should run
+func (c *LSPCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.LSP
+}
+
+// Execute runs the command
+func (c *LSPCommand) Execute(flags interface{}) error {
+	// stdout carries the protocol, so nothing else may be printed there
+	server := lsp.NewServer(c.in, c.out, c.version)
+	if err := server.Run(context.Background()); err != nil {
+		return fmt.Errorf("language server stopped: %w", err)
+	}
+	return nil
+}
+
+// Group returns the command group
+func (c *LSPCommand) Group() string {
+	return "config"
+}
+
+// HasOptions returns if this command has additional options
+func (c *LSPCommand) HasOptions() bool {
+	return false
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLSPCommand(t *testing.T) {
+	cmd := NewLSPCommand("1.2.3")
+
+	if cmd.Name() != "LSP" {
+		t.Errorf("Name() = %v, want %v", cmd.Name(), "LSP")
+	}
+	if cmd.FlagName() != "lsp" {
+		t.Errorf("FlagName() = %v, want %v", cmd.FlagName(), "lsp")
+	}
+	if cmd.Group() != "config" {
+		t.Errorf("Group() = %v, want %v", cmd.Group(), "config")
+	}
+	if cmd.HasOptions() {
+		t.Error("HasOptions() = true, want false")
+	}
+
+	if !cmd.IsActive(&Flags{LSP: true}) {
+		t.Error("IsActive() = false with LSP flag set")
+	}
+	if cmd.IsActive(&Flags{}) {
+		t.Error("IsActive() = true without LSP flag")
+	}
+	if cmd.IsActive("invalid") {
+		t.Error("IsActive() = true for invalid flags type")
+	}
+}
+
+func TestLSPCommand_Execute(t *testing.T) {
+	frame := func(body string) string {
+		return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+
+	input := frame(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`) +
+		frame(`{"jsonrpc":"2.0","id":2,"method":"shutdown"}`) +
+		frame(`{"jsonrpc":"2.0","method":"exit"}`)
+
+	var out bytes.Buffer
+	cmd := &LSPCommand{version: "1.2.3", in: strings.NewReader(input), out: &out}
+
+	if err := cmd.Execute(&Flags{LSP: true}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"version":"1.2.3"`) {
+		t.Errorf("initialize response missing server version: %s", out.String())
+	}
+}
+
+func TestLSPCommand_ExecuteWithoutShutdown(t *testing.T) {
+	cmd := &LSPCommand{version: "1.2.3", in: strings.NewReader(""), out: &bytes.Buffer{}}
+
+	if err := cmd.Execute(&Flags{LSP: true}); err == nil {
+		t.Error("Execute() expected error when the client disconnects without shutdown")
+	}
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// MigrateConfigCommand upgrades spellbook files to the current format version
+type MigrateConfigCommand struct {
+	getConfigPath func() string
+}
+
+// NewMigrateConfigCommand creates a new migrate config command
+func NewMigrateConfigCommand(getConfigPath func() string) Command {
+	return &MigrateConfigCommand{
+		getConfigPath: getConfigPath,
+	}
+}
+
+// Name returns the command name
+func (c *MigrateConfigCommand) Name() string {
+	return "Migrate Config"
+}
+
+// Description returns the command description
+func (c *MigrateConfigCommand) Description() string {
+	return "Upgrade spellbook files to the current format version (use -dry-run to preview)"
+}
+
+// FlagName returns the flag name
+func (c *MigrateConfigCommand) FlagName() string {
+	return "migrate-config"
+}
+
+// IsActive checks if the command should run
+func (c *MigrateConfigCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.MigrateConfig
+}
+
+// Execute runs the command
+func (c *MigrateConfigCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	configDir := c.getConfigPath()
+	files := []string{
+		filepath.Join(configDir, config.ConfigName+".yml"),
+		filepath.Join(configDir, config.GetPlatformResolver().GetPlatformConfigFile()),
+	}
+
+	found := false
+	migrated := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		found = true
+
+		result, err := config.Migrate(data)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", path, err)
+		}
+
+		if !result.Changed() {
+			fmt.Printf("✅ %s is up to date (version %d)\n", filepath.Base(path), result.ToVersion)
+			continue
+		}
+
+		fmt.Printf("🔄 %s: version %d → %d\n", filepath.Base(path), result.FromVersion, result.ToVersion)
+		for _, description := range result.Applied {
+			fmt.Printf("   • %s\n", description)
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(data)),
+			B:        difflib.SplitLines(string(result.Data)),
+			FromFile: path,
+			ToFile:   path + " (migrated)",
+			Context:  3,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build diff for %s: %w", path, err)
+		}
+		fmt.Println()
+		fmt.Print(diff)
+		fmt.Println()
+
+		if f.DryRun {
+			continue
+		}
+
+		backupPath, err := config.WriteFileWithBackup(path, result.Data)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Printf("   • Backed up original to: %s\n", filepath.Base(backupPath))
+		migrated++
+	}
+
+	if !found {
+		return fmt.Errorf("no configuration files found in %s", configDir)
+	}
+
+	switch {
+	case f.DryRun:
+		fmt.Println("ℹ️  Dry run: no files were changed")
+	case migrated > 0:
+		fmt.Printf("✅ Migrated %d file(s) to version %d\n", migrated, config.SchemaVersion)
+	}
+
+	return nil
+}
+
+// Group returns the command group
+func (c *MigrateConfigCommand) Group() string {
+	return "config"
+}
+
+// HasOptions returns if this command has additional options
+func (c *MigrateConfigCommand) HasOptions() bool {
+	return true
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacySpellbook = `# Team spellbook
+spells:
+  g: github # open GitHub
+
+grimoire:
+  github:
+    type: url
+    url: "https://github.com"
+`
+
+func TestMigrateConfigCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		dryRun      bool
+		wantErr     bool
+		wantContent []string
+		wantBackup  bool
+	}{
+		{
+			name:        "migrates legacy spellbook",
+			content:     legacySpellbook,
+			wantContent: []string{"version: 1", "# Team spellbook", "# open GitHub", `command: "https://github.com"`},
+			wantBackup:  true,
+		},
+		{
+			name:        "dry run leaves file untouched",
+			content:     legacySpellbook,
+			dryRun:      true,
+			wantContent: []string{legacySpellbook},
+		},
+		{
+			name:        "current version is left alone",
+			content:     "version: 1\nspells:\n  e: editor\n",
+			wantContent: []string{"version: 1\nspells:\n  e: editor\n"},
+		},
+		{
+			name:    "newer version is rejected",
+			content: "version: 99\nspells:\n  e: editor\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "spellbook.yml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cmd := NewMigrateConfigCommand(func() string { return tmpDir })
+			err := cmd.Execute(&Flags{MigrateConfig: true, DryRun: tt.dryRun})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatalf("Failed to read config: %v", err)
+			}
+			for _, want := range tt.wantContent {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("config missing %q:\n%s", want, data)
+				}
+			}
+
+			backups, _ := filepath.Glob(configPath + ".backup.*")
+			if tt.wantBackup != (len(backups) == 1) {
+				t.Errorf("backups = %v, wantBackup %v", backups, tt.wantBackup)
+			}
+		})
+	}
+}
+
+func TestMigrateConfigCommand_NoConfig(t *testing.T) {
+	cmd := NewMigrateConfigCommand(func() string { return t.TempDir() })
+	if err := cmd.Execute(&Flags{MigrateConfig: true}); err == nil {
+		t.Error("Execute() expected error without configuration files")
+	}
+}
+
+func TestMigrateConfigCommand_Metadata(t *testing.T) {
+	cmd := NewMigrateConfigCommand(func() string { return "" })
+
+	if cmd.FlagName() != "migrate-config" {
+		t.Errorf("FlagName() = %v, want migrate-config", cmd.FlagName())
+	}
+	if cmd.Group() != "config" {
+		t.Errorf("Group() = %v, want config", cmd.Group())
+	}
+	if !cmd.IsActive(&Flags{MigrateConfig: true}) || cmd.IsActive(&Flags{}) {
+		t.Error("IsActive() returned unexpected result")
+	}
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/notify"
+)
+
+// notificationStatusStaleAfter is how old a running daemon's status may be before it is
+// reported as stale. The daemon refreshes the file every few seconds.
+const notificationStatusStaleAfter = 30 * time.Second
+
+// NotificationStatusCommand shows notification queue metrics reported by the daemon
+type NotificationStatusCommand struct {
+	getConfigPath func() string
+	out           io.Writer
+	now           func() time.Time
+}
+
+// NewNotificationStatusCommand creates a new notification status command
+func NewNotificationStatusCommand(getConfigPath func() string) Command {
+	return &NotificationStatusCommand{
+		getConfigPath: getConfigPath,
+		out:           os.Stdout,
+		now:           time.Now,
+	}
+}
+
+// Name returns the command name
+func (c *NotificationStatusCommand) Name() string {
+	return "Notification Status"
+}
+
+// Description returns the command description
+func (c *NotificationStatusCommand) Description() string {
+	return "Show notification queue metrics of the running daemon"
+}
+
+// FlagName returns the flag name
+func (c *NotificationStatusCommand) FlagName() string {
+	return "notification-status"
+}
+
+// IsActive checks if the command should run
+func (c *NotificationStatusCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.NotificationStatus
+}
+
+// Execute runs the command
+func (c *NotificationStatusCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	path := filepath.Join(c.getConfigPath(), notify.QueueStatusFile)
+	status, err := notify.ReadQueueStatus(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no notification status found at %s; is the daemon running?", path)
+		}
+		return err
+	}
+
+	switch f.ShowFormat {
+	case "json":
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(status)
+	case "human", "":
+		c.writeHuman(status)
+		return nil
+	default:
+		return fmt.Errorf("unsupported notification-status format: %s (use human or json)", f.ShowFormat)
+	}
+}
+
+// writeHuman prints the status as a table
+func (c *NotificationStatusCommand) writeHuman(status *notify.QueueStatus) {
+	age := c.now().Sub(status.UpdatedAt).Round(time.Second)
+
+	fmt.Fprintln(c.out, "🔔 Notification Queue")
+	switch {
+	case !status.Running:
+		fmt.Fprintf(c.out, "   State:     stopped (drained on shutdown %s ago)\n", age)
+	case age > notificationStatusStaleAfter:
+		fmt.Fprintf(c.out, "   State:     ⚠️  stale (last report %s ago; the daemon may have exited)\n", age)
+	default:
+		fmt.Fprintf(c.out, "   State:     running (pid %d)\n", status.PID)
+	}
+	fmt.Fprintf(c.out, "   Delivered: %d\n", status.Processed)
+	fmt.Fprintf(c.out, "   Failed:    %d\n", status.Failed)
+	fmt.Fprintf(c.out, "   Dropped:   %d\n", status.Dropped)
+	fmt.Fprintf(c.out, "   Pending:   %d\n", status.Pending)
+	fmt.Fprintf(c.out, "   Updated:   %s\n", status.UpdatedAt.Format(time.RFC3339))
+}
+
+// Group returns the command group
+func (c *NotificationStatusCommand) Group() string {
+	return "debug"
+}
+
+// HasOptions returns if this command has additional options
+func (c *NotificationStatusCommand) HasOptions() bool {
+	return true
+}
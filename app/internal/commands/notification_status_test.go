@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/notify"
+)
+
+func TestNotificationStatusCommand(t *testing.T) {
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		status     *notify.QueueStatus
+		format     string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:    "no status file",
+			wantErr: true,
+		},
+		{
+			name:       "running daemon",
+			status:     &notify.QueueStatus{PID: 42, Running: true, Processed: 7, Failed: 1, UpdatedAt: now.Add(-2 * time.Second)},
+			wantOutput: "running (pid 42)",
+		},
+		{
+			name:       "stale status",
+			status:     &notify.QueueStatus{PID: 42, Running: true, UpdatedAt: now.Add(-5 * time.Minute)},
+			wantOutput: "stale",
+		},
+		{
+			name:       "stopped daemon",
+			status:     &notify.QueueStatus{PID: 42, Processed: 3, UpdatedAt: now.Add(-time.Hour)},
+			wantOutput: "stopped",
+		},
+		{
+			name:       "json format",
+			status:     &notify.QueueStatus{PID: 42, Running: true, Dropped: 2, UpdatedAt: now},
+			format:     "json",
+			wantOutput: `"dropped": 2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if tt.status != nil {
+				if err := notify.WriteQueueStatus(filepath.Join(tmpDir, notify.QueueStatusFile), *tt.status); err != nil {
+					t.Fatalf("Failed to write status: %v", err)
+				}
+			}
+
+			var out bytes.Buffer
+			cmd := &NotificationStatusCommand{
+				getConfigPath: func() string { return tmpDir },
+				out:           &out,
+				now:           func() time.Time { return now },
+			}
+
+			err := cmd.Execute(&Flags{NotificationStatus: true, ShowFormat: tt.format})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output missing %q:\n%s", tt.wantOutput, out.String())
+			}
+			if tt.format == "json" && !json.Valid(out.Bytes()) {
+				t.Errorf("invalid JSON output:\n%s", out.String())
+			}
+		})
+	}
+}
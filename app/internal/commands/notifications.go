@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/notify"
+)
+
+// defaultNotificationsLimit is how many notifications are listed when --limit is not set
+const defaultNotificationsLimit = 20
+
+// NotificationsCommand lists, searches and shows past notifications from the history
+type NotificationsCommand struct {
+	getConfigPath func() string
+	out           io.Writer
+}
+
+// NewNotificationsCommand creates a new notifications command
+func NewNotificationsCommand(getConfigPath func() string) Command {
+	return &NotificationsCommand{
+		getConfigPath: getConfigPath,
+		out:           os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *NotificationsCommand) Name() string {
+	return "Notifications"
+}
+
+// Description returns the command description
+func (c *NotificationsCommand) Description() string {
+	return "List past notifications, or show one with its output (-notification=<id>)"
+}
+
+// FlagName returns the flag name
+func (c *NotificationsCommand) FlagName() string {
+	return "notifications"
+}
+
+// IsActive checks if the command should run
+func (c *NotificationsCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.Notifications || f.NotificationID > 0
+}
+
+// Execute runs the command
+func (c *NotificationsCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+	if f.ShowFormat != "" && f.ShowFormat != "human" && f.ShowFormat != "json" {
+		return fmt.Errorf("unsupported notifications format: %s (use human or json)", f.ShowFormat)
+	}
+
+	entries, err := notify.ReadHistory(filepath.Join(c.getConfigPath(), notify.HistoryFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if f.NotificationID > 0 {
+		for _, entry := range entries {
+			if entry.ID == f.NotificationID {
+				return c.show(entry, f.ShowFormat)
+			}
+		}
+		return fmt.Errorf("notification #%d not found in history", f.NotificationID)
+	}
+
+	var matched []notify.HistoryEntry
+	for _, entry := range entries {
+		if entry.Matches(f.ListFilter) {
+			matched = append(matched, entry)
+		}
+	}
+
+	limit := f.NotificationLimit
+	if limit <= 0 {
+		limit = defaultNotificationsLimit
+	}
+	shown := matched
+	if len(shown) > limit {
+		shown = shown[len(shown)-limit:]
+	}
+
+	if f.ShowFormat == "json" {
+		if shown == nil {
+			shown = []notify.HistoryEntry{}
+		}
+		return c.writeJSON(shown)
+	}
+	c.writeList(shown, len(matched), f.ListFilter)
+	return nil
+}
+
+// writeList prints one line per notification, oldest first
+func (c *NotificationsCommand) writeList(entries []notify.HistoryEntry, total int, filter string) {
+	if total == 0 {
+		if filter != "" {
+			fmt.Fprintf(c.out, "No notifications match %q\n", filter)
+		} else {
+			fmt.Fprintln(c.out, "No notifications recorded yet")
+		}
+		return
+	}
+
+	fmt.Fprintf(c.out, "🔔 Notifications (showing %d of %d)\n", len(entries), total)
+	for _, entry := range entries {
+		line := fmt.Sprintf("  #%-5d %s %-7s %s", entry.ID, entry.Time.Local().Format(time.DateTime), entry.Level, entry.Title)
+		if entry.Message != "" {
+			line += ": " + firstLine(entry.Message)
+		}
+		if entry.Spell != "" {
+			line += fmt.Sprintf(" [%s]", entry.Spell)
+		}
+		if entry.Output != "" {
+			line += " 📄"
+		}
+		if entry.Held {
+			line += " 🔕"
+		}
+		fmt.Fprintln(c.out, line)
+	}
+	fmt.Fprintln(c.out)
+	fmt.Fprintln(c.out, "📄 has output, 🔕 held by do-not-disturb. Use -notification=<id> for details.")
+}
+
+// show prints a single notification with its full output
+func (c *NotificationsCommand) show(entry notify.HistoryEntry, format string) error {
+	if format == "json" {
+		return c.writeJSON(entry)
+	}
+
+	fmt.Fprintf(c.out, "🔔 Notification #%d\n", entry.ID)
+	fmt.Fprintf(c.out, "   Time:    %s\n", entry.Time.Local().Format(time.RFC3339))
+	fmt.Fprintf(c.out, "   Level:   %s\n", entry.Level)
+	fmt.Fprintf(c.out, "   Title:   %s\n", entry.Title)
+	if entry.Spell != "" {
+		fmt.Fprintf(c.out, "   Spell:   %s\n", entry.Spell)
+	}
+	if entry.ExitCode != nil {
+		fmt.Fprintf(c.out, "   Exit:    %d\n", *entry.ExitCode)
+	}
+	if entry.Held {
+		fmt.Fprintln(c.out, "   Held by do-not-disturb")
+	}
+	if entry.Message != "" {
+		fmt.Fprintf(c.out, "\n%s\n", entry.Message)
+	}
+	if entry.Output != "" {
+		fmt.Fprintln(c.out, "\n--- output ---")
+		fmt.Fprint(c.out, entry.Output)
+		if !strings.HasSuffix(entry.Output, "\n") {
+			fmt.Fprintln(c.out)
+		}
+		if entry.TruncatedBytes > 0 {
+			fmt.Fprintf(c.out, "... (%d bytes truncated)\n", entry.TruncatedBytes)
+		}
+	}
+	return nil
+}
+
+// writeJSON prints v as indented JSON
+func (c *NotificationsCommand) writeJSON(v interface{}) error {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// firstLine returns the first line of s, marking that more follows
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " …"
+	}
+	return s
+}
+
+// Group returns the command group
+func (c *NotificationsCommand) Group() string {
+	return "utility"
+}
+
+// HasOptions returns if this command has additional options
+func (c *NotificationsCommand) HasOptions() bool {
+	return true
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/notify"
+)
+
+func TestNotificationsCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	history := notify.NewHistory(filepath.Join(tmpDir, notify.HistoryFile), 100)
+	exitCode := 1
+	for _, entry := range []notify.HistoryEntry{
+		{Level: "info", Title: "Spell Cast", Message: "editor", Spell: "editor"},
+		{Level: "error", Title: "Spell Failed", Message: "exit status 1", Spell: "deploy", Output: "line one\nconnection refused\n", ExitCode: &exitCode},
+		{Level: "success", Title: "Configuration Reloaded", Held: true},
+	} {
+		if err := history.Record(entry); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		flags       Flags
+		wantErr     bool
+		wantOutput  []string
+		avoidOutput []string
+	}{
+		{
+			name:       "list all",
+			flags:      Flags{Notifications: true},
+			wantOutput: []string{"showing 3 of 3", "#1", "Spell Failed: exit status 1 [deploy] 📄", "Configuration Reloaded 🔕"},
+		},
+		{
+			name:        "search output",
+			flags:       Flags{Notifications: true, ListFilter: "refused"},
+			wantOutput:  []string{"showing 1 of 1", "#2"},
+			avoidOutput: []string{"#1 "},
+		},
+		{
+			name:        "limit keeps newest",
+			flags:       Flags{Notifications: true, NotificationLimit: 1},
+			wantOutput:  []string{"showing 1 of 3", "#3"},
+			avoidOutput: []string{"#2 "},
+		},
+		{
+			name:       "no match",
+			flags:      Flags{Notifications: true, ListFilter: "nothing"},
+			wantOutput: []string{`No notifications match "nothing"`},
+		},
+		{
+			name:       "show full output",
+			flags:      Flags{NotificationID: 2},
+			wantOutput: []string{"Notification #2", "Spell:   deploy", "Exit:    1", "--- output ---", "connection refused"},
+		},
+		{
+			name:    "show missing",
+			flags:   Flags{NotificationID: 42},
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			flags:   Flags{Notifications: true, ShowFormat: "yaml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := &NotificationsCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+
+			if !cmd.IsActive(&tt.flags) {
+				t.Fatal("Expected command to be active")
+			}
+			err := cmd.Execute(&tt.flags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("Output %q does not contain %q", out.String(), want)
+				}
+			}
+			for _, avoid := range tt.avoidOutput {
+				if strings.Contains(out.String(), avoid) {
+					t.Errorf("Output %q should not contain %q", out.String(), avoid)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationsCommand_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	var out bytes.Buffer
+	cmd := &NotificationsCommand{getConfigPath: func() string { return tmpDir }, out: &out}
+
+	// An empty history is an empty list rather than an error
+	if err := cmd.Execute(&Flags{Notifications: true, ShowFormat: "json"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var entries []notify.HistoryEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil || entries == nil || len(entries) != 0 {
+		t.Errorf("Output %q, want empty JSON list (err %v)", out.String(), err)
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// RemoveSpellCommand removes a spell and, when no longer used, its grimoire action
+type RemoveSpellCommand struct {
+	getConfigPath func() string
+}
+
+// NewRemoveSpellCommand creates a new remove spell command
+func NewRemoveSpellCommand(getConfigPath func() string) Command {
+	return &RemoveSpellCommand{
+		getConfigPath: getConfigPath,
+	}
+}
+
+// Name returns the command name
+func (c *RemoveSpellCommand) Name() string {
+	return "Remove Spell"
+}
+
+// Description returns the command description
+func (c *RemoveSpellCommand) Description() string {
+	return "Remove a spell and its grimoire action if no other spell uses it"
+}
+
+// FlagName returns the flag name
+func (c *RemoveSpellCommand) FlagName() string {
+	return "remove-spell"
+}
+
+// IsActive checks if the command should run
+func (c *RemoveSpellCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.RemoveSpell != ""
+}
+
+// Execute runs the command
+func (c *RemoveSpellCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	configDir := c.getConfigPath()
+	editor, err := config.NewEditor(spellbookFile(configDir))
+	if err != nil {
+		return fmt.Errorf("failed to open configuration: %w", err)
+	}
+
+	sequence := f.RemoveSpell
+	actionName, removed := editor.RemoveSpell(sequence)
+	if !removed {
+		return fmt.Errorf("spell '%s' not found in %s", sequence, editor.Path())
+	}
+
+	actionRemoved := false
+	if !f.KeepAction && !c.actionInUse(configDir, editor, sequence, actionName) {
+		actionRemoved = editor.RemoveAction(actionName)
+	}
+
+	if err := saveSpellbook(editor); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Removed spell '%s'\n", sequence)
+	if actionRemoved {
+		fmt.Printf("   • Removed unused grimoire action: %s\n", actionName)
+	}
+	return nil
+}
+
+// actionInUse reports whether any other spell, in this file or the platform file, uses the action
+func (c *RemoveSpellCommand) actionInUse(configDir string, editor *config.Editor, removedSequence, actionName string) bool {
+	if len(editor.SpellsForAction(actionName)) > 0 {
+		return true
+	}
+	if cfg := mergedSpellbook(configDir); cfg != nil {
+		for seq, name := range cfg.Shortcuts {
+			if seq != removedSequence && name == actionName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Group returns the command group
+func (c *RemoveSpellCommand) Group() string {
+	return "spell"
+}
+
+// HasOptions returns if this command has additional options
+func (c *RemoveSpellCommand) HasOptions() bool {
+	return true
+}
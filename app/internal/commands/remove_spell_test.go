@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestRemoveSpellCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         string
+		platformConfig string
+		flags          *Flags
+		wantErr        string
+		wantContent    []string
+		wantMissing    []string
+	}{
+		{
+			name:        "removes unused action",
+			config:      spellEditTestConfig,
+			flags:       &Flags{RemoveSpell: "g,s"},
+			wantContent: []string{"e: editor # main editor"},
+			wantMissing: []string{"g,s", "git_status"},
+		},
+		{
+			name:        "keep action",
+			config:      spellEditTestConfig,
+			flags:       &Flags{RemoveSpell: "g,s", KeepAction: true},
+			wantContent: []string{"git_status:"},
+			wantMissing: []string{"g,s"},
+		},
+		{
+			name:        "action still used by another spell",
+			config:      strings.Replace(spellEditTestConfig, "  \"g,s\": git_status\n", "  \"g,s\": git_status\n  \"g,t\": git_status\n", 1),
+			flags:       &Flags{RemoveSpell: "g,s"},
+			wantContent: []string{`"g,t": git_status`, "git_status:"},
+		},
+		{
+			name:           "action used by platform spell",
+			config:         spellEditTestConfig,
+			platformConfig: "spells:\n  s: git_status\n",
+			flags:          &Flags{RemoveSpell: "g,s"},
+			wantContent:    []string{"git_status:"},
+		},
+		{
+			name:    "unknown spell",
+			config:  spellEditTestConfig,
+			flags:   &Flags{RemoveSpell: "z"},
+			wantErr: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := writeSpellEditConfig(t, tt.config)
+			if tt.platformConfig != "" {
+				platformFile := filepath.Join(tmpDir, config.GetPlatformResolver().GetPlatformConfigFile())
+				if err := os.WriteFile(platformFile, []byte(tt.platformConfig), 0o600); err != nil {
+					t.Fatalf("Failed to write platform config: %v", err)
+				}
+			}
+			cmd := NewRemoveSpellCommand(func() string { return tmpDir })
+
+			err := cmd.Execute(tt.flags)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			content := readSpellEditConfig(t, tmpDir)
+			for _, want := range tt.wantContent {
+				if !strings.Contains(content, want) {
+					t.Errorf("config missing %q:\n%s", want, content)
+				}
+			}
+			for _, unwanted := range tt.wantMissing {
+				if strings.Contains(content, unwanted) {
+					t.Errorf("config still contains %q:\n%s", unwanted, content)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveSpellCommand_Metadata(t *testing.T) {
+	cmd := NewRemoveSpellCommand(func() string { return "" })
+
+	if cmd.FlagName() != "remove-spell" {
+		t.Errorf("FlagName() = %v, want remove-spell", cmd.FlagName())
+	}
+	if !cmd.IsActive(&Flags{RemoveSpell: "x"}) || cmd.IsActive(&Flags{}) {
+		t.Error("IsActive() returned unexpected result")
+	}
+}
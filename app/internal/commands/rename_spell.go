@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// RenameSpellCommand changes the key sequence of an existing spell or the
+// name of the grimoire action it is bound to
+type RenameSpellCommand struct {
+	getConfigPath func() string
+}
+
+// NewRenameSpellCommand creates a new rename spell command
+func NewRenameSpellCommand(getConfigPath func() string) Command {
+	return &RenameSpellCommand{
+		getConfigPath: getConfigPath,
+	}
+}
+
+// Name returns the command name
+func (c *RenameSpellCommand) Name() string {
+	return "Rename Spell"
+}
+
+// Description returns the command description
+func (c *RenameSpellCommand) Description() string {
+	return "Change the key sequence of a spell (-to) or rename its action (-to-action)"
+}
+
+// FlagName returns the flag name
+func (c *RenameSpellCommand) FlagName() string {
+	return "rename-spell"
+}
+
+// IsActive checks if the command should run
+func (c *RenameSpellCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.RenameSpell != ""
+}
+
+// Execute runs the command
+func (c *RenameSpellCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	oldSequence := f.RenameSpell
+	newSequence := f.RenameTo
+	newAction := f.RenameToAction
+	if newSequence == "" && newAction == "" {
+		return fmt.Errorf("--rename-spell requires --to <new sequence> or --to-action <new action name>")
+	}
+
+	configDir := c.getConfigPath()
+	editor, err := config.NewEditor(spellbookFile(configDir))
+	if err != nil {
+		return fmt.Errorf("failed to open configuration: %w", err)
+	}
+
+	actionName, exists := editor.Spell(oldSequence)
+	if !exists {
+		return fmt.Errorf("spell '%s' not found in %s", oldSequence, editor.Path())
+	}
+	if newSequence == "" {
+		newSequence = oldSequence
+	}
+	if newAction == "" {
+		newAction = actionName
+	}
+
+	if newSequence != oldSequence {
+		if err := checkSpellConflicts(configDir, editor, newSequence, newAction, oldSequence); err != nil {
+			return err
+		}
+	}
+
+	// The action goes first, while the file still holds the sequences of the merged config
+	var rebound []string
+	var dangling []string
+	if newAction != actionName {
+		if rebound, dangling, err = renameAction(configDir, editor, actionName, newAction); err != nil {
+			return err
+		}
+	}
+
+	if newSequence != oldSequence {
+		if err := editor.RenameSpell(oldSequence, newSequence); err != nil {
+			return err
+		}
+	}
+
+	if err := saveSpellbook(editor); err != nil {
+		return err
+	}
+
+	if newSequence != oldSequence {
+		fmt.Printf("✅ Renamed spell '%s' → '%s' (%s)\n", oldSequence, newSequence, newAction)
+	}
+	if newAction != actionName {
+		fmt.Printf("✅ Renamed action '%s' → '%s' (spells: %s)\n", actionName, newAction, strings.Join(rebound, ", "))
+	}
+	if len(dangling) > 0 {
+		fmt.Printf("\n⚠️  Warning: spells in other files still reference '%s': %s\n", actionName, strings.Join(dangling, ", "))
+	}
+	return nil
+}
+
+// renameAction renames a grimoire action of the base spellbook and rebinds
+// its spells. The new name must not be taken in the merged configuration.
+// It returns the rebound sequences and those of other files that still use
+// the old name.
+func renameAction(configDir string, editor *config.Editor, oldName, newName string) ([]string, []string, error) {
+	if !editor.HasAction(oldName) {
+		return nil, nil, fmt.Errorf("action '%s' is not defined in %s", oldName, editor.Path())
+	}
+
+	cfg := mergedSpellbook(configDir)
+	if cfg != nil {
+		if _, exists := cfg.Actions[newName]; exists {
+			return nil, nil, fmt.Errorf("action '%s' already exists", newName)
+		}
+	}
+
+	rebound, err := editor.RenameAction(oldName, newName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dangling []string
+	if cfg != nil {
+		spells := editor.Spells()
+		for seq, name := range cfg.Shortcuts {
+			if _, inFile := spells[seq]; !inFile && name == oldName {
+				dangling = append(dangling, seq)
+			}
+		}
+		sort.Strings(dangling)
+	}
+	return rebound, dangling, nil
+}
+
+// Group returns the command group
+func (c *RenameSpellCommand) Group() string {
+	return "spell"
+}
+
+// HasOptions returns if this command has additional options
+func (c *RenameSpellCommand) HasOptions() bool {
+	return true
+}
@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestRenameSpellCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       *Flags
+		wantErr     string
+		wantContent string
+	}{
+		{
+			name:        "rename keeps comment",
+			flags:       &Flags{RenameSpell: "e", RenameTo: "e,d"},
+			wantContent: `"e,d": editor # main editor`,
+		},
+		{
+			name:    "missing target",
+			flags:   &Flags{RenameSpell: "e"},
+			wantErr: "requires --to",
+		},
+		{
+			name:        "rename action rebinds spells",
+			flags:       &Flags{RenameSpell: "g,s", RenameToAction: "git_st"},
+			wantContent: "\"g,s\": git_st\n",
+		},
+		{
+			name:        "rename sequence and action",
+			flags:       &Flags{RenameSpell: "e", RenameTo: "e,d", RenameToAction: "vi"},
+			wantContent: "\"e,d\": vi # main editor",
+		},
+		{
+			name:    "action name taken",
+			flags:   &Flags{RenameSpell: "e", RenameToAction: "git_status"},
+			wantErr: "already exists",
+		},
+		{
+			name:    "unknown spell",
+			flags:   &Flags{RenameSpell: "z", RenameTo: "y"},
+			wantErr: "not found",
+		},
+		{
+			name:    "conflicts with other spell",
+			flags:   &Flags{RenameSpell: "e", RenameTo: "g"},
+			wantErr: "conflicts",
+		},
+		{
+			name:    "duplicate of other spell",
+			flags:   &Flags{RenameSpell: "e", RenameTo: "g,s"},
+			wantErr: "already registered",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := writeSpellEditConfig(t, spellEditTestConfig)
+			cmd := NewRenameSpellCommand(func() string { return tmpDir })
+
+			err := cmd.Execute(tt.flags)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				if readSpellEditConfig(t, tmpDir) != spellEditTestConfig {
+					t.Error("config modified despite error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if content := readSpellEditConfig(t, tmpDir); !strings.Contains(content, tt.wantContent) {
+				t.Errorf("config missing %q:\n%s", tt.wantContent, content)
+			}
+		})
+	}
+}
+
+func TestRenameSpellCommand_ActionInOtherFile(t *testing.T) {
+	tmpDir := writeSpellEditConfig(t, spellEditTestConfig)
+	platformFile := filepath.Join(tmpDir, config.GetPlatformResolver().GetPlatformConfigFile())
+	if err := os.WriteFile(platformFile, []byte("spells:\n  \"g,t\": git_status\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write platform config: %v", err)
+	}
+	cmd := NewRenameSpellCommand(func() string { return tmpDir })
+
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := cmd.Execute(&Flags{RenameSpell: "g,s", RenameToAction: "git_st"})
+
+	w.Close()
+	os.Stdout = old
+
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	output := buf.String()
+	if !strings.Contains(output, "still reference 'git_status': g,t") {
+		t.Errorf("missing warning for spells in other files:\n%s", output)
+	}
+
+	// Actions defined outside the base spellbook cannot be renamed from it
+	if err := os.WriteFile(platformFile, []byte("grimoire:\n  pager:\n    type: script\n    command: less\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write platform config: %v", err)
+	}
+	before := readSpellEditConfig(t, tmpDir)
+	if err := cmd.Execute(&Flags{RenameSpell: "e", RenameToAction: "pager"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Execute() error = %v, want action already exists", err)
+	}
+	if readSpellEditConfig(t, tmpDir) != before {
+		t.Error("config modified despite error")
+	}
+}
+
+func TestRenameSpellCommand_Metadata(t *testing.T) {
+	cmd := NewRenameSpellCommand(func() string { return "" })
+
+	if cmd.FlagName() != "rename-spell" {
+		t.Errorf("FlagName() = %v, want rename-spell", cmd.FlagName())
+	}
+	if !cmd.IsActive(&Flags{RenameSpell: "x"}) || cmd.IsActive(&Flags{}) {
+		t.Error("IsActive() returned unexpected result")
+	}
+}
@@ -116,6 +116,9 @@ func (c *ShowConfigCommand) Execute(flags interface{}) error {
 		return err
 	}
 
+	// Never show literal secrets
+	cfg = config.Redacted(cfg)
+
 	// Only show header for human format
 	if f.ShowFormat == "human" || f.ShowFormat == "" {
 		fmt.Println("\n📋 Merged Configuration:")
@@ -157,8 +160,10 @@ func (c *ShowConfigCommand) showConfigJSON(cfg *config.Config) error {
 			"timeout":          cfg.Hotkeys.Timeout.ToDuration().Milliseconds(),
 			"sequence_timeout": cfg.Hotkeys.SequenceTimeout.ToDuration().Milliseconds(),
 		},
-		"spells":   cfg.Shortcuts,
-		"grimoire": cfg.Actions,
+		"spells":       cfg.Shortcuts,
+		"grimoire":     cfg.Actions,
+		"env_file":     cfg.EnvFile,
+		"secrets_file": cfg.SecretsFile,
 		"logger": map[string]interface{}{
 			"level":       cfg.Logger.Level,
 			"file":        cfg.Logger.File,
@@ -232,7 +237,11 @@ func (c *ShowConfigCommand) showConfigHuman(cfg *config.Config) error {
 		action := cfg.Actions[name]
 		fmt.Printf("\n   %s:\n", name)
 		fmt.Printf("      Type: %s\n", action.Type)
-		fmt.Printf("      Command: %s\n", action.Command)
+		if action.ScriptFile != "" {
+			fmt.Printf("      Script File: %s\n", action.ScriptFile)
+		} else {
+			fmt.Printf("      Command: %s\n", action.Command)
+		}
 
 		if len(action.Args) > 0 {
 			fmt.Printf("      Args: %v\n", action.Args)
@@ -240,6 +249,9 @@ func (c *ShowConfigCommand) showConfigHuman(cfg *config.Config) error {
 		if action.WorkingDir != "" {
 			fmt.Printf("      Working Dir: %s\n", action.WorkingDir)
 		}
+		if len(action.EnvFile) > 0 {
+			fmt.Printf("      Env Files: %s\n", strings.Join(action.EnvFile, ", "))
+		}
 		if len(action.Env) > 0 {
 			fmt.Printf("      Environment:\n")
 			for k, v := range action.Env {
@@ -277,6 +289,17 @@ func (c *ShowConfigCommand) showConfigHuman(cfg *config.Config) error {
 		}
 	}
 
+	// Environment settings
+	if len(cfg.EnvFile) > 0 || cfg.SecretsFile != "" {
+		fmt.Println("\n🌍 Environment Settings:")
+		if len(cfg.EnvFile) > 0 {
+			fmt.Printf("   Env Files: %s\n", strings.Join(cfg.EnvFile, ", "))
+		}
+		if cfg.SecretsFile != "" {
+			fmt.Printf("   Secrets File: %s\n", cfg.SecretsFile)
+		}
+	}
+
 	// Logger settings
 	fmt.Println("\n📝 Logger Settings:")
 	fmt.Printf("   Level: %s\n", cfg.Logger.Level)
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/hotkey"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// spellbookFile returns the base spellbook edited by the spell commands
+func spellbookFile(configDir string) string {
+	return filepath.Join(configDir, config.ConfigName+".yml")
+}
+
+// mergedSpellbook loads the merged configuration without validation.
+// It returns nil when no configuration exists yet.
+func mergedSpellbook(configDir string) *config.Config {
+	cfg, err := config.NewLoader(configDir).LoadRaw()
+	if err != nil {
+		logger.Debug("Could not load merged configuration: %v", err)
+		return nil
+	}
+	return cfg
+}
+
+// checkSpellConflicts verifies that a sequence can be bound to an action
+// without clashing with the other spells in the merged configuration.
+// Sequences listed in ignore are left out, e.g. the old name of a renamed spell.
+func checkSpellConflicts(configDir string, editor *config.Editor, sequence, actionName string, ignore ...string) error {
+	spells := editor.Spells()
+	if cfg := mergedSpellbook(configDir); cfg != nil {
+		for seq, name := range cfg.Shortcuts {
+			if _, inFile := spells[seq]; !inFile {
+				spells[seq] = name
+			}
+		}
+	}
+
+	skip := make(map[string]bool, len(ignore))
+	for _, seq := range ignore {
+		skip[seq] = true
+	}
+
+	validator := hotkey.NewValidator()
+	for seq, name := range spells {
+		if skip[seq] {
+			continue
+		}
+		// Existing conflicts are reported by --validate-config, not here
+		if err := validator.Register(seq, name); err != nil {
+			logger.Debug("Skipping conflicting existing spell %s: %v", seq, err)
+		}
+	}
+
+	if err := validator.Validate(sequence, actionName); err != nil {
+		return fmt.Errorf("invalid spell '%s': %w", sequence, err)
+	}
+	return nil
+}
+
+// saveSpellbook writes the edited spellbook and reports where it went
+func saveSpellbook(editor *config.Editor) error {
+	backupPath, err := editor.Save()
+	if err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	fmt.Printf("   • Configuration written to: %s\n", editor.Path())
+	if backupPath != "" {
+		fmt.Printf("   • Backed up existing config to: %s\n", filepath.Base(backupPath))
+	}
+	return nil
+}
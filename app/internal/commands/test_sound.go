@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/sound"
+)
+
+// TestSoundCommand plays the sound cues with the configured player and volume
+type TestSoundCommand struct {
+	getConfigPath func() string
+	cacheDir      string
+	out           io.Writer
+}
+
+// NewTestSoundCommand creates a new test sound command
+func NewTestSoundCommand(getConfigPath func() string) Command {
+	return &TestSoundCommand{
+		getConfigPath: getConfigPath,
+		cacheDir:      sound.DefaultCacheDir(),
+		out:           os.Stdout,
+	}
+}
+
+// Name returns the command name
+func (c *TestSoundCommand) Name() string {
+	return "Test Sound"
+}
+
+// Description returns the command description
+func (c *TestSoundCommand) Description() string {
+	return "Play the sound cue of an event (prefix|success|failure|timeout|all)"
+}
+
+// FlagName returns the flag name
+func (c *TestSoundCommand) FlagName() string {
+	return "test-sound"
+}
+
+// IsActive checks if the command should run
+func (c *TestSoundCommand) IsActive(flags interface{}) bool {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return false
+	}
+	return f.TestSound != ""
+}
+
+// Execute plays the cues. They play even when sound cues are disabled in the
+// configuration, so that a player and volume can be tried before enabling them.
+func (c *TestSoundCommand) Execute(flags interface{}) error {
+	f, ok := flags.(*Flags)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	eventNames, err := soundEvents(f.TestSound)
+	if err != nil {
+		return err
+	}
+
+	loader := config.NewLoader(c.getConfigPath())
+	cfg, err := loader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	notification := cfg.Notification
+	enabled := true
+	notification.Sound = &enabled
+	notification.Sounds.Enabled = &enabled
+
+	manager := sound.NewManager(c.cacheDir)
+	if err := manager.Configure(notification); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.out, "🔊 SilentCast Sound Test")
+	fmt.Fprintln(c.out, "========================")
+	fmt.Fprintf(c.out, "Player: %s\n", manager.Player().Name)
+	fmt.Fprintf(c.out, "Volume: %d\n", notification.Sounds.Volume)
+	if !cfg.Notification.SoundEnabled() || !cfg.Notification.Sounds.SoundsEnabled() {
+		fmt.Fprintln(c.out, "Note:   sound cues are disabled; set notification.sounds.enabled to true to hear them")
+	}
+	fmt.Fprintln(c.out)
+
+	failed := 0
+	for _, event := range eventNames {
+		name := manager.Sound(event)
+		if name == config.SoundNone {
+			fmt.Fprintf(c.out, "  %-8s %s (silent)\n", event, name)
+			continue
+		}
+		fmt.Fprintf(c.out, "  %-8s %s ... ", event, name)
+		if err := manager.PlayAndWait(event); err != nil {
+			failed++
+			fmt.Fprintf(c.out, "❌ %v\n", err)
+			continue
+		}
+		fmt.Fprintln(c.out, "✅")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sounds failed to play", failed, len(eventNames))
+	}
+	return nil
+}
+
+// soundEvents returns the events selected by a --test-sound argument
+func soundEvents(arg string) ([]string, error) {
+	if arg == "all" {
+		return config.SoundEvents, nil
+	}
+	for _, event := range config.SoundEvents {
+		if arg == event {
+			return []string{event}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown sound event: %s (use prefix, success, failure, timeout, or all)", arg)
+}
+
+// Group returns the command group
+func (c *TestSoundCommand) Group() string {
+	return "debug"
+}
+
+// HasOptions returns if this command has additional options
+func (c *TestSoundCommand) HasOptions() bool {
+	return false
+}
@@ -118,6 +118,14 @@ func (c *ValidateConfigCommand) Execute(flags interface{}) error {
 		}
 	}
 
+	// Older formats still load, but only --migrate-config updates the files
+	fmt.Println("\n🔍 Checking spellbook format...")
+	if layers, err := loader.LoadLayers(); err == nil {
+		for _, layer := range layers {
+			c.checkFormatVersion(layer.Path)
+		}
+	}
+
 	// Check permissions
 	fmt.Println("\n🔍 Checking permissions...")
 	ctx := context.Background()
@@ -171,6 +179,25 @@ func (c *ValidateConfigCommand) HasOptions() bool {
 	return false
 }
 
+// checkFormatVersion prints whether a spellbook file uses the current format
+func (c *ValidateConfigCommand) checkFormatVersion(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	result, err := config.Migrate(data)
+	if err != nil {
+		fmt.Printf("   ❌ %s: %v\n", filepath.Base(path), err)
+		return
+	}
+	if result.Outdated {
+		fmt.Printf("   ⚠️  %s uses format version %d (current: %d); run 'silentcast --migrate-config' to upgrade it\n",
+			filepath.Base(path), result.FromVersion, result.ToVersion)
+		return
+	}
+	fmt.Printf("   ✅ %s: format version %d\n", filepath.Base(path), result.FromVersion)
+}
+
 // validateAction checks an action configuration for issues
 func validateAction(_ string, action *config.ActionConfig) []string {
 	var issues []string
@@ -179,7 +206,7 @@ func validateAction(_ string, action *config.ActionConfig) []string {
 	if action.Type == "" {
 		issues = append(issues, "missing type")
 	}
-	if action.Command == "" {
+	if action.Command == "" && action.ScriptFile == "" {
 		issues = append(issues, "missing command")
 	}
 
@@ -278,6 +305,7 @@ func (c *ValidateConfigCommand) validateWithLineNumbers(_ *config.Loader) ([]str
 
 		// Use the validator with line number information
 		validator := config.NewValidator()
+		validator.SetBaseDir(c.getConfigPath())
 		validationErrors := validator.ValidateWithYAML(tempCfg, yamlContent)
 
 		// Add file context to each error
@@ -23,10 +23,11 @@ const (
 
 // YAML top-level key names - customize these to change the configuration structure
 var (
-	KeyDaemon    = "daemon"   // デーモン設定
-	KeyHotkeys   = "hotkeys"  // ホットキー設定
-	KeyShortcuts = "spells"   // ショートカット定義（Config構造体ではSpells）
-	KeyActions   = "grimoire" // アクション定義（Config構造体ではGrimoire）
-	KeyLogger    = "logger"   // ログ設定
-	KeyUpdater   = "updater"  // 更新設定
+	KeyDaemon       = "daemon"       // デーモン設定
+	KeyHotkeys      = "hotkeys"      // ホットキー設定
+	KeyShortcuts    = "spells"       // ショートカット定義（Config構造体ではSpells）
+	KeyActions      = "grimoire"     // アクション定義（Config構造体ではGrimoire）
+	KeyLogger       = "logger"       // ログ設定
+	KeyUpdater      = "updater"      // 更新設定
+	KeyNotification = "notification" // 通知設定
 )
@@ -0,0 +1,404 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Editor edits a spellbook file in place through its YAML node tree.
+// Unlike round-tripping through Config, comments and key order of
+// the untouched parts of the file are preserved.
+type Editor struct {
+	path     string
+	original []byte // Content the editor was opened with
+	doc      *yaml.Node
+	root     *yaml.Node
+}
+
+// NewEditor opens a spellbook file for editing.
+// A missing or empty file yields an editor for an empty document.
+func NewEditor(path string) (*Editor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	e, err := newEditorFromBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	e.path = path
+	return e, nil
+}
+
+// newEditorFromBytes parses YAML content into an editor
+func newEditorFromBytes(data []byte) (*Editor, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind == 0 {
+		// Empty input: start a fresh document
+		doc = yaml.Node{Kind: yaml.DocumentNode}
+	}
+	if len(doc.Content) == 0 {
+		// New files start at the current format version
+		root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setDocumentVersion(root, SchemaVersion)
+		doc.Content = []*yaml.Node{root}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("top-level element must be a mapping")
+	}
+
+	return &Editor{original: data, doc: &doc, root: root}, nil
+}
+
+// Path returns the file the editor writes to
+func (e *Editor) Path() string {
+	return e.path
+}
+
+// Spells returns the spells defined in the file
+func (e *Editor) Spells() map[string]string {
+	spells := make(map[string]string)
+	if section := e.section(KeyShortcuts, false); section != nil {
+		for i := 0; i+1 < len(section.Content); i += 2 {
+			spells[section.Content[i].Value] = section.Content[i+1].Value
+		}
+	}
+	return spells
+}
+
+// Spell returns the action name bound to a sequence
+func (e *Editor) Spell(sequence string) (string, bool) {
+	section := e.section(KeyShortcuts, false)
+	if idx := findKey(section, sequence); idx >= 0 {
+		return section.Content[idx+1].Value, true
+	}
+	return "", false
+}
+
+// SetSpell binds a sequence to an action, appending the spell if it is new
+func (e *Editor) SetSpell(sequence, actionName string) {
+	section := e.section(KeyShortcuts, true)
+	if idx := findKey(section, sequence); idx >= 0 {
+		section.Content[idx+1].Value = actionName
+		section.Content[idx+1].Tag = "!!str"
+		return
+	}
+	section.Content = append(section.Content, spellKeyNode(sequence), scalarNode(actionName))
+}
+
+// RemoveSpell deletes a spell and returns the action it was bound to
+func (e *Editor) RemoveSpell(sequence string) (string, bool) {
+	section := e.section(KeyShortcuts, false)
+	idx := findKey(section, sequence)
+	if idx < 0 {
+		return "", false
+	}
+	actionName := section.Content[idx+1].Value
+	section.Content = append(section.Content[:idx], section.Content[idx+2:]...)
+	return actionName, true
+}
+
+// RenameSpell changes the sequence of an existing spell, keeping its
+// position and any comments attached to it
+func (e *Editor) RenameSpell(oldSequence, newSequence string) error {
+	section := e.section(KeyShortcuts, false)
+	idx := findKey(section, oldSequence)
+	if idx < 0 {
+		return fmt.Errorf("spell '%s' not found", oldSequence)
+	}
+	if oldSequence != newSequence && findKey(section, newSequence) >= 0 {
+		return fmt.Errorf("spell '%s' already exists", newSequence)
+	}
+
+	key := section.Content[idx]
+	renamed := spellKeyNode(newSequence)
+	key.Value = renamed.Value
+	key.Style = renamed.Style
+	key.Tag = renamed.Tag
+	return nil
+}
+
+// SpellsForAction returns the sequences bound to an action
+func (e *Editor) SpellsForAction(actionName string) []string {
+	var sequences []string
+	if section := e.section(KeyShortcuts, false); section != nil {
+		for i := 0; i+1 < len(section.Content); i += 2 {
+			if section.Content[i+1].Value == actionName {
+				sequences = append(sequences, section.Content[i].Value)
+			}
+		}
+	}
+	return sequences
+}
+
+// HasAction reports whether a grimoire action is defined in the file
+func (e *Editor) HasAction(name string) bool {
+	return findKey(e.section(KeyActions, false), name) >= 0
+}
+
+// SetAction defines a grimoire action, replacing an existing definition in place
+func (e *Editor) SetAction(name string, action ActionConfig) error {
+	var value yaml.Node
+	if err := value.Encode(action); err != nil {
+		return fmt.Errorf("failed to encode action '%s': %w", name, err)
+	}
+
+	section := e.section(KeyActions, true)
+	if idx := findKey(section, name); idx >= 0 {
+		section.Content[idx+1] = &value
+		return nil
+	}
+	section.Content = append(section.Content, scalarNode(name), &value)
+	return nil
+}
+
+// RemoveAction deletes a grimoire action
+func (e *Editor) RemoveAction(name string) bool {
+	section := e.section(KeyActions, false)
+	idx := findKey(section, name)
+	if idx < 0 {
+		return false
+	}
+	section.Content = append(section.Content[:idx], section.Content[idx+2:]...)
+	return true
+}
+
+// RenameAction renames a grimoire action in place, keeping its position and
+// comments, and rebinds every spell that referenced it.
+// It returns the sequences of the rebound spells.
+func (e *Editor) RenameAction(oldName, newName string) ([]string, error) {
+	section := e.section(KeyActions, false)
+	idx := findKey(section, oldName)
+	if idx < 0 {
+		return nil, fmt.Errorf("action '%s' not found", oldName)
+	}
+	if oldName != newName && findKey(section, newName) >= 0 {
+		return nil, fmt.Errorf("action '%s' already exists", newName)
+	}
+
+	key := section.Content[idx]
+	renamed := scalarNode(newName)
+	key.Value = renamed.Value
+	key.Style = renamed.Style
+	key.Tag = renamed.Tag
+
+	var sequences []string
+	if spells := e.section(KeyShortcuts, false); spells != nil {
+		for i := 0; i+1 < len(spells.Content); i += 2 {
+			if value := spells.Content[i+1]; value.Value == oldName {
+				value.Value = newName
+				sequences = append(sequences, spells.Content[i].Value)
+			}
+		}
+	}
+	return sequences, nil
+}
+
+// Bytes renders the edited document. Lines the edits did not change keep
+// their original layout, including blank lines and comment spacing.
+func (e *Editor) Bytes() ([]byte, error) {
+	edited, err := encodeDocument(e.doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(e.original)) == 0 {
+		return edited, nil
+	}
+
+	// The original parsed before, so it parses again
+	var doc yaml.Node
+	if err := yaml.Unmarshal(e.original, &doc); err != nil {
+		return edited, nil
+	}
+	base, err := encodeDocument(&doc)
+	if err != nil {
+		return edited, nil
+	}
+	return preserveLayout(e.original, base, edited), nil
+}
+
+// encodeDocument renders a document node the way spellbooks are written
+func encodeDocument(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes the document back to its file using WriteFileWithBackup.
+// It returns the backup path, or an empty string if there was nothing to back up.
+func (e *Editor) Save() (string, error) {
+	data, err := e.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return WriteFileWithBackup(e.path, data)
+}
+
+// WriteFileWithBackup replaces a configuration file.
+// An existing file is first copied to a timestamped backup, and the new
+// content is written to a temporary file that is renamed over the original
+// so readers such as the config watcher never observe a partial write.
+// It returns the backup path, or an empty string if there was nothing to back up.
+func WriteFileWithBackup(path string, data []byte) (string, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	mode := os.FileMode(0o600)
+	backupPath := ""
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read existing config: %w", err)
+		}
+		if backupPath, err = writeBackup(path, original); err != nil {
+			return "", fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(path, data, mode); err != nil {
+		return backupPath, err
+	}
+	return backupPath, nil
+}
+
+// maxBackupAttempts bounds the search for a free backup name within one second
+const maxBackupAttempts = 1000
+
+// writeBackup copies data to a new timestamped backup of path. Backups are
+// created exclusively, so a second save within the same second gets a
+// numbered name instead of overwriting the first backup.
+func writeBackup(path string, data []byte) (string, error) {
+	base := fmt.Sprintf("%s.backup.%s", path, time.Now().Format("20060102-150405"))
+	for n := 0; n < maxBackupAttempts; n++ {
+		backupPath := base
+		if n > 0 {
+			backupPath = fmt.Sprintf("%s.%d", base, n)
+		}
+
+		file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			os.Remove(backupPath)
+			return "", err
+		}
+		if err := file.Close(); err != nil {
+			os.Remove(backupPath)
+			return "", err
+		}
+		return backupPath, nil
+	}
+	return "", fmt.Errorf("no free backup name for %s", base)
+}
+
+// writeFileAtomic writes data to a temporary file in the target directory and renames it into place
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// section returns the mapping node of a top-level key, optionally creating it
+func (e *Editor) section(key string, create bool) *yaml.Node {
+	if idx := findKey(e.root, key); idx >= 0 {
+		value := e.root.Content[idx+1]
+		if value.Kind == yaml.MappingNode {
+			return value
+		}
+		if !create {
+			return nil
+		}
+		// Replace an empty (null) section with a mapping, keeping its comments
+		mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", LineComment: value.LineComment}
+		e.root.Content[idx+1] = mapping
+		return mapping
+	}
+
+	if !create {
+		return nil
+	}
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	e.root.Content = append(e.root.Content, scalarNode(key), mapping)
+	return mapping
+}
+
+// findKey returns the index of a key in a mapping node, or -1
+func findKey(mapping *yaml.Node, key string) int {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// scalarNode creates a plain string scalar
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// spellKeyNode creates a spell key, quoting sequences the way spellbooks are usually written
+func spellKeyNode(sequence string) *yaml.Node {
+	node := scalarNode(sequence)
+	if strings.ContainsAny(sequence, ", ") {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	return node
+}
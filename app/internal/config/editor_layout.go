@@ -0,0 +1,177 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// preserveLayout carries the layout of the original file over to an edited
+// rendering. The encoder drops blank lines and normalizes the spacing before
+// comments, so lines the edit did not change are taken from the original
+// file, with the blank lines in front of them, and only changed lines come
+// from the encoder.
+//
+// base is the encoding of the unedited original and edited the encoding of
+// the edited document. If the result does not decode to the same content as
+// edited, edited is returned unchanged.
+func preserveLayout(original, base, edited []byte) []byte {
+	originalLines := splitLines(original)
+	baseLines := splitLines(base)
+	editedLines := splitLines(edited)
+
+	// Which original line each line of the encoding was rendered from
+	source := make([]int, len(baseLines))
+	for i := range source {
+		source[i] = -1
+	}
+	for _, pair := range commonLines(baseLines, originalLines, layoutKey) {
+		source[pair[0]] = pair[1]
+	}
+
+	var out []string
+	next := 0 // First original line not yet emitted or skipped
+	emit := func(baseIdx int, fallback string) {
+		origIdx := source[baseIdx]
+		if origIdx < 0 {
+			out = append(out, fallback)
+			return
+		}
+		// Blank lines in front of an unchanged line belong to it
+		for ; next < origIdx; next++ {
+			if strings.TrimSpace(originalLines[next]) == "" {
+				out = append(out, originalLines[next])
+			}
+		}
+		out = append(out, originalLines[origIdx])
+		next = origIdx + 1
+	}
+	skip := func(baseIdx int) {
+		if origIdx := source[baseIdx]; origIdx >= 0 {
+			next = origIdx + 1
+		}
+	}
+
+	b, e := 0, 0
+	for _, pair := range commonLines(baseLines, editedLines, exactKey) {
+		for ; b < pair[0]; b++ {
+			skip(b)
+		}
+		for ; e < pair[1]; e++ {
+			out = append(out, editedLines[e])
+		}
+		emit(b, editedLines[e])
+		b, e = b+1, e+1
+	}
+	for ; b < len(baseLines); b++ {
+		skip(b)
+	}
+	out = append(out, editedLines[e:]...)
+	// Trailing blank lines of the original are kept
+	for ; next < len(originalLines); next++ {
+		if strings.TrimSpace(originalLines[next]) == "" {
+			out = append(out, originalLines[next])
+		}
+	}
+
+	result := []byte(strings.Join(out, "\n") + "\n")
+	if !sameContent(result, edited) {
+		return edited
+	}
+	return result
+}
+
+// splitLines splits content into lines without their line breaks
+func splitLines(data []byte) []string {
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// exactKey compares lines as they are
+func exactKey(line string) string {
+	return line
+}
+
+// layoutKey compares lines regardless of the spacing between words, such as
+// the spacing before a comment, but keeps their indentation
+func layoutKey(line string) string {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	return line[:indent] + strings.Join(strings.Fields(line), " ")
+}
+
+// commonLines returns the index pairs of a longest common subsequence of
+// lines a and b, compared by key
+func commonLines(a, b []string, key func(string) string) [][2]int {
+	ka := make([]string, len(a))
+	for i, line := range a {
+		ka[i] = key(line)
+	}
+	kb := make([]string, len(b))
+	for i, line := range b {
+		kb[i] = key(line)
+	}
+
+	// Edits are usually small, so the common prefix and suffix are matched directly
+	prefix := 0
+	for prefix < len(ka) && prefix < len(kb) && ka[prefix] == kb[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(ka)-prefix && suffix < len(kb)-prefix && ka[len(ka)-1-suffix] == kb[len(kb)-1-suffix] {
+		suffix++
+	}
+
+	var pairs [][2]int
+	for i := 0; i < prefix; i++ {
+		pairs = append(pairs, [2]int{i, i})
+	}
+
+	ma, mb := ka[prefix:len(ka)-suffix], kb[prefix:len(kb)-suffix]
+	// lengths[i][j] is the LCS length of ma[i:] and mb[j:]
+	lengths := make([][]int, len(ma)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(ma) && j < len(mb); {
+		switch {
+		case ma[i] == mb[j]:
+			pairs = append(pairs, [2]int{prefix + i, prefix + j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	for i := suffix; i > 0; i-- {
+		pairs = append(pairs, [2]int{len(ka) - i, len(kb) - i})
+	}
+	return pairs
+}
+
+// sameContent reports whether two YAML documents decode to the same values
+func sameContent(a, b []byte) bool {
+	var va, vb interface{}
+	if err := yaml.Unmarshal(a, &va); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal(b, &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const editorTestConfig = `# My spellbook
+hotkeys:
+  prefix: "alt+space"
+
+spells:
+  # Editors
+  e: editor # main editor
+  "g,s": git_status
+
+grimoire:
+  editor:
+    type: app
+    command: code
+  # Git helpers
+  git_status:
+    type: script
+    command: git status
+`
+
+func writeEditorTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ConfigName+".yml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestEditor_PreservesComments(t *testing.T) {
+	editor, err := NewEditor(writeEditorTestConfig(t, editorTestConfig))
+	if err != nil {
+		t.Fatalf("NewEditor() error = %v", err)
+	}
+
+	editor.SetSpell("g,l", "git_log")
+	if err := editor.SetAction("git_log", ActionConfig{Type: "script", Command: "git log --oneline"}); err != nil {
+		t.Fatalf("SetAction() error = %v", err)
+	}
+
+	data, err := editor.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	output := string(data)
+
+	for _, want := range []string{"# My spellbook", "# Editors", "# main editor", "# Git helpers", `"g,l": git_log`, "command: git log --oneline"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	// Existing entries keep their order and new ones are appended
+	if strings.Index(output, "e: editor") > strings.Index(output, `"g,s": git_status`) ||
+		strings.Index(output, `"g,s": git_status`) > strings.Index(output, `"g,l": git_log`) {
+		t.Errorf("spell order not preserved:\n%s", output)
+	}
+}
+
+func TestEditor_RemoveAndRename(t *testing.T) {
+	editor, err := NewEditor(writeEditorTestConfig(t, editorTestConfig))
+	if err != nil {
+		t.Fatalf("NewEditor() error = %v", err)
+	}
+
+	if err := editor.RenameSpell("e", "e,d"); err != nil {
+		t.Fatalf("RenameSpell() error = %v", err)
+	}
+	if err := editor.RenameSpell("missing", "x"); err == nil {
+		t.Error("RenameSpell() expected error for missing spell")
+	}
+	if err := editor.RenameSpell("e,d", "g,s"); err == nil {
+		t.Error("RenameSpell() expected error when target exists")
+	}
+
+	action, removed := editor.RemoveSpell("g,s")
+	if !removed || action != "git_status" {
+		t.Errorf("RemoveSpell() = %q, %v; want git_status, true", action, removed)
+	}
+	if len(editor.SpellsForAction("git_status")) != 0 {
+		t.Error("git_status should no longer be referenced")
+	}
+	if !editor.RemoveAction("git_status") {
+		t.Error("RemoveAction() = false, want true")
+	}
+	if editor.HasAction("git_status") {
+		t.Error("git_status should have been removed")
+	}
+
+	spells := editor.Spells()
+	if len(spells) != 1 || spells["e,d"] != "editor" {
+		t.Errorf("Spells() = %v, want map[e,d:editor]", spells)
+	}
+
+	data, err := editor.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"e,d": editor # main editor`) {
+		t.Errorf("renamed spell lost its comment:\n%s", data)
+	}
+}
+
+func TestEditor_RenameAction(t *testing.T) {
+	editor, err := NewEditor(writeEditorTestConfig(t, editorTestConfig+"  git_log:\n    type: script\n    command: git log\n"))
+	if err != nil {
+		t.Fatalf("NewEditor() error = %v", err)
+	}
+	editor.SetSpell("g,t", "git_status")
+
+	sequences, err := editor.RenameAction("git_status", "git_st")
+	if err != nil {
+		t.Fatalf("RenameAction() error = %v", err)
+	}
+	if len(sequences) != 2 || sequences[0] != "g,s" || sequences[1] != "g,t" {
+		t.Errorf("RenameAction() = %v, want [g,s g,t]", sequences)
+	}
+	if _, err := editor.RenameAction("missing", "x"); err == nil {
+		t.Error("RenameAction() expected error for missing action")
+	}
+	if _, err := editor.RenameAction("git_st", "editor"); err == nil {
+		t.Error("RenameAction() expected error when target exists")
+	}
+
+	if editor.HasAction("git_status") || !editor.HasAction("git_st") {
+		t.Error("action was not renamed")
+	}
+	if len(editor.SpellsForAction("git_status")) != 0 {
+		t.Error("git_status should no longer be referenced")
+	}
+
+	data, err := editor.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	output := string(data)
+	for _, want := range []string{`"g,s": git_st`, `"g,t": git_st`, "# Git helpers\n  git_st:", "e: editor # main editor"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	// The renamed action keeps its position before git_log
+	if strings.Index(output, "git_st:") > strings.Index(output, "git_log:") {
+		t.Errorf("action order not preserved:\n%s", output)
+	}
+}
+
+func TestEditor_PreservesLayout(t *testing.T) {
+	const original = `# My spellbook
+
+hotkeys:
+  prefix: "alt+space"
+
+spells:
+  e: editor        # main editor
+  "g,s": git_status
+
+grimoire:
+  editor:
+    type: app
+    command: code
+
+  # Git helpers
+  git_status:
+    type: script
+    command: git status
+`
+
+	tests := []struct {
+		name string
+		edit func(e *Editor) error
+		want string
+	}{
+		{
+			name: "add spell",
+			edit: func(e *Editor) error {
+				e.SetSpell("g,l", "git_status")
+				return nil
+			},
+			want: strings.Replace(original, "  \"g,s\": git_status\n", "  \"g,s\": git_status\n  \"g,l\": git_status\n", 1),
+		},
+		{
+			name: "remove spell",
+			edit: func(e *Editor) error {
+				e.RemoveSpell("g,s")
+				return nil
+			},
+			want: strings.Replace(original, "  \"g,s\": git_status\n", "", 1),
+		},
+		{
+			name: "rename action",
+			edit: func(e *Editor) error {
+				_, err := e.RenameAction("git_status", "git_st")
+				return err
+			},
+			want: strings.ReplaceAll(original, "git_status", "git_st"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor, err := NewEditor(writeEditorTestConfig(t, original))
+			if err != nil {
+				t.Fatalf("NewEditor() error = %v", err)
+			}
+			if err := tt.edit(editor); err != nil {
+				t.Fatalf("edit error = %v", err)
+			}
+			data, err := editor.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Bytes() changed untouched lines:\n%s\nwant:\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditor_EmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", ConfigName+".yml")
+
+	editor, err := NewEditor(path)
+	if err != nil {
+		t.Fatalf("NewEditor() error = %v", err)
+	}
+	editor.SetSpell("t", "terminal")
+	if err := editor.SetAction("terminal", ActionConfig{Type: "app", Command: "xterm"}); err != nil {
+		t.Fatalf("SetAction() error = %v", err)
+	}
+
+	backup, err := editor.Save()
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if backup != "" {
+		t.Errorf("Save() backup = %q, want none for new file", backup)
+	}
+
+	cfg, err := NewLoader(filepath.Dir(path)).LoadRaw()
+	if err != nil {
+		t.Fatalf("saved file does not load: %v", err)
+	}
+	if cfg.Shortcuts["t"] != "terminal" || cfg.Actions["terminal"].Command != "xterm" {
+		t.Errorf("unexpected saved config: %+v", cfg)
+	}
+}
+
+func TestEditor_SaveCreatesBackup(t *testing.T) {
+	path := writeEditorTestConfig(t, editorTestConfig)
+
+	editor, err := NewEditor(path)
+	if err != nil {
+		t.Fatalf("NewEditor() error = %v", err)
+	}
+	editor.RemoveSpell("e")
+
+	backup, err := editor.Save()
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	original, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatalf("backup not readable: %v", err)
+	}
+	if string(original) != editorTestConfig {
+		t.Error("backup content differs from original file")
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file left behind: %s", entry.Name())
+		}
+	}
+}
+
+func TestEditor_SaveKeepsEveryBackup(t *testing.T) {
+	path := writeEditorTestConfig(t, editorTestConfig)
+
+	var backups []string
+	for _, sequence := range []string{"e", "g,s"} {
+		editor, err := NewEditor(path)
+		if err != nil {
+			t.Fatalf("NewEditor() error = %v", err)
+		}
+		editor.RemoveSpell(sequence)
+		backup, err := editor.Save()
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		backups = append(backups, backup)
+	}
+
+	if backups[0] == backups[1] {
+		t.Fatalf("second save reused backup %s", backups[0])
+	}
+	original, err := os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatalf("first backup not readable: %v", err)
+	}
+	if string(original) != editorTestConfig {
+		t.Error("first backup was overwritten")
+	}
+	intermediate, err := os.ReadFile(backups[1])
+	if err != nil {
+		t.Fatalf("second backup not readable: %v", err)
+	}
+	if strings.Contains(string(intermediate), "e: editor") || !strings.Contains(string(intermediate), `"g,s": git_status`) {
+		t.Errorf("second backup should hold the file after the first save:\n%s", intermediate)
+	}
+}
+
+func TestEditor_InvalidDocument(t *testing.T) {
+	if _, err := NewEditor(writeEditorTestConfig(t, "- a\n- b\n")); err == nil {
+		t.Error("NewEditor() expected error for non-mapping document")
+	}
+	if _, err := NewEditor(writeEditorTestConfig(t, "spells: [unclosed\n")); err == nil {
+		t.Error("NewEditor() expected error for invalid YAML")
+	}
+}
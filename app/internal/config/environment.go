@@ -0,0 +1,110 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+)
+
+// sensitiveEnvNames are parts of env names whose literal values are
+// redacted when the configuration is shown or exported
+var sensitiveEnvNames = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "API_KEY", "APIKEY", "PRIVATE_KEY", "CREDENTIAL"}
+
+// StringList is a list of strings that can also be written as a single
+// string in YAML
+type StringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler for StringList
+func (l *StringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		*l = StringList{value}
+		return nil
+	}
+	var values []string
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
+
+// mergeEnvironment applies the env files and secrets file a file sets.
+// Env files of every file are loaded, in order; the last secrets file wins.
+func mergeEnvironment(dst, src *Config) {
+	dst.EnvFile = append(dst.EnvFile, src.EnvFile...)
+	if src.SecretsFile != "" {
+		dst.SecretsFile = src.SecretsFile
+	}
+}
+
+// Redacted returns a copy of the configuration for --show-config and
+// --export-config. Literal values of sensitive-looking env variables and
+// webhook headers are replaced; values that only reference variables, e.g.
+// ${secret:NAME}, are kept since they do not contain the secret. Webhook URLs
+// keep only their host, since tokens are often part of the path.
+func Redacted(cfg *Config) *Config {
+	redacted := *cfg
+	redacted.Actions = make(map[string]ActionConfig, len(cfg.Actions))
+	for name, action := range cfg.Actions {
+		if len(action.Env) > 0 {
+			env := make(map[string]string, len(action.Env))
+			for k, v := range action.Env {
+				if isSensitiveEnv(k) && v != "" && !strings.Contains(v, "${") {
+					v = dotenv.RedactedValue
+				}
+				env[k] = v
+			}
+			action.Env = env
+		}
+		redacted.Actions[name] = action
+	}
+
+	if len(cfg.Notification.Webhooks) > 0 {
+		redacted.Notification.Webhooks = make([]WebhookConfig, len(cfg.Notification.Webhooks))
+		for i, webhook := range cfg.Notification.Webhooks {
+			webhook.URL = redactURL(webhook.URL)
+			if len(webhook.Headers) > 0 {
+				headers := make(map[string]string, len(webhook.Headers))
+				for k, v := range webhook.Headers {
+					if (isSensitiveEnv(k) || strings.EqualFold(k, "Authorization")) && v != "" && !strings.Contains(v, "${") {
+						v = dotenv.RedactedValue
+					}
+					headers[k] = v
+				}
+				webhook.Headers = headers
+			}
+			redacted.Notification.Webhooks[i] = webhook
+		}
+	}
+	return &redacted
+}
+
+// redactURL replaces everything after the host of a URL, where tokens such
+// as those of Slack webhooks and ntfy topics live. A URL that is a single
+// variable reference is kept.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || strings.Contains(u.Host, "$") {
+		if strings.HasPrefix(raw, "$") && !strings.ContainsAny(strings.TrimPrefix(raw, "$"), "$/") {
+			return raw
+		}
+		return dotenv.RedactedValue
+	}
+	if u.User == nil && strings.Trim(u.Path, "/") == "" && u.RawQuery == "" && u.Fragment == "" {
+		return raw
+	}
+	return u.Scheme + "://" + u.Host + "/" + dotenv.RedactedValue
+}
+
+// isSensitiveEnv reports whether an env name looks like it holds a secret
+func isSensitiveEnv(name string) bool {
+	name = strings.ToUpper(name)
+	for _, part := range sensitiveEnvNames {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
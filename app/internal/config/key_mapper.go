@@ -37,12 +37,14 @@ func MapCustomKeys(data []byte) (mappedData []byte, hasPrefix bool, err error) {
 
 	// Map custom keys to standard keys (Config struct YAML tags)
 	keyMap := map[string]string{
-		KeyDaemon:    "daemon",
-		KeyHotkeys:   "hotkeys",
-		KeyShortcuts: "spells",   // Config struct field: Spells
-		KeyActions:   "grimoire", // Config struct field: Grimoire
-		KeyLogger:    "logger",
-		KeyUpdater:   "updater",
+		KeyDaemon:       "daemon",
+		KeyHotkeys:      "hotkeys",
+		KeyShortcuts:    "spells",   // Config struct field: Spells
+		KeyActions:      "grimoire", // Config struct field: Grimoire
+		KeyLogger:       "logger",
+		KeyUpdater:      "updater",
+		KeyNotification: "notification",
+		KeyVersion:      "version",
 	}
 
 	for customKey, standardKey := range keyMap {
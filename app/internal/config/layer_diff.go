@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangeKind describes how a layer changed the merged configuration
+type ChangeKind string
+
+const (
+	ChangeAdded      ChangeKind = "added"
+	ChangeOverridden ChangeKind = "overridden"
+	ChangeRemoved    ChangeKind = "removed"
+)
+
+// Change sections
+const (
+	SectionSpells   = "spells"
+	SectionGrimoire = "grimoire"
+	SectionSettings = "settings"
+)
+
+// LayerChange is a single difference introduced by merging a configuration layer
+type LayerChange struct {
+	File    string      `json:"file"`
+	Section string      `json:"section"`
+	Key     string      `json:"key"`
+	Kind    ChangeKind  `json:"kind"`
+	Before  interface{} `json:"before,omitempty"`
+	After   interface{} `json:"after,omitempty"`
+	Fields  []string    `json:"fields,omitempty"` // changed fields of an overridden action
+}
+
+// DiffLayers merges the layers one by one, exactly as Load does, and reports
+// how each layer after the base changed the result
+func (l *Loader) DiffLayers(layers []Layer) ([]LayerChange, error) {
+	if len(layers) == 0 {
+		return nil, nil
+	}
+
+	current := &Config{
+		Shortcuts: make(map[string]string),
+		Actions:   make(map[string]ActionConfig),
+	}
+	l.merge(current, layers[0].Config)
+
+	var changes []LayerChange
+	for _, layer := range layers[1:] {
+		before, err := snapshotConfig(current)
+		if err != nil {
+			return nil, err
+		}
+		l.merge(current, layer.Config)
+		after, err := snapshotConfig(current)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, diffSection(layer.Path, SectionSpells, before.spells, after.spells)...)
+		changes = append(changes, diffSection(layer.Path, SectionGrimoire, before.actions, after.actions)...)
+		changes = append(changes, diffSection(layer.Path, SectionSettings, before.settings, after.settings)...)
+	}
+	return changes, nil
+}
+
+// configSnapshot is a comparable copy of a merged configuration
+type configSnapshot struct {
+	spells   map[string]interface{}
+	actions  map[string]interface{}
+	settings map[string]interface{}
+}
+
+// snapshotConfig copies spells, actions and flattened settings of a configuration
+func snapshotConfig(cfg *Config) (*configSnapshot, error) {
+	snap := &configSnapshot{
+		spells:   make(map[string]interface{}, len(cfg.Shortcuts)),
+		actions:  make(map[string]interface{}, len(cfg.Actions)),
+		settings: make(map[string]interface{}),
+	}
+
+	for seq, action := range cfg.Shortcuts {
+		snap.spells[seq] = action
+	}
+
+	for name, action := range cfg.Actions {
+		fields, err := toMap(action)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare action '%s': %w", name, err)
+		}
+		snap.actions[name] = fields
+	}
+
+	settings := *cfg
+	settings.Shortcuts = nil
+	settings.Actions = nil
+	fields, err := toMap(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare settings: %w", err)
+	}
+	flatten("", fields, snap.settings)
+
+	return snap, nil
+}
+
+// toMap converts a value into its YAML field map
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// flatten stores nested mappings under dotted keys
+func flatten(prefix string, fields map[string]interface{}, out map[string]interface{}) {
+	for key, value := range fields {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = value
+	}
+}
+
+// diffSection compares two versions of a section, sorted by key
+func diffSection(file, section string, before, after map[string]interface{}) []LayerChange {
+	keys := make(map[string]bool, len(before)+len(after))
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var changes []LayerChange
+	for _, key := range sorted {
+		oldValue, hadOld := before[key]
+		newValue, hasNew := after[key]
+
+		change := LayerChange{File: file, Section: section, Key: key, Before: oldValue, After: newValue}
+		switch {
+		case !hadOld:
+			change.Kind = ChangeAdded
+		case !hasNew:
+			change.Kind = ChangeRemoved
+		case !reflect.DeepEqual(oldValue, newValue):
+			change.Kind = ChangeOverridden
+			change.Fields = changedFields(oldValue, newValue)
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// changedFields lists the differing keys of two field maps
+func changedFields(before, after interface{}) []string {
+	oldFields, ok1 := before.(map[string]interface{})
+	newFields, ok2 := after.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return nil
+	}
+
+	var fields []string
+	for key, value := range newFields {
+		if !reflect.DeepEqual(oldFields[key], value) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range oldFields {
+		if _, ok := newFields[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
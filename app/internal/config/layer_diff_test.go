@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoader_DiffLayers(t *testing.T) {
+	tempDir := t.TempDir()
+
+	base := `daemon:
+  config_watch: true
+hotkeys:
+  prefix: "alt+space"
+  timeout: 1000
+spells:
+  e: editor
+  t: terminal
+grimoire:
+  editor:
+    type: app
+    command: vi
+  terminal:
+    type: app
+    command: xterm
+`
+	overlay := `daemon:
+  config_watch: true
+hotkeys:
+  timeout: 500
+spells:
+  e: code
+  b: browser
+grimoire:
+  terminal:
+    type: app
+    command: gnome-terminal
+    description: GNOME Terminal
+  browser:
+    type: url
+    command: https://example.com
+`
+
+	if err := os.WriteFile(filepath.Join(tempDir, ConfigName+".yml"), []byte(base), 0o600); err != nil {
+		t.Fatalf("Failed to write base config: %v", err)
+	}
+	platformFile := filepath.Join(tempDir, GetPlatformResolver().GetPlatformConfigFile())
+	if err := os.WriteFile(platformFile, []byte(overlay), 0o600); err != nil {
+		t.Fatalf("Failed to write platform config: %v", err)
+	}
+
+	loader := NewLoader(tempDir)
+	layers, err := loader.LoadLayers()
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	changes, err := loader.DiffLayers(layers)
+	if err != nil {
+		t.Fatalf("DiffLayers() error = %v", err)
+	}
+
+	got := make(map[string]LayerChange)
+	for _, change := range changes {
+		if change.File != platformFile {
+			t.Errorf("change %s.%s attributed to %s", change.Section, change.Key, change.File)
+		}
+		got[change.Section+"."+change.Key] = change
+	}
+
+	tests := []struct {
+		key  string
+		kind ChangeKind
+	}{
+		{"spells.e", ChangeOverridden},
+		{"spells.b", ChangeAdded},
+		{"grimoire.terminal", ChangeOverridden},
+		{"grimoire.browser", ChangeAdded},
+		{"settings.hotkeys.timeout", ChangeOverridden},
+	}
+	for _, tt := range tests {
+		change, ok := got[tt.key]
+		if !ok {
+			t.Errorf("missing change for %s", tt.key)
+			continue
+		}
+		if change.Kind != tt.kind {
+			t.Errorf("%s kind = %s, want %s", tt.key, change.Kind, tt.kind)
+		}
+	}
+
+	for _, unchanged := range []string{"spells.t", "grimoire.editor", "settings.hotkeys.prefix", "settings.daemon.config_watch"} {
+		if _, ok := got[unchanged]; ok {
+			t.Errorf("unexpected change for %s", unchanged)
+		}
+	}
+
+	if fields := got["grimoire.terminal"].Fields; !reflect.DeepEqual(fields, []string{"command", "description"}) {
+		t.Errorf("grimoire.terminal fields = %v, want [command description]", fields)
+	}
+	// Durations are compared in the milliseconds used by spellbook files
+	if timeout := got["settings.hotkeys.timeout"]; timeout.Before != 1000 || timeout.After != 500 {
+		t.Errorf("hotkeys.timeout = %v → %v, want 1000 → 500", timeout.Before, timeout.After)
+	}
+	if spell := got["spells.e"]; spell.Before != "editor" || spell.After != "code" {
+		t.Errorf("spells.e = %v → %v, want editor → code", spell.Before, spell.After)
+	}
+}
+
+func TestLoader_DiffLayers_BaseOnly(t *testing.T) {
+	loader := NewLoader(t.TempDir())
+
+	changes, err := loader.DiffLayers([]Layer{{Path: "spellbook.yml", Config: &Config{Shortcuts: map[string]string{"e": "editor"}}}})
+	if err != nil {
+		t.Fatalf("DiffLayers() error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("DiffLayers() = %v, want no changes for a single layer", changes)
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// I/O scheduling classes of LimitsConfig.IONice
+const (
+	IONiceIdle = "idle" // Only use the disk when no other process does
+)
+
+// LimitsConfig limits the resources of a script and its children. Zero
+// values are unlimited.
+type LimitsConfig struct {
+	Memory    string `yaml:"memory,omitempty"`    // Address space of each process, e.g. 512M or 2G
+	CPUTime   int    `yaml:"cpu_time,omitempty"`  // Seconds of CPU time of each process
+	Processes int    `yaml:"processes,omitempty"` // Processes of the user, including the script's
+	FileSize  string `yaml:"file_size,omitempty"` // Largest file the script can write, e.g. 100M
+	Nice      int    `yaml:"nice,omitempty"`      // CPU priority from 1 to 19 (lowest); 0 leaves it unchanged
+	IONice    string `yaml:"ionice,omitempty"`    // idle, or a best-effort level from 0 (highest) to 7
+}
+
+// IsZero reports whether no limit is set
+func (l LimitsConfig) IsZero() bool {
+	return l == LimitsConfig{}
+}
+
+// ParseSize parses a size in bytes with an optional K, M, G or T suffix,
+// in powers of 1024
+func ParseSize(s string) (uint64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+	multiplier := uint64(1)
+	for i, suffix := range []string{"K", "M", "G", "T"} {
+		if strings.HasSuffix(value, suffix) {
+			value = strings.TrimSuffix(value, suffix)
+			multiplier = 1 << (10 * (i + 1))
+			break
+		}
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * multiplier, nil
+}
+
+// ParseIONice parses an ionice setting into an I/O scheduling class
+// (2 = best-effort, 3 = idle) and a level
+func ParseIONice(s string) (class, level int, err error) {
+	if s == IONiceIdle {
+		return 3, 0, nil
+	}
+	level, err = strconv.Atoi(s)
+	if err != nil || level < 0 || level > 7 {
+		return 0, 0, fmt.Errorf("invalid ionice %q", s)
+	}
+	return 2, level, nil
+}
+
+// SandboxConfig isolates a script: it runs in its own process group with
+// a restricted environment and, unless allowed, without network access.
+// `sandbox: true` enables it with the defaults, and a mapping enables it
+// unless it sets enabled: false.
+type SandboxConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Network  bool     `yaml:"network,omitempty"`   // Allow network access
+	ReadOnly []string `yaml:"read_only,omitempty"` // Paths the script cannot write to
+	Env      []string `yaml:"env,omitempty"`       // Variables of the environment passed in addition to the defaults
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for SandboxConfig
+func (s *SandboxConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var enabled bool
+	if err := unmarshal(&enabled); err == nil {
+		*s = SandboxConfig{Enabled: enabled}
+		return nil
+	}
+	type plain SandboxConfig
+	*s = SandboxConfig{Enabled: true}
+	return unmarshal((*plain)(s))
+}
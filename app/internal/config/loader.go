@@ -8,10 +8,12 @@ import (
 	"gopkg.in/yaml.v3"
 
 	appErrors "github.com/SphereStacking/silentcast/internal/errors"
+	"github.com/SphereStacking/silentcast/pkg/logger"
 )
 
 // Loader handles configuration loading and merging
 type Loader struct {
+	baseDir     string
 	configPaths []string
 }
 
@@ -26,6 +28,7 @@ func NewLoader(basePath string) *Loader {
 	paths = append(paths, filepath.Join(basePath, platform.GetPlatformConfigFile()))
 
 	return &Loader{
+		baseDir:     basePath,
 		configPaths: paths,
 	}
 }
@@ -61,6 +64,7 @@ func (l *Loader) Validate() ([]string, error) {
 
 	// Use the new comprehensive validator
 	validator := NewValidator()
+	validator.SetBaseDir(l.baseDir)
 	validationErrors := validator.Validate(cfg)
 
 	// Convert to string slice for backward compatibility
@@ -101,6 +105,14 @@ func (l *Loader) Load() (*Config, error) {
 		l.applyDefaults(cfg)
 	}
 
+	// Script files are relative to the config directory
+	for name, action := range cfg.Actions {
+		if action.ScriptFile != "" {
+			action.ScriptFile = action.ScriptPath(l.baseDir)
+			cfg.Actions[name] = action
+		}
+	}
+
 	// Validate the configuration
 	if err := l.validate(cfg); err != nil {
 		return nil, appErrors.Wrap(appErrors.ErrorTypeValidation, "configuration validation failed", err).
@@ -143,6 +155,36 @@ func (l *Loader) LoadRaw() (*Config, error) {
 	return cfg, nil
 }
 
+// Layer is a single configuration file as written, before merging
+type Layer struct {
+	Path   string
+	Config *Config
+}
+
+// LoadLayers loads each existing configuration file separately, in merge order.
+// Like LoadRaw, no defaults are applied and nothing is validated.
+func (l *Loader) LoadLayers() ([]Layer, error) {
+	var layers []Layer
+	for _, path := range l.configPaths {
+		cfg, err := l.parseFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, appErrors.Wrap(appErrors.ErrorTypeConfig, "failed to load configuration file", err).
+				WithContext("path", path).
+				WithContext("operation", "load_layers")
+		}
+		layers = append(layers, Layer{Path: path, Config: cfg})
+	}
+
+	if len(layers) == 0 {
+		return nil, appErrors.ErrConfigNotFound.WithContext("searched_paths", l.configPaths).
+			WithContext("operation", "load_layers")
+	}
+	return layers, nil
+}
+
 // applyDefaults sets default values for unset fields
 func (l *Loader) applyDefaults(cfg *Config) {
 	// Daemon defaults
@@ -175,19 +217,62 @@ func (l *Loader) applyDefaults(cfg *Config) {
 	if cfg.Logger.MaxAge == 0 {
 		cfg.Logger.MaxAge = 7 // 7 days
 	}
+
+	// Notification defaults
+	if cfg.Notification.MaxOutputLength == 0 {
+		cfg.Notification.MaxOutputLength = 1024
+	}
+	if cfg.Notification.History.MaxEntries == 0 {
+		cfg.Notification.History.MaxEntries = 500
+	}
+	if cfg.Notification.Coalesce.Window == 0 {
+		cfg.Notification.Coalesce.Window = 10
+	}
+	if cfg.Notification.Sounds.Volume == 0 {
+		cfg.Notification.Sounds.Volume = 80
+	}
+	if cfg.Notification.File.MaxSize == 0 {
+		cfg.Notification.File.MaxSize = 1024
+	}
+
+	// Execution log defaults
+	if cfg.ExecutionLogs.MaxRuns == 0 {
+		cfg.ExecutionLogs.MaxRuns = 10
+	}
+	if cfg.ExecutionLogs.MaxSize == 0 {
+		cfg.ExecutionLogs.MaxSize = 1024
+	}
 }
 
 // loadFile reads a single configuration file and merges it into the config
 func (l *Loader) loadFile(path string, cfg *Config) error {
-	data, err := os.ReadFile(path)
+	temp, err := l.parseFile(path)
 	if err != nil {
 		return err
 	}
 
+	// Merge the configurations
+	l.merge(cfg, temp)
+
+	return nil
+}
+
+// parseFile reads a single configuration file without merging it
+func (l *Loader) parseFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = upgradeFormat(path, data)
+	if err != nil {
+		return nil, err
+	}
+
 	// Map custom keys to standard keys if needed
 	mappedData, hasPrefix, err := MapCustomKeys(data)
 	if err != nil {
-		return appErrors.Wrap(appErrors.ErrorTypeConfig, "failed to map custom keys", err).
+		return nil, appErrors.Wrap(appErrors.ErrorTypeConfig, "failed to map custom keys", err).
 			WithContext("path", path)
 	}
 
@@ -199,14 +284,11 @@ func (l *Loader) loadFile(path string, cfg *Config) error {
 	}
 
 	if err := yaml.Unmarshal(mappedData, temp); err != nil {
-		return appErrors.Wrap(appErrors.ErrorTypeConfig, "failed to parse YAML", err).
+		return nil, appErrors.Wrap(appErrors.ErrorTypeConfig, "failed to parse YAML", err).
 			WithContext("path", path)
 	}
 
-	// Merge the configurations
-	l.merge(cfg, temp)
-
-	return nil
+	return temp, nil
 }
 
 // loadFileForValidation loads a config file for validation, preserving all values including invalid ones
@@ -217,6 +299,11 @@ func (l *Loader) loadFileForValidation(path string, cfg *Config) error {
 		return err
 	}
 
+	data, err = upgradeFormat(path, data)
+	if err != nil {
+		return err
+	}
+
 	// Apply key mapping
 	mappedData, hasPrefix, err := MapCustomKeys(data)
 	if err != nil {
@@ -244,6 +331,10 @@ func (l *Loader) loadFileForValidation(path string, cfg *Config) error {
 
 // mergeForValidation combines two configurations for validation, preserving all values including invalid ones
 func (l *Loader) mergeForValidation(dst, src *Config) {
+	if src.Version > dst.Version {
+		dst.Version = src.Version
+	}
+
 	// Merge daemon config
 	if src.Daemon.LogLevel != "" {
 		dst.Daemon.LogLevel = src.Daemon.LogLevel
@@ -311,10 +402,17 @@ func (l *Loader) mergeForValidation(dst, src *Config) {
 	if src.Updater.Prerelease {
 		dst.Updater.Prerelease = src.Updater.Prerelease
 	}
+	mergeNotification(&dst.Notification, &src.Notification)
+	mergeExecutionLogs(&dst.ExecutionLogs, &src.ExecutionLogs)
+	mergeEnvironment(dst, src)
 }
 
 // merge combines two configurations, with 'src' overriding 'dst'
 func (l *Loader) merge(dst, src *Config) {
+	if src.Version > dst.Version {
+		dst.Version = src.Version
+	}
+
 	// Merge daemon config
 	if src.Daemon.LogLevel != "" {
 		dst.Daemon.LogLevel = src.Daemon.LogLevel
@@ -372,12 +470,108 @@ func (l *Loader) merge(dst, src *Config) {
 	for k := range src.Actions {
 		dst.Actions[k] = src.Actions[k]
 	}
+
+	mergeNotification(&dst.Notification, &src.Notification)
+	mergeExecutionLogs(&dst.ExecutionLogs, &src.ExecutionLogs)
+	mergeEnvironment(dst, src)
+}
+
+// mergeExecutionLogs applies the execution log settings a file sets explicitly
+func mergeExecutionLogs(dst, src *ExecutionLogsConfig) {
+	if src.Enabled {
+		dst.Enabled = src.Enabled
+	}
+	if src.MaxRuns != 0 {
+		dst.MaxRuns = src.MaxRuns
+	}
+	if src.MaxSize != 0 {
+		dst.MaxSize = src.MaxSize
+	}
+}
+
+// mergeNotification applies the notification settings a file sets explicitly
+func mergeNotification(dst, src *NotificationConfig) {
+	if src.EnableTimeout != nil {
+		dst.EnableTimeout = src.EnableTimeout
+	}
+	if src.EnableWarning != nil {
+		dst.EnableWarning = src.EnableWarning
+	}
+	if src.Sound != nil {
+		dst.Sound = src.Sound
+	}
+	if src.MaxOutputLength != 0 {
+		dst.MaxOutputLength = src.MaxOutputLength
+	}
+	dst.Levels = dst.Levels.Merge(src.Levels)
+
+	if src.DoNotDisturb.Mode != "" {
+		dst.DoNotDisturb.Mode = src.DoNotDisturb.Mode
+	}
+	if src.DoNotDisturb.Allow != nil {
+		dst.DoNotDisturb.Allow = src.DoNotDisturb.Allow
+	}
+	if src.DoNotDisturb.QuietHours != nil {
+		dst.DoNotDisturb.QuietHours = src.DoNotDisturb.QuietHours
+	}
+
+	if src.History.Enabled != nil {
+		dst.History.Enabled = src.History.Enabled
+	}
+	if src.History.MaxEntries != 0 {
+		dst.History.MaxEntries = src.History.MaxEntries
+	}
+
+	if src.Webhooks != nil {
+		dst.Webhooks = src.Webhooks
+	}
+
+	if src.Coalesce.Enabled != nil {
+		dst.Coalesce.Enabled = src.Coalesce.Enabled
+	}
+	if src.Coalesce.Window != 0 {
+		dst.Coalesce.Window = src.Coalesce.Window
+	}
+	if src.File.Path != "" {
+		dst.File.Path = src.File.Path
+	}
+	if src.File.Levels != nil {
+		dst.File.Levels = src.File.Levels
+	}
+	if src.File.MaxSize != 0 {
+		dst.File.MaxSize = src.File.MaxSize
+	}
+	mergeSounds(&dst.Sounds, &src.Sounds)
+}
+
+// mergeSounds merges the sound cue settings that are set in src
+func mergeSounds(dst, src *SoundConfig) {
+	if src.Enabled != nil {
+		dst.Enabled = src.Enabled
+	}
+	if src.Player != "" {
+		dst.Player = src.Player
+	}
+	if src.Volume != 0 {
+		dst.Volume = src.Volume
+	}
+	for _, field := range []struct{ dst, src *string }{
+		{&dst.Prefix, &src.Prefix},
+		{&dst.Success, &src.Success},
+		{&dst.Failure, &src.Failure},
+		{&dst.Timeout, &src.Timeout},
+	} {
+		if *field.src != "" {
+			*field.dst = *field.src
+		}
+	}
 }
 
 // validate checks if the configuration is valid
 func (l *Loader) validate(cfg *Config) error {
 	// Use the new comprehensive validator
 	validator := NewValidator()
+	validator.SetBaseDir(l.baseDir)
 	errors := validator.Validate(cfg)
 
 	if len(errors) > 0 {
@@ -389,3 +583,23 @@ func (l *Loader) validate(cfg *Config) error {
 
 	return nil
 }
+
+// upgradeFormat applies pending format migrations in memory so that
+// spellbooks written for older releases keep loading unchanged.
+// The file itself is only rewritten by --migrate-config, which
+// --validate-config and the linter suggest for files using legacy fields;
+// loading stays quiet since the upgrade loses nothing and runs on every reload.
+func upgradeFormat(path string, data []byte) ([]byte, error) {
+	result, err := Migrate(data)
+	if err != nil {
+		return nil, appErrors.Wrap(appErrors.ErrorTypeConfig, "unsupported spellbook format", err).
+			WithContext("path", path)
+	}
+	if !result.Changed() {
+		return data, nil
+	}
+
+	logger.Debug("Upgraded %s from spellbook format version %d to %d in memory",
+		path, result.FromVersion, result.ToVersion)
+	return result.Data, nil
+}
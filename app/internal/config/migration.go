@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the spellbook format version understood by this release.
+// Files without a version key are treated as version 0.
+const SchemaVersion = 1
+
+// KeyVersion is the top-level key holding the spellbook format version
+const KeyVersion = "version"
+
+// Migration upgrades a spellbook document from one format version to the next.
+// Apply edits the top-level mapping node in place so comments survive.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(root *yaml.Node) error
+}
+
+// migrations holds registered migrations keyed by the version they upgrade from
+var migrations = make(map[int]Migration)
+
+func init() {
+	RegisterMigration(Migration{
+		From:        0,
+		Description: "Rename legacy app/script/url action fields to command",
+		Apply:       migrateLegacyCommandFields,
+	})
+}
+
+// RegisterMigration adds a migration to the registry.
+// Every format change must register a migration from the previous version.
+func RegisterMigration(m Migration) {
+	if _, exists := migrations[m.From]; exists {
+		panic(fmt.Sprintf("config: duplicate migration from version %d", m.From))
+	}
+	migrations[m.From] = m
+}
+
+// Migrations returns the registered migrations in the order they are applied
+func Migrations() []Migration {
+	result := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].From < result[j].From })
+	return result
+}
+
+// MigrationResult describes the outcome of migrating a spellbook
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Applied     []string // descriptions of the applied migrations
+	Data        []byte   // migrated content, or the original content if unchanged
+	Outdated    bool     // the upgrade changes more than the version key
+}
+
+// Changed reports whether the spellbook had to be upgraded
+func (r *MigrationResult) Changed() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// Migrate upgrades spellbook content to SchemaVersion.
+// Content written by a newer release is rejected instead of being misread.
+func Migrate(data []byte) (*MigrationResult, error) {
+	editor, err := newEditorFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := documentVersion(editor.root)
+	if err != nil {
+		return nil, err
+	}
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("spellbook format version %d is newer than supported version %d; upgrade SilentCast", version, SchemaVersion)
+	}
+
+	result := &MigrationResult{FromVersion: version, ToVersion: version, Data: data}
+	if version == SchemaVersion {
+		return result, nil
+	}
+
+	for v := version; v < SchemaVersion; v++ {
+		m, ok := migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration registered from spellbook format version %d", v)
+		}
+		if err := m.Apply(editor.root); err != nil {
+			return nil, fmt.Errorf("migration from version %d failed: %w", v, err)
+		}
+		result.Applied = append(result.Applied, m.Description)
+	}
+
+	setDocumentVersion(editor.root, SchemaVersion)
+	result.ToVersion = SchemaVersion
+
+	result.Data, err = editor.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	result.Outdated = contentChanged(data, result.Data)
+	return result, nil
+}
+
+// contentChanged reports whether two spellbooks differ in anything but the
+// version key, e.g. an unversioned file that uses no legacy fields does not
+func contentChanged(before, after []byte) bool {
+	var a, b map[string]interface{}
+	if yaml.Unmarshal(before, &a) != nil || yaml.Unmarshal(after, &b) != nil {
+		return true
+	}
+	delete(a, KeyVersion)
+	delete(b, KeyVersion)
+	return !reflect.DeepEqual(a, b)
+}
+
+// documentVersion reads the version key of a top-level mapping
+func documentVersion(root *yaml.Node) (int, error) {
+	idx := findKey(root, KeyVersion)
+	if idx < 0 {
+		return 0, nil
+	}
+	value := root.Content[idx+1]
+	version, err := strconv.Atoi(value.Value)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid spellbook version %q at line %d", value.Value, value.Line)
+	}
+	return version, nil
+}
+
+// setDocumentVersion writes the version key, adding it at the top of the file if missing
+func setDocumentVersion(root *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if idx := findKey(root, KeyVersion); idx >= 0 {
+		value.LineComment = root.Content[idx+1].LineComment
+		root.Content[idx+1] = value
+		return
+	}
+
+	key := scalarNode(KeyVersion)
+	if len(root.Content) > 0 {
+		// Keep a leading file comment above the new key
+		key.HeadComment = root.Content[0].HeadComment
+		root.Content[0].HeadComment = ""
+	}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// migrateLegacyCommandFields converts actions written as `url: ...`, `app: ...`
+// or `script: ...` into the `command` field. Those fields were never read by
+// ActionConfig, so such actions silently had an empty command.
+func migrateLegacyCommandFields(root *yaml.Node) error {
+	idx := findKey(root, KeyActions)
+	if idx < 0 {
+		return nil
+	}
+	grimoire := root.Content[idx+1]
+	if grimoire.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(grimoire.Content); i += 2 {
+		action := grimoire.Content[i+1]
+		if action.Kind != yaml.MappingNode || findKey(action, "command") >= 0 {
+			continue
+		}
+
+		// Prefer the field matching the action type
+		candidates := []string{"app", "script", "url"}
+		if typeIdx := findKey(action, "type"); typeIdx >= 0 {
+			candidates = append([]string{action.Content[typeIdx+1].Value}, candidates...)
+		}
+		for _, field := range candidates {
+			if fieldIdx := findKey(action, field); fieldIdx >= 0 && action.Content[fieldIdx+1].Kind == yaml.ScalarNode {
+				action.Content[fieldIdx].Value = "command"
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantFrom    int
+		wantChanged bool
+		wantOutdate bool
+		wantErr     bool
+		wantContent []string
+		wantMissing []string
+	}{
+		{
+			name: "legacy command fields",
+			input: `# header comment
+spells:
+  g: github
+grimoire:
+  github:
+    type: url
+    url: "https://github.com" # project page
+  editor:
+    type: app
+    app: code
+  build:
+    type: script
+    script: make
+    url: "ignored"
+`,
+			wantFrom:    0,
+			wantChanged: true,
+			wantOutdate: true,
+			wantContent: []string{
+				"# header comment\nversion: 1\n",
+				`command: "https://github.com" # project page`,
+				"command: code",
+				"command: make",
+				`url: "ignored"`,
+			},
+		},
+		{
+			name:        "existing command is kept",
+			input:       "grimoire:\n  x:\n    type: url\n    command: https://a\n    url: https://b\n",
+			wantChanged: true,
+			wantContent: []string{"command: https://a", "url: https://b"},
+		},
+		{
+			name:        "unversioned without legacy fields",
+			input:       "spells:\n  e: editor\ngrimoire:\n  editor:\n    type: app\n    command: code\n",
+			wantChanged: true,
+			wantContent: []string{"version: 1\nspells:"},
+		},
+		{
+			name:        "current version",
+			input:       "version: 1\nspells: {}\n",
+			wantFrom:    1,
+			wantChanged: false,
+		},
+		{
+			name:    "newer version",
+			input:   "version: 2\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			input:   "version: latest\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Migrate([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Migrate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if result.FromVersion != tt.wantFrom {
+				t.Errorf("FromVersion = %d, want %d", result.FromVersion, tt.wantFrom)
+			}
+			if result.Changed() != tt.wantChanged {
+				t.Errorf("Changed() = %v, want %v", result.Changed(), tt.wantChanged)
+			}
+			if result.Outdated != tt.wantOutdate {
+				t.Errorf("Outdated = %v, want %v", result.Outdated, tt.wantOutdate)
+			}
+			if !tt.wantChanged && string(result.Data) != tt.input {
+				t.Errorf("unchanged spellbook was rewritten:\n%s", result.Data)
+			}
+			for _, want := range tt.wantContent {
+				if !strings.Contains(string(result.Data), want) {
+					t.Errorf("migrated data missing %q:\n%s", want, result.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrations_Registry(t *testing.T) {
+	registered := Migrations()
+	for i, m := range registered {
+		if m.From != i {
+			t.Errorf("migration %d upgrades from version %d; chain must be contiguous", i, m.From)
+		}
+		if m.Description == "" {
+			t.Errorf("migration from version %d has no description", m.From)
+		}
+	}
+	if len(registered) != SchemaVersion {
+		t.Errorf("expected %d migrations for schema version %d, got %d", SchemaVersion, SchemaVersion, len(registered))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterMigration() should panic on duplicate version")
+		}
+	}()
+	RegisterMigration(Migration{From: 0, Apply: func(*yaml.Node) error { return nil }})
+}
+
+func TestLoader_LoadsLegacySpellbook(t *testing.T) {
+	path := writeEditorTestConfig(t, `spells:
+  g: github
+grimoire:
+  github:
+    type: url
+    url: "https://github.com"
+`)
+
+	dir := filepath.Dir(path)
+	cfg, err := NewLoader(dir).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Actions["github"].Command != "https://github.com" {
+		t.Errorf("legacy url field not migrated: %+v", cfg.Actions["github"])
+	}
+	if cfg.Version != SchemaVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, SchemaVersion)
+	}
+
+	if err := os.WriteFile(path, []byte("version: 99\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := NewLoader(dir).Load(); err == nil {
+		t.Error("Load() expected error for newer format version")
+	}
+}
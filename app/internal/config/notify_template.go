@@ -0,0 +1,83 @@
+package config
+
+import (
+	"strings"
+	"text/template"
+)
+
+// NotifyTemplateConfig replaces a script's completion notifications with
+// text/template output. Outcomes without a template keep the default text.
+type NotifyTemplateConfig struct {
+	Success  MessageTemplate   `yaml:"success,omitempty"`
+	Warning  MessageTemplate   `yaml:"warning,omitempty"` // Runs marked by output_rules; defaults to success
+	Failure  MessageTemplate   `yaml:"failure,omitempty"`
+	Timeout  MessageTemplate   `yaml:"timeout,omitempty"`
+	Captures map[string]string `yaml:"captures,omitempty"` // Name → regular expression matched against the output
+}
+
+// IsZero reports whether no template is configured
+func (t NotifyTemplateConfig) IsZero() bool {
+	return t.Success.IsZero() && t.Warning.IsZero() && t.Failure.IsZero() && t.Timeout.IsZero() && len(t.Captures) == 0
+}
+
+// WarningTemplate returns the template of runs with warnings, which is the
+// success template unless a warning template is set
+func (t NotifyTemplateConfig) WarningTemplate() MessageTemplate {
+	if t.Warning.IsZero() {
+		return t.Success
+	}
+	return t.Warning
+}
+
+// MessageTemplate is a notification title and message template.
+// A plain string in YAML sets the message only.
+type MessageTemplate struct {
+	Title   string `yaml:"title,omitempty"`
+	Message string `yaml:"message,omitempty"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for MessageTemplate
+func (t *MessageTemplate) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var message string
+	if err := unmarshal(&message); err == nil {
+		*t = MessageTemplate{Message: message}
+		return nil
+	}
+	type plain MessageTemplate
+	return unmarshal((*plain)(t))
+}
+
+// IsZero reports whether neither title nor message is set
+func (t MessageTemplate) IsZero() bool {
+	return t.Title == "" && t.Message == ""
+}
+
+// notifyTemplateFuncs are the functions available in notify_template templates
+var notifyTemplateFuncs = template.FuncMap{
+	"head": func(n int, text string) string {
+		n = max(n, 0)
+		lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+		if n < len(lines) {
+			lines = lines[:n]
+		}
+		return strings.Join(lines, "\n")
+	},
+	"tail": func(n int, text string) string {
+		n = max(n, 0)
+		lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+		if n < len(lines) {
+			lines = lines[len(lines)-n:]
+		}
+		return strings.Join(lines, "\n")
+	},
+	"trim":  strings.TrimSpace,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// ParseNotifyTemplate parses a notify_template title or message.
+// Besides the text/template builtins it provides head and tail (first or
+// last n lines), trim, upper and lower.
+func ParseNotifyTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(notifyTemplateFuncs).Option("missingkey=zero").Parse(text)
+}
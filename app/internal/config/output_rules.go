@@ -0,0 +1,32 @@
+package config
+
+import "regexp"
+
+// Output rule statuses
+const (
+	OutputStatusFailure = "failure" // The run failed, even with exit status 0
+	OutputStatusWarning = "warning" // The run succeeded with warnings
+)
+
+// OutputRule matches a regular expression against each line of a script's
+// output. A matching line can set the status of the run or be shown in the
+// notification, and the rule's named groups become template captures.
+type OutputRule struct {
+	Match  string `yaml:"match"`            // Regular expression matched against each line
+	Status string `yaml:"status,omitempty"` // failure or warning
+	Show   bool   `yaml:"show,omitempty"`   // Show matching lines instead of the whole output
+}
+
+// HasNamedGroups reports whether the rule's pattern has named groups
+func (r OutputRule) HasNamedGroups() bool {
+	re, err := regexp.Compile(r.Match)
+	if err != nil {
+		return false
+	}
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package config
+
+// StdinConfig is the input piped into a script. Exactly one source is set;
+// a plain string in YAML is literal text.
+type StdinConfig struct {
+	Text      string `yaml:"text,omitempty"`      // Literal text
+	File      string `yaml:"file,omitempty"`      // File path, relative to working_dir
+	Clipboard bool   `yaml:"clipboard,omitempty"` // Current clipboard contents
+	Prompt    string `yaml:"prompt,omitempty"`    // Message of a dialog that asks for the input
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for StdinConfig
+func (s *StdinConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err == nil {
+		*s = StdinConfig{Text: text}
+		return nil
+	}
+	type plain StdinConfig
+	return unmarshal((*plain)(s))
+}
+
+// IsZero reports whether no input is configured
+func (s StdinConfig) IsZero() bool {
+	return len(s.Sources()) == 0
+}
+
+// Sources returns the names of the configured input sources
+func (s StdinConfig) Sources() []string {
+	var sources []string
+	if s.Text != "" {
+		sources = append(sources, "text")
+	}
+	if s.File != "" {
+		sources = append(sources, "file")
+	}
+	if s.Clipboard {
+		sources = append(sources, "clipboard")
+	}
+	if s.Prompt != "" {
+		sources = append(sources, "prompt")
+	}
+	return sources
+}
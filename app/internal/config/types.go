@@ -1,15 +1,19 @@
 package config
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/SphereStacking/silentcast/internal/dotenv"
 	"github.com/SphereStacking/silentcast/internal/terminal"
 )
 
 // Config represents the main configuration structure
 type Config struct {
+	Version      int                     `yaml:"version,omitempty"` // Spellbook format version (see SchemaVersion)
 	Daemon       DaemonConfig            `yaml:"daemon"`
 	Hotkeys      HotkeyConfig            `yaml:"hotkeys"`
 	Shortcuts    map[string]string       `yaml:"spells"`   // YAMLでは"spells"だがコードではShortcuts
@@ -19,6 +23,10 @@ type Config struct {
 	Notification NotificationConfig      `yaml:"notification"`
 	Performance  PerformanceConfig       `yaml:"performance"`
 
+	ExecutionLogs ExecutionLogsConfig `yaml:"execution_logs,omitempty"` // Per-spell logs of script output
+	EnvFile       StringList          `yaml:"env_file,omitempty"`       // .env files loaded for every spell
+	SecretsFile   string              `yaml:"secrets_file,omitempty"`   // File of values referenced as ${secret:NAME}
+
 	// Internal fields (not from YAML)
 	prefixExplicitlySet bool `yaml:"-"`
 }
@@ -69,6 +77,14 @@ type LoggerConfig struct {
 	Compress   bool   `yaml:"compress"`    // compress old files
 }
 
+// ExecutionLogsConfig controls the logs of script output kept per spell in
+// the config directory
+type ExecutionLogsConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`  // Log the output of every script run (default: false)
+	MaxRuns int  `yaml:"max_runs,omitempty"` // Runs kept per spell (default: 10)
+	MaxSize int  `yaml:"max_size,omitempty"` // Kilobytes of output logged per run (default: 1024)
+}
+
 // UpdaterConfig contains auto-update settings
 type UpdaterConfig struct {
 	Enabled       bool   `yaml:"enabled"`        // Enable auto-update checks
@@ -77,12 +93,263 @@ type UpdaterConfig struct {
 	Prerelease    bool   `yaml:"prerelease"`     // Include pre-release versions
 }
 
-// NotificationConfig contains notification-related settings
+// NotificationConfig contains notification-related settings.
+// Toggles are pointers so that an unset value can default to true.
 type NotificationConfig struct {
-	EnableTimeout   bool `yaml:"enable_timeout,omitempty"`    // Enable timeout notifications (default: true)
-	EnableWarning   bool `yaml:"enable_warning,omitempty"`    // Enable warning before timeout (default: true)
-	Sound           bool `yaml:"sound,omitempty"`             // Play sound for notifications (default: true)
-	MaxOutputLength int  `yaml:"max_output_length,omitempty"` // Max output length in notifications (default: 1024)
+	EnableTimeout   *bool              `yaml:"enable_timeout,omitempty"`    // Enable timeout notifications (default: true)
+	EnableWarning   *bool              `yaml:"enable_warning,omitempty"`    // Enable warning before timeout (default: true)
+	Sound           *bool              `yaml:"sound,omitempty"`             // Play sound for notifications (default: true)
+	MaxOutputLength int                `yaml:"max_output_length,omitempty"` // Max output length in notifications (default: 1024)
+	Levels          NotificationLevels `yaml:"levels,omitempty"`            // Per-level toggles (default: all enabled)
+	DoNotDisturb    DoNotDisturbConfig `yaml:"do_not_disturb,omitempty"`    // Do-not-disturb behaviour and quiet hours
+	History         HistoryConfig      `yaml:"history,omitempty"`           // On-disk notification history
+	Webhooks        []WebhookConfig    `yaml:"webhooks,omitempty"`          // Notifications posted over HTTP
+	Coalesce        CoalesceConfig     `yaml:"coalesce,omitempty"`          // Merging of repeated notifications
+	File            FileNotifierConfig `yaml:"file,omitempty"`              // Notifications appended as JSON lines
+	Sounds          SoundConfig        `yaml:"sounds,omitempty"`            // Sound cues for the prefix key and results
+}
+
+// Sound cue events
+const (
+	SoundEventPrefix  = "prefix"  // Prefix key pressed
+	SoundEventSuccess = "success" // Success notification
+	SoundEventFailure = "failure" // Error notification
+	SoundEventTimeout = "timeout" // Script stopped by its timeout
+)
+
+// SoundEvents lists the sound cue events in display order
+var SoundEvents = []string{SoundEventPrefix, SoundEventSuccess, SoundEventFailure, SoundEventTimeout}
+
+// Bundled sounds, and the value that silences an event
+const (
+	SoundPop   = "pop"
+	SoundChime = "chime"
+	SoundBuzz  = "buzz"
+	SoundAlarm = "alarm"
+	SoundNone  = "none"
+)
+
+// BundledSounds lists the sounds that ship with SilentCast
+var BundledSounds = []string{SoundPop, SoundChime, SoundBuzz, SoundAlarm}
+
+// SoundPlayers lists the audio players that can be selected by name
+var SoundPlayers = []string{"pw-play", "paplay", "aplay", "afplay", "powershell"}
+
+// SoundConfig controls the sound cues played for the prefix key and spell results.
+// Each event takes a bundled sound name, an audio file path, or "none".
+type SoundConfig struct {
+	Enabled *bool  `yaml:"enabled,omitempty"` // Play sound cues (default: false)
+	Player  string `yaml:"player,omitempty"`  // pw-play, paplay, aplay, afplay, powershell, or auto (default: auto)
+	Volume  int    `yaml:"volume,omitempty"`  // 1-100 (default: 80)
+	Prefix  string `yaml:"prefix,omitempty"`  // Default: pop
+	Success string `yaml:"success,omitempty"` // Default: chime
+	Failure string `yaml:"failure,omitempty"` // Default: buzz
+	Timeout string `yaml:"timeout,omitempty"` // Default: alarm
+}
+
+// SoundsEnabled reports whether sound cues are played, defaulting to false
+func (s SoundConfig) SoundsEnabled() bool {
+	return boolOrDefault(s.Enabled, false)
+}
+
+// Sound returns the sound configured for an event, or its bundled default
+func (s SoundConfig) Sound(event string) string {
+	configured := map[string]string{
+		SoundEventPrefix:  s.Prefix,
+		SoundEventSuccess: s.Success,
+		SoundEventFailure: s.Failure,
+		SoundEventTimeout: s.Timeout,
+	}
+	defaults := map[string]string{
+		SoundEventPrefix:  SoundPop,
+		SoundEventSuccess: SoundChime,
+		SoundEventFailure: SoundBuzz,
+		SoundEventTimeout: SoundAlarm,
+	}
+	if value := configured[event]; value != "" {
+		return value
+	}
+	return defaults[event]
+}
+
+// IsBundledSound reports whether name is one of BundledSounds
+func IsBundledSound(name string) bool {
+	for _, bundled := range BundledSounds {
+		if name == bundled {
+			return true
+		}
+	}
+	return false
+}
+
+// FileNotifierConfig appends every notification as a JSON line to a file or FIFO,
+// for status bars and other tools
+type FileNotifierConfig struct {
+	Path    string   `yaml:"path,omitempty"`     // File or named pipe; ~ and $VAR are expanded (default: disabled)
+	Levels  []string `yaml:"levels,omitempty"`   // Levels written (default: all)
+	MaxSize int      `yaml:"max_size,omitempty"` // Kilobytes before the file is rotated to <path>.1 (default: 1024)
+}
+
+// CoalesceConfig controls how repeated notifications are merged
+type CoalesceConfig struct {
+	Enabled *bool `yaml:"enabled,omitempty"` // Merge repeats (default: true)
+	Window  int   `yaml:"window,omitempty"`  // Seconds during which repeats are merged (default: 10)
+}
+
+// CoalesceEnabled reports whether repeated notifications are merged, defaulting to true
+func (c CoalesceConfig) CoalesceEnabled() bool {
+	return boolOrDefault(c.Enabled, true)
+}
+
+// Webhook types
+const (
+	WebhookTypeGeneric = "webhook" // JSON object with every notification field
+	WebhookTypeSlack   = "slack"   // Slack incoming webhook ({"text": ...})
+	WebhookTypeNtfy    = "ntfy"    // ntfy topic URL, e.g. https://ntfy.sh/my-topic
+)
+
+// WebhookConfig sends notifications to an HTTP endpoint
+type WebhookConfig struct {
+	Name    string            `yaml:"name,omitempty"`    // Shown in logs (default: the URL host)
+	Type    string            `yaml:"type,omitempty"`    // webhook, slack, or ntfy (default: webhook)
+	URL     string            `yaml:"url"`               // Endpoint or topic URL; env files and ${secret:NAME} are expanded
+	Levels  []string          `yaml:"levels,omitempty"`  // Levels sent (default: all)
+	Title   string            `yaml:"title,omitempty"`   // Title template (default: {{.Title}})
+	Message string            `yaml:"message,omitempty"` // Message template (default: message and output)
+	Headers map[string]string `yaml:"headers,omitempty"` // Extra request headers, e.g. Authorization; expanded like url
+	Timeout int               `yaml:"timeout,omitempty"` // Request timeout in seconds (default: 10)
+}
+
+// HistoryConfig controls the notification history kept in the config directory
+type HistoryConfig struct {
+	Enabled    *bool `yaml:"enabled,omitempty"`     // Record notifications (default: true)
+	MaxEntries int   `yaml:"max_entries,omitempty"` // Entries kept (default: 500)
+}
+
+// HistoryEnabled reports whether notifications are recorded, defaulting to true
+func (h HistoryConfig) HistoryEnabled() bool {
+	return boolOrDefault(h.Enabled, true)
+}
+
+// Do-not-disturb modes
+const (
+	DNDModeSuppress  = "suppress"  // Hold notifications without showing them
+	DNDModeDowngrade = "downgrade" // Hold notifications and show them on the console only
+)
+
+// DoNotDisturbConfig controls what happens to notifications while do-not-disturb
+// is active, either toggled manually or during quiet hours
+type DoNotDisturbConfig struct {
+	Mode       string             `yaml:"mode,omitempty"`        // suppress or downgrade (default: suppress)
+	Allow      []string           `yaml:"allow,omitempty"`       // Levels still shown (default: error)
+	QuietHours []QuietHoursConfig `yaml:"quiet_hours,omitempty"` // Scheduled do-not-disturb periods
+}
+
+// QuietHoursConfig is a daily do-not-disturb period in local time
+type QuietHoursConfig struct {
+	Start string   `yaml:"start"`          // "HH:MM"
+	End   string   `yaml:"end"`            // "HH:MM"; earlier than start spans midnight
+	Days  []string `yaml:"days,omitempty"` // Days the period starts on (default: every day)
+}
+
+// weekdayNames maps day names accepted in quiet_hours to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Minutes returns the start and end of the period as minutes after midnight
+func (q QuietHoursConfig) Minutes() (start, end int, err error) {
+	if start, err = parseClock(q.Start); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(q.End); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// Weekdays returns the days the period starts on; nil means every day
+func (q QuietHoursConfig) Weekdays() (map[time.Weekday]bool, error) {
+	if len(q.Days) == 0 {
+		return nil, nil
+	}
+	days := make(map[time.Weekday]bool, len(q.Days))
+	for _, name := range q.Days {
+		key := strings.ToLower(name)
+		if len(key) > 3 {
+			key = key[:3]
+		}
+		day, ok := weekdayNames[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", name)
+		}
+		days[day] = true
+	}
+	return days, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// TimeoutEnabled reports whether timeout notifications are shown
+func (n NotificationConfig) TimeoutEnabled() bool {
+	return boolOrDefault(n.EnableTimeout, true)
+}
+
+// WarningEnabled reports whether a warning is shown before a script times out
+func (n NotificationConfig) WarningEnabled() bool {
+	return boolOrDefault(n.EnableWarning, true)
+}
+
+// SoundEnabled reports whether notifications play a sound
+func (n NotificationConfig) SoundEnabled() bool {
+	return boolOrDefault(n.Sound, true)
+}
+
+// NotificationLevels toggles notifications by level.
+// Unset levels are enabled, or inherit the global setting when used as a per-spell override.
+type NotificationLevels struct {
+	Info    *bool `yaml:"info,omitempty"`
+	Success *bool `yaml:"success,omitempty"`
+	Warning *bool `yaml:"warning,omitempty"`
+	Error   *bool `yaml:"error,omitempty"`
+}
+
+// Merge returns the levels with every toggle set in override taking precedence
+func (l NotificationLevels) Merge(override NotificationLevels) NotificationLevels {
+	if override.Info != nil {
+		l.Info = override.Info
+	}
+	if override.Success != nil {
+		l.Success = override.Success
+	}
+	if override.Warning != nil {
+		l.Warning = override.Warning
+	}
+	if override.Error != nil {
+		l.Error = override.Error
+	}
+	return l
+}
+
+// IsZero reports whether no level is toggled
+func (l NotificationLevels) IsZero() bool {
+	return l.Info == nil && l.Success == nil && l.Warning == nil && l.Error == nil
+}
+
+// boolOrDefault dereferences an optional toggle
+func boolOrDefault(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
 }
 
 // HotkeyConfig contains hotkey-related settings
@@ -105,34 +372,84 @@ func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// MarshalYAML implements yaml.Marshaler for Duration, writing milliseconds
+// so that marshaled configurations can be read back unchanged
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).Milliseconds(), nil
+}
+
 // ToDuration converts Duration to time.Duration
 func (d Duration) ToDuration() time.Duration {
 	return time.Duration(d)
 }
 
+// Script execution modes
+const (
+	ScriptModeWait   = "wait"   // Wait for the script and report a non-zero exit status
+	ScriptModeDetach = "detach" // Run in the background; the exit status is only logged
+	ScriptModeDaemon = "daemon" // Run in its own session, outliving SilentCast, with no timeout
+)
+
 // ActionConfig represents an action that can be executed
 type ActionConfig struct {
 	Type        string            `yaml:"type"`    // "app", "script", or "url"
 	Command     string            `yaml:"command"` // Path or command
 	Args        []string          `yaml:"args,omitempty"`
+	ScriptFile  string            `yaml:"script_file,omitempty"` // Script run instead of command, relative to the config directory
 	Env         map[string]string `yaml:"env,omitempty"`
+	EnvFile     StringList        `yaml:"env_file,omitempty"` // .env files loaded for this spell
 	WorkingDir  string            `yaml:"working_dir,omitempty"`
 	Description string            `yaml:"description,omitempty"`
+	Stdin       StdinConfig       `yaml:"stdin,omitempty"` // Input piped into a script
 
 	// Output control
 	ShowOutput bool `yaml:"show_output,omitempty"` // Show command output as notification
+	CopyOutput bool `yaml:"copy_output,omitempty"` // Copy the output of a successful script to the clipboard
 	KeepOpen   bool `yaml:"keep_open,omitempty"`   // Keep terminal open after execution
 
 	// Execution control
+	Mode           string `yaml:"mode,omitempty"`            // Script execution mode: wait, detach, or daemon (default: wait)
 	Timeout        int    `yaml:"timeout,omitempty"`         // Timeout in seconds (0 = no timeout)
 	GracePeriod    int    `yaml:"grace_period,omitempty"`    // Grace period before SIGKILL in seconds (default: 5)
 	TimeoutWarning int    `yaml:"timeout_warning,omitempty"` // Warning before timeout in seconds (0 = no warning)
 	Shell          string `yaml:"shell,omitempty"`           // Custom shell to use
 	Interpreter    bool   `yaml:"interpreter,omitempty"`     // Run directly in interpreter mode (bypass shell)
+	PTY            bool   `yaml:"pty,omitempty"`             // Run under a pseudo-terminal, for commands that need a TTY
 	Admin          bool   `yaml:"admin,omitempty"`           // Run with elevated privileges
 	Terminal       bool   `yaml:"terminal,omitempty"`        // Force run in terminal
 	ForceTerminal  bool   `yaml:"force_terminal,omitempty"`  // Force terminal even in GUI/tray mode
 
+	// Resource control (Linux)
+	Limits  LimitsConfig  `yaml:"limits,omitempty"`  // Resource limits of the script and its children
+	Sandbox SandboxConfig `yaml:"sandbox,omitempty"` // Process group, environment, filesystem and network isolation
+
+	// Notification control
+	Log            *bool                `yaml:"log,omitempty"`             // Log output of this spell (default: execution_logs.enabled)
+	Notification   NotificationLevels   `yaml:"notification,omitempty"`    // Per-spell notification level overrides
+	NotifyTemplate NotifyTemplateConfig `yaml:"notify_template,omitempty"` // Custom completion notifications for scripts
+	OutputRules    []OutputRule         `yaml:"output_rules,omitempty"`    // Patterns that set the status and shown lines of script output
+
 	// Terminal customization
+	PreferredTerminal     string                  `yaml:"preferred_terminal,omitempty"`     // Terminal emulator by name or command (default: detected)
 	TerminalCustomization *terminal.Customization `yaml:"terminal_customization,omitempty"` // Visual customization for terminal window
 }
+
+// LogEnabled reports whether the output of the spell is logged, defaulting
+// to the execution_logs setting
+func (a ActionConfig) LogEnabled(logs ExecutionLogsConfig) bool {
+	return boolOrDefault(a.Log, logs.Enabled)
+}
+
+// ScriptPath returns the path of the script file, expanded and resolved
+// against configDir with ExpandPath
+func (a ActionConfig) ScriptPath(configDir string) string {
+	return dotenv.ExpandPath(a.ScriptFile, configDir)
+}
+
+// ExecutionMode returns the script execution mode, defaulting to wait
+func (a ActionConfig) ExecutionMode() string {
+	if a.Mode == "" {
+		return ScriptModeWait
+	}
+	return a.Mode
+}
@@ -6,11 +6,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/SphereStacking/silentcast/internal/dotenv"
 )
 
 // ValidationError represents a single validation error with context
@@ -32,32 +37,44 @@ func (e *ValidationError) Error() string {
 
 // Validator performs comprehensive configuration validation
 type Validator struct {
-	config     *Config
-	errors     []*ValidationError
-	yamlNode   *yaml.Node
-	lineMapper map[string]int // Maps field paths to line numbers
+	config       *Config
+	baseDir      string // Directory relative script files are resolved against
+	errors       []*ValidationError
+	yamlNode     *yaml.Node
+	lineMapper   map[string]int // Maps field paths to line numbers
+	columnMapper map[string]int // Maps field paths to column numbers
 }
 
 // NewValidator creates a new configuration validator
 func NewValidator() *Validator {
 	return &Validator{
-		errors:     make([]*ValidationError, 0),
-		lineMapper: make(map[string]int),
+		errors:       make([]*ValidationError, 0),
+		lineMapper:   make(map[string]int),
+		columnMapper: make(map[string]int),
 	}
 }
 
+// SetBaseDir sets the config directory that relative script files are
+// resolved against; without it, they are not checked
+func (v *Validator) SetBaseDir(dir string) {
+	v.baseDir = dir
+}
+
 // Validate performs comprehensive validation on the configuration
 func (v *Validator) Validate(cfg *Config) []*ValidationError {
 	v.config = cfg
 	v.errors = make([]*ValidationError, 0)
 
 	// Validate all sections
+	v.validateVersion()
 	v.validateHotkeys()
 	v.validateDaemon()
 	v.validateLogger()
 	v.validateSpells()
 	v.validateGrimoire()
 	v.validateUpdater()
+	v.validateNotification()
+	v.validateExecutionLogs()
 
 	return v.errors
 }
@@ -96,12 +113,14 @@ func (v *Validator) buildLineMapper(node *yaml.Node, prefix string) {
 				fieldPath = prefix + "." + key
 			}
 			v.lineMapper[fieldPath] = node.Content[i].Line
+			v.columnMapper[fieldPath] = node.Content[i].Column
 			v.buildLineMapper(node.Content[i+1], fieldPath)
 		}
 	case yaml.SequenceNode:
 		for i, item := range node.Content {
 			fieldPath := fmt.Sprintf("%s[%d]", prefix, i)
 			v.lineMapper[fieldPath] = item.Line
+			v.columnMapper[fieldPath] = item.Column
 			v.buildLineMapper(item, fieldPath)
 		}
 	}
@@ -109,16 +128,25 @@ func (v *Validator) buildLineMapper(node *yaml.Node, prefix string) {
 
 // addError adds a validation error
 func (v *Validator) addError(field string, value interface{}, message, suggestion string) {
-	line := v.lineMapper[field]
 	v.errors = append(v.errors, &ValidationError{
 		Field:      field,
 		Value:      value,
 		Message:    message,
 		Suggestion: suggestion,
-		Line:       line,
+		Line:       v.lineMapper[field],
+		Column:     v.columnMapper[field],
 	})
 }
 
+// validateVersion validates the spellbook format version
+func (v *Validator) validateVersion() {
+	if v.config.Version < 0 || v.config.Version > SchemaVersion {
+		v.addError("version", v.config.Version,
+			fmt.Sprintf("unsupported spellbook format version (supported: 0-%d)", SchemaVersion),
+			"Upgrade SilentCast or set the version to one supported by this release")
+	}
+}
+
 // validateHotkeys validates hotkey configuration
 func (v *Validator) validateHotkeys() {
 	// Validate prefix key
@@ -171,7 +199,7 @@ func (v *Validator) validateDaemon() {
 func (v *Validator) validateLogger() {
 	// Validate log file path
 	if v.config.Logger.File != "" {
-		expandedPath := os.ExpandEnv(v.config.Logger.File)
+		expandedPath := dotenv.ExpandPath(v.config.Logger.File, "")
 		dir := filepath.Dir(expandedPath)
 		if _, err := os.Stat(dir); os.IsNotExist(err) {
 			v.addError("logger.file", v.config.Logger.File,
@@ -257,7 +285,20 @@ func (v *Validator) validateGrimoire() {
 		}
 
 		// Validate command
-		if action.Command == "" {
+		if action.ScriptFile != "" {
+			if action.Type != "script" {
+				v.addError(fieldPrefix+".script_file", action.ScriptFile,
+					"script_file only applies to script actions",
+					"Use command, or change the type to script")
+				continue
+			}
+			if action.Command != "" {
+				v.addError(fieldPrefix+".script_file", action.ScriptFile,
+					"command and script_file are mutually exclusive",
+					"Remove command to run the script file, or script_file to run the command")
+				continue
+			}
+		} else if action.Command == "" {
 			v.addError(fieldPrefix+".command", "", "command is required",
 				"Specify the command, application path, or URL")
 			continue
@@ -280,7 +321,10 @@ func (v *Validator) validateGrimoire() {
 
 // validateAppAction validates app-specific action fields
 func (v *Validator) validateAppAction(fieldPrefix string, action *ActionConfig) {
-	expandedCmd := os.ExpandEnv(action.Command)
+	expandedCmd, ok := v.expand(action.Command, action)
+	if !ok {
+		return
+	}
 
 	// Check if it's an absolute path
 	if filepath.IsAbs(expandedCmd) {
@@ -324,6 +368,10 @@ func (v *Validator) validateScriptAction(fieldPrefix string, action *ActionConfi
 		}
 	}
 
+	if action.ScriptFile != "" {
+		v.validateScriptFile(fieldPrefix+".script_file", action)
+	}
+
 	// Validate timeout
 	if action.Timeout < 0 {
 		v.addError(fieldPrefix+".timeout", action.Timeout,
@@ -337,8 +385,8 @@ func (v *Validator) validateScriptAction(fieldPrefix string, action *ActionConfi
 
 	// Validate working directory
 	if action.WorkingDir != "" {
-		expandedDir := os.ExpandEnv(action.WorkingDir)
-		if !strings.Contains(expandedDir, "$") { // Skip if contains unresolved variables
+		// An empty result runs the script in the current directory
+		if expandedDir, ok := v.expand(action.WorkingDir, action); ok && expandedDir != "" {
 			if _, err := os.Stat(expandedDir); os.IsNotExist(err) {
 				v.addError(fieldPrefix+".working_dir", action.WorkingDir,
 					"working directory does not exist",
@@ -348,6 +396,62 @@ func (v *Validator) validateScriptAction(fieldPrefix string, action *ActionConfi
 	}
 }
 
+// expand expands the variables of a value like the executor does, from the
+// env files, the secrets file and the process environment; action is nil for
+// values outside the grimoire. It reports false when the value cannot be
+// expanded, e.g. for a missing env file or an unset ${VAR:?message}, so that
+// checks of the result are skipped.
+func (v *Validator) expand(value string, action *ActionConfig) (string, bool) {
+	scope := dotenv.NewScope(v.baseDir)
+	scope.Configure(v.config.EnvFile, v.config.SecretsFile)
+
+	var spellFiles []string
+	if action != nil {
+		spellFiles = action.EnvFile
+	}
+	vars, err := scope.Resolve(spellFiles)
+	if err != nil {
+		return "", false
+	}
+	expanded, err := vars.Expand(value)
+	if err != nil {
+		return "", false
+	}
+	return expanded, true
+}
+
+// validateScriptFile validates that a script file exists and, unless a
+// shell runs it, is executable
+func (v *Validator) validateScriptFile(field string, action *ActionConfig) {
+	if action.Interpreter {
+		v.addError(field, action.ScriptFile,
+			"interpreter cannot be combined with script_file",
+			"Set the interpreter as shell instead, e.g. 'shell: python3'")
+		return
+	}
+
+	path := action.ScriptPath(v.baseDir)
+	if strings.Contains(path, "$") || !filepath.IsAbs(path) {
+		// Unresolved variables, or relative without a known config directory
+		return
+	}
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		v.addError(field, action.ScriptFile,
+			"script file does not exist",
+			fmt.Sprintf("Create %s; relative paths are resolved against the config directory", path))
+	case info.IsDir():
+		v.addError(field, action.ScriptFile,
+			"script file is a directory",
+			"Use the path of the script itself")
+	case action.Shell == "" && runtime.GOOS != "windows" && info.Mode()&0o111 == 0:
+		v.addError(field, action.ScriptFile,
+			"script file is not executable",
+			fmt.Sprintf("Run 'chmod +x %s', or set shell to run it with, e.g. 'shell: bash'", path))
+	}
+}
+
 // validateURLAction validates URL-specific action fields
 func (v *Validator) validateURLAction(fieldPrefix string, action *ActionConfig) {
 	urlStr := strings.TrimSpace(action.Command)
@@ -386,6 +490,42 @@ func (v *Validator) validateURLAction(fieldPrefix string, action *ActionConfig)
 	}
 }
 
+// validateMode validates the script execution mode and the options that
+// only work when SilentCast waits for the script
+func (v *Validator) validateMode(field string, action *ActionConfig) {
+	switch action.Mode {
+	case "":
+		return
+	case ScriptModeWait, ScriptModeDetach, ScriptModeDaemon:
+	default:
+		v.addError(field, action.Mode,
+			"invalid execution mode",
+			"Use wait, detach, or daemon")
+		return
+	}
+
+	if action.Type != "script" {
+		v.addError(field, action.Mode,
+			"mode only applies to script actions",
+			"Remove mode or change the type to script")
+		return
+	}
+
+	if action.Mode == ScriptModeWait {
+		return
+	}
+	if action.ShowOutput || !action.NotifyTemplate.IsZero() || action.TimeoutWarning > 0 || len(action.OutputRules) > 0 {
+		v.addError(field, action.Mode,
+			"detached scripts are not reported in notifications",
+			"Use mode: wait with show_output, notify_template, output_rules or timeout_warning")
+	}
+	if action.Mode == ScriptModeDaemon && action.Timeout > 0 {
+		v.addError(field, action.Mode,
+			"daemon scripts have no timeout",
+			"Remove timeout, or use mode: detach to stop the script after a timeout")
+	}
+}
+
 // validateCommonActionFields validates fields common to all action types
 func (v *Validator) validateCommonActionFields(fieldPrefix string, action *ActionConfig) {
 	// Validate mutually exclusive options
@@ -401,12 +541,54 @@ func (v *Validator) validateCommonActionFields(fieldPrefix string, action *Actio
 			"Remove the terminal option")
 	}
 
-	if action.KeepOpen && !action.Terminal {
+	if action.KeepOpen && !action.Terminal && !action.ForceTerminal {
 		v.addError(fieldPrefix+".keep_open", true,
 			"keep_open requires terminal to be true",
 			"Add 'terminal: true' or remove keep_open")
 	}
 
+	if (action.PreferredTerminal != "" || action.TerminalCustomization != nil) && action.Type != "script" {
+		v.addError(fieldPrefix+".preferred_terminal", action.PreferredTerminal,
+			"preferred_terminal and terminal_customization only apply to script actions",
+			"Remove them or change the type to script")
+	}
+
+	v.validateMode(fieldPrefix+".mode", action)
+
+	if action.Log != nil && action.Type != "script" {
+		v.addError(fieldPrefix+".log", *action.Log,
+			"log only applies to script actions",
+			"Remove log or change the type to script")
+	}
+
+	if action.Interpreter && action.Type != "script" {
+		v.addError(fieldPrefix+".interpreter", true,
+			"interpreter only applies to script actions",
+			"Remove interpreter or change the type to script")
+	}
+
+	if action.PTY {
+		v.validatePTY(fieldPrefix+".pty", action)
+	}
+
+	v.validateEnvironment(fieldPrefix, action)
+	if !action.Limits.IsZero() || action.Sandbox.Enabled {
+		v.validateContainment(fieldPrefix, action)
+	}
+	v.validateStdin(fieldPrefix+".stdin", action)
+	if action.CopyOutput {
+		v.validateCopyOutput(fieldPrefix+".copy_output", action)
+	}
+
+	if action.TimeoutWarning > 0 && (action.Timeout <= 0 || action.TimeoutWarning >= action.Timeout) {
+		v.addError(fieldPrefix+".timeout_warning", action.TimeoutWarning,
+			"timeout_warning must be shorter than timeout",
+			"Set timeout, or use a warning of fewer seconds than the timeout")
+	}
+
+	v.validateNotifyTemplate(fieldPrefix+".notify_template", action)
+	v.validateOutputRules(fieldPrefix+".output_rules", action)
+
 	if action.Admin && runtime.GOOS == "linux" {
 		// Check for elevation tools on Linux
 		tools := []string{"pkexec", "gksudo", "kdesudo", "sudo"}
@@ -487,3 +669,436 @@ func validateSpellKey(key string) error {
 
 	return nil
 }
+
+// validateNotifyTemplate validates a spell's notify_template templates and captures
+func (v *Validator) validateNotifyTemplate(field string, action *ActionConfig) {
+	tmpl := action.NotifyTemplate
+	if tmpl.IsZero() {
+		return
+	}
+	if action.Type != "script" {
+		v.addError(field, nil,
+			"notify_template only applies to script actions",
+			"Remove notify_template or change the type to script")
+		return
+	}
+
+	outcomes := []struct {
+		name     string
+		template MessageTemplate
+	}{{"success", tmpl.Success}, {"warning", tmpl.Warning}, {"failure", tmpl.Failure}, {"timeout", tmpl.Timeout}}
+	for _, outcome := range outcomes {
+		parts := []struct{ name, text string }{{"title", outcome.template.Title}, {"message", outcome.template.Message}}
+		for _, part := range parts {
+			if _, err := ParseNotifyTemplate(outcome.name, part.text); err != nil {
+				v.addError(field+"."+outcome.name+"."+part.name, part.text, "invalid template: "+err.Error(),
+					"Use Go template syntax, e.g. 'Deployed {{.Captures.version}} in {{.Duration}}'")
+			}
+		}
+	}
+
+	names := make([]string, 0, len(tmpl.Captures))
+	for name := range tmpl.Captures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := regexp.Compile(tmpl.Captures[name]); err != nil {
+			v.addError(field+".captures."+name, tmpl.Captures[name], "invalid regular expression: "+err.Error(),
+				"Use Go regexp syntax; the first group is captured, e.g. 'version (v[0-9.]+)'")
+		}
+	}
+}
+
+// validatePTY validates that a script can run under a pseudo-terminal
+func (v *Validator) validatePTY(field string, action *ActionConfig) {
+	switch {
+	case action.Type != "script":
+		v.addError(field, true,
+			"pty only applies to script actions",
+			"Remove pty or change the type to script")
+	case runtime.GOOS == "windows":
+		v.addError(field, true,
+			"pty is not supported on Windows",
+			"Remove pty, or move it to spellbook.linux.yml or spellbook.mac.yml")
+	case action.Terminal || action.ForceTerminal || action.KeepOpen:
+		v.addError(field, true,
+			"pty and terminal are mutually exclusive",
+			"Use pty to capture TTY output headlessly, or terminal to open a window")
+	case action.ExecutionMode() != ScriptModeWait:
+		v.addError(field, true,
+			"pty requires mode: wait",
+			"Remove pty, or remove mode so that SilentCast reads the terminal until the script exits")
+	}
+}
+
+// validateStdin validates the input piped into a script
+func (v *Validator) validateStdin(field string, action *ActionConfig) {
+	sources := action.Stdin.Sources()
+	switch {
+	case len(sources) == 0:
+		return
+	case action.Type != "script":
+		v.addError(field, nil,
+			"stdin only applies to script actions",
+			"Remove stdin or change the type to script")
+	case len(sources) > 1:
+		v.addError(field, strings.Join(sources, ", "),
+			"stdin has more than one source",
+			"Use one of text, file, clipboard or prompt")
+	case action.Terminal || action.ForceTerminal || action.KeepOpen || action.PTY:
+		v.addError(field, sources[0],
+			"stdin cannot be piped into a terminal",
+			"Remove stdin, or terminal, force_terminal, keep_open and pty")
+	}
+}
+
+// validateEnvironment validates env files and ${secret:NAME} references.
+// Secrets are only passed in env, since the command, args and working_dir
+// show up in logs, notifications and process lists.
+func (v *Validator) validateEnvironment(fieldPrefix string, action *ActionConfig) {
+	if len(action.EnvFile) > 0 && action.Type != "app" && action.Type != "script" {
+		v.addError(fieldPrefix+".env_file", strings.Join(action.EnvFile, ", "),
+			"env_file only applies to app and script actions",
+			"Remove env_file or change the type to app or script")
+	}
+
+	fields := []string{fieldPrefix + ".command", fieldPrefix + ".script_file", fieldPrefix + ".working_dir"}
+	values := []string{action.Command, action.ScriptFile, action.WorkingDir}
+	for i, arg := range action.Args {
+		fields = append(fields, fmt.Sprintf("%s.args[%d]", fieldPrefix, i))
+		values = append(values, arg)
+	}
+	for i, value := range values {
+		if strings.Contains(value, "${"+dotenv.SecretPrefix) {
+			v.addError(fields[i], value,
+				"secrets can only be referenced in env",
+				"Pass the secret in env, e.g. 'env: {API_TOKEN: ${secret:API_TOKEN}}', and use $API_TOKEN in the script")
+		}
+	}
+
+	if v.config == nil || v.config.SecretsFile != "" {
+		return
+	}
+	for k, value := range action.Env {
+		if strings.Contains(value, "${"+dotenv.SecretPrefix) {
+			v.addError(fieldPrefix+".env."+k, value,
+				"secret is referenced, but no secrets_file is configured",
+				"Add 'secrets_file: ~/.config/silentcast/secrets.env' and chmod 600 the file")
+		}
+	}
+}
+
+// validateContainment validates the limits and sandbox of a script, which
+// SilentCast applies itself on Linux
+func (v *Validator) validateContainment(fieldPrefix string, action *ActionConfig) {
+	field := fieldPrefix + ".limits"
+	if action.Limits.IsZero() {
+		field = fieldPrefix + ".sandbox"
+	}
+	switch {
+	case action.Type != "script":
+		v.addError(field, nil,
+			"limits and sandbox only apply to script actions",
+			"Remove them or change the type to script")
+		return
+	case runtime.GOOS != "linux":
+		v.addError(field, nil,
+			"limits and sandbox are only supported on Linux",
+			"Move them to spellbook.linux.yml")
+	case action.Terminal || action.ForceTerminal || action.KeepOpen:
+		v.addError(field, nil,
+			"limits and sandbox cannot be applied to scripts run in a terminal",
+			"Remove them, or terminal, force_terminal and keep_open")
+	case action.Admin:
+		v.addError(field, nil,
+			"limits and sandbox cannot be combined with admin",
+			"Remove admin, or limits and sandbox")
+	}
+
+	limits := action.Limits
+	for _, size := range []struct{ name, value string }{{"memory", limits.Memory}, {"file_size", limits.FileSize}} {
+		if size.value == "" {
+			continue
+		}
+		if _, err := ParseSize(size.value); err != nil {
+			v.addError(fieldPrefix+".limits."+size.name, size.value,
+				"invalid size",
+				"Use bytes or a K, M, G or T suffix, e.g. 512M")
+		}
+	}
+	if limits.CPUTime < 0 {
+		v.addError(fieldPrefix+".limits.cpu_time", limits.CPUTime,
+			"cpu_time cannot be negative",
+			"Use seconds of CPU time, or remove it for no limit")
+	}
+	if limits.Processes < 0 {
+		v.addError(fieldPrefix+".limits.processes", limits.Processes,
+			"processes cannot be negative",
+			"Use the number of processes, or remove it for no limit")
+	}
+	if limits.Nice < 0 || limits.Nice > 19 {
+		v.addError(fieldPrefix+".limits.nice", limits.Nice,
+			"nice must be between 0 and 19",
+			"Use 19 for the lowest CPU priority, or 0 to leave it unchanged; scripts cannot raise their priority")
+	}
+	if limits.IONice != "" {
+		if _, _, err := ParseIONice(limits.IONice); err != nil {
+			v.addError(fieldPrefix+".limits.ionice", limits.IONice,
+				"invalid ionice",
+				"Use idle, or a best-effort level from 0 (highest) to 7 (lowest)")
+		}
+	}
+
+	for i, path := range action.Sandbox.ReadOnly {
+		if strings.TrimSpace(path) == "" {
+			v.addError(fmt.Sprintf("%s.sandbox.read_only[%d]", fieldPrefix, i), path,
+				"read_only path is empty",
+				"Use a path, e.g. ~ or /etc")
+		}
+	}
+	for i, name := range action.Sandbox.Env {
+		if !envNameRegex.MatchString(name) {
+			v.addError(fmt.Sprintf("%s.sandbox.env[%d]", fieldPrefix, i), name,
+				"invalid environment variable name",
+				"Use the names of variables to pass, e.g. DISPLAY")
+		}
+	}
+}
+
+// envNameRegex matches the names of environment variables
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateCopyOutput validates copying a script's output to the clipboard
+func (v *Validator) validateCopyOutput(field string, action *ActionConfig) {
+	switch {
+	case action.Type != "script":
+		v.addError(field, true,
+			"copy_output only applies to script actions",
+			"Remove copy_output or change the type to script")
+	case action.Terminal || action.ForceTerminal || action.KeepOpen:
+		v.addError(field, true,
+			"copy_output cannot copy the output of scripts run in a terminal",
+			"Remove copy_output, or terminal, force_terminal and keep_open")
+	case action.ExecutionMode() != ScriptModeWait:
+		v.addError(field, true,
+			"copy_output requires mode: wait",
+			"Remove mode so that SilentCast waits for the output")
+	}
+}
+
+// validateOutputRules validates a spell's output_rules patterns and statuses
+func (v *Validator) validateOutputRules(field string, action *ActionConfig) {
+	if len(action.OutputRules) == 0 {
+		return
+	}
+	if action.Type != "script" {
+		v.addError(field, nil,
+			"output_rules only applies to script actions",
+			"Remove output_rules or change the type to script")
+		return
+	}
+	if action.Terminal || action.ForceTerminal || action.KeepOpen {
+		v.addError(field, nil,
+			"output_rules cannot match the output of scripts run in a terminal",
+			"Remove output_rules, or terminal, force_terminal and keep_open")
+	}
+
+	for i, rule := range action.OutputRules {
+		ruleField := fmt.Sprintf("%s[%d]", field, i)
+		if rule.Match == "" {
+			v.addError(ruleField+".match", rule.Match,
+				"match is required",
+				"Add a regular expression matched against each line, e.g. '^FAIL'")
+			continue
+		}
+		if _, err := regexp.Compile(rule.Match); err != nil {
+			v.addError(ruleField+".match", rule.Match, "invalid regular expression: "+err.Error(),
+				"Use Go regexp syntax, e.g. '(?i)^error:'")
+			continue
+		}
+		switch rule.Status {
+		case "", OutputStatusFailure, OutputStatusWarning:
+		default:
+			v.addError(ruleField+".status", rule.Status,
+				"invalid output rule status",
+				"Use failure or warning")
+		}
+		if rule.Status == "" && !rule.Show && !rule.HasNamedGroups() {
+			v.addError(ruleField, rule.Match,
+				"output rule has no effect",
+				"Set status or show, or capture a named group, e.g. '(?P<failed>[0-9]+) failed'")
+		}
+	}
+}
+
+// validateExecutionLogs validates the per-spell logs of script output
+func (v *Validator) validateExecutionLogs() {
+	logs := v.config.ExecutionLogs
+	if logs.MaxRuns < 0 {
+		v.addError("execution_logs.max_runs", logs.MaxRuns,
+			"max_runs cannot be negative",
+			"Use 0 for the default of 10 runs per spell")
+	}
+	if logs.MaxSize < 0 {
+		v.addError("execution_logs.max_size", logs.MaxSize,
+			"max_size cannot be negative",
+			"Use 0 for the default of 1024 KB per run")
+	}
+}
+
+// validateNotification validates notification configuration
+func (v *Validator) validateNotification() {
+	if v.config.Notification.MaxOutputLength < 0 {
+		v.addError("notification.max_output_length", v.config.Notification.MaxOutputLength,
+			"max_output_length cannot be negative",
+			"Use 0 for the default of 1024 bytes")
+	}
+
+	if v.config.Notification.History.MaxEntries < 0 {
+		v.addError("notification.history.max_entries", v.config.Notification.History.MaxEntries,
+			"max_entries cannot be negative",
+			"Use 0 for the default of 500 entries, or set enabled: false")
+	}
+
+	if v.config.Notification.Coalesce.Window < 0 {
+		v.addError("notification.coalesce.window", v.config.Notification.Coalesce.Window,
+			"window cannot be negative",
+			"Use 0 for the default of 10 seconds, or set enabled: false")
+	}
+
+	dnd := v.config.Notification.DoNotDisturb
+	if dnd.Mode != "" && dnd.Mode != DNDModeSuppress && dnd.Mode != DNDModeDowngrade {
+		v.addError("notification.do_not_disturb.mode", dnd.Mode,
+			"invalid do-not-disturb mode",
+			"Use 'suppress' or 'downgrade'")
+	}
+	for i, level := range dnd.Allow {
+		switch strings.ToLower(level) {
+		case "info", "success", "warning", "error":
+		default:
+			v.addError(fmt.Sprintf("notification.do_not_disturb.allow[%d]", i), level,
+				"invalid notification level",
+				"Use info, success, warning, or error")
+		}
+	}
+	for i, period := range dnd.QuietHours {
+		field := fmt.Sprintf("notification.do_not_disturb.quiet_hours[%d]", i)
+		start, end, err := period.Minutes()
+		if err != nil {
+			v.addError(field, period.Start+"-"+period.End, err.Error(), "Use 24-hour times like '22:00' and '07:30'")
+			continue
+		}
+		if start == end {
+			v.addError(field, period.Start+"-"+period.End,
+				"quiet hours start and end at the same time",
+				"Use different start and end times; periods may span midnight")
+		}
+		if _, err := period.Weekdays(); err != nil {
+			v.addError(field+".days", period.Days, err.Error(), "Use day names like mon, tue, wed")
+		}
+	}
+
+	for i, webhook := range v.config.Notification.Webhooks {
+		v.validateWebhook(fmt.Sprintf("notification.webhooks[%d]", i), webhook)
+	}
+
+	v.validateSounds(v.config.Notification.Sounds)
+
+	if v.config.Notification.File.MaxSize < 0 {
+		v.addError("notification.file.max_size", v.config.Notification.File.MaxSize,
+			"max_size cannot be negative",
+			"Use 0 for the default of 1024 KB")
+	}
+
+	for i, level := range v.config.Notification.File.Levels {
+		switch strings.ToLower(level) {
+		case "info", "success", "warning", "error":
+		default:
+			v.addError(fmt.Sprintf("notification.file.levels[%d]", i), level,
+				"invalid notification level",
+				"Use info, success, warning, or error")
+		}
+	}
+}
+
+// validateSounds validates the sound cue settings
+func (v *Validator) validateSounds(sounds SoundConfig) {
+	if sounds.Volume < 0 || sounds.Volume > 100 {
+		v.addError("notification.sounds.volume", sounds.Volume,
+			"volume must be between 1 and 100",
+			"Use 0 for the default of 80")
+	}
+
+	if sounds.Player != "" && sounds.Player != "auto" {
+		known := false
+		for _, player := range SoundPlayers {
+			if sounds.Player == player {
+				known = true
+				break
+			}
+		}
+		if !known {
+			v.addError("notification.sounds.player", sounds.Player,
+				"unknown sound player",
+				"Use auto, or one of "+strings.Join(SoundPlayers, ", "))
+		}
+	}
+
+	for _, event := range SoundEvents {
+		sound := sounds.Sound(event)
+		if sound == SoundNone || IsBundledSound(sound) {
+			continue
+		}
+		// Anything else is a file; a bare word is most likely a misspelt bundled sound
+		if !strings.ContainsAny(sound, `/\`) && filepath.Ext(sound) == "" {
+			v.addError("notification.sounds."+event, sound,
+				"unknown sound",
+				"Use "+strings.Join(BundledSounds, ", ")+", none, or the path of an audio file")
+		}
+	}
+}
+
+// validateWebhook validates a webhook notifier
+func (v *Validator) validateWebhook(field string, webhook WebhookConfig) {
+	switch webhook.Type {
+	case "", WebhookTypeGeneric, WebhookTypeSlack, WebhookTypeNtfy:
+	default:
+		v.addError(field+".type", webhook.Type,
+			"invalid webhook type",
+			"Use 'webhook', 'slack', or 'ntfy'")
+	}
+
+	if webhook.URL == "" {
+		v.addError(field+".url", webhook.URL, "webhook URL is required", "Set the endpoint, e.g. https://ntfy.sh/my-topic")
+	} else if expandedURL, ok := v.expand(webhook.URL, nil); ok {
+		// A URL whose variables cannot be expanded yet is not checked
+		if u, err := url.Parse(expandedURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.addError(field+".url", webhook.URL, "invalid webhook URL", "Use an http:// or https:// URL")
+		} else if webhook.Type == WebhookTypeNtfy && strings.Trim(u.Path, "/") == "" {
+			v.addError(field+".url", webhook.URL, "ntfy URL has no topic", "Append the topic, e.g. https://ntfy.sh/my-topic")
+		}
+	}
+
+	for i, level := range webhook.Levels {
+		switch strings.ToLower(level) {
+		case "info", "success", "warning", "error":
+		default:
+			v.addError(fmt.Sprintf("%s.levels[%d]", field, i), level,
+				"invalid notification level",
+				"Use info, success, warning, or error")
+		}
+	}
+
+	templates := []struct{ name, text string }{{"title", webhook.Title}, {"message", webhook.Message}}
+	for _, tmpl := range templates {
+		if _, err := template.New(tmpl.name).Parse(tmpl.text); err != nil {
+			v.addError(field+"."+tmpl.name, tmpl.text, "invalid template: "+err.Error(),
+				"Use Go template syntax, e.g. '{{.Spell}}: {{.Title}}'")
+		}
+	}
+
+	if webhook.Timeout < 0 {
+		v.addError(field+".timeout", webhook.Timeout, "timeout cannot be negative", "Use 0 for the default of 10 seconds")
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -382,6 +384,307 @@ func TestValidator_ValidateGrimoire(t *testing.T) {
 			},
 			wantErr: []string{"keep_open requires terminal"},
 		},
+		{
+			name: "preferred terminal on app action",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"editor": {
+						Type:              "app",
+						Command:           "code",
+						PreferredTerminal: "kitty",
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"only apply to script actions"},
+		},
+		{
+			name: "interpreter on URL action",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"docs": {
+						Type:        "url",
+						Command:     "https://example.com",
+						Interpreter: true,
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"interpreter only applies to script actions"},
+		},
+		{
+			name: "log on app action and negative log retention",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"editor": {
+						Type:    "app",
+						Command: "code",
+						Log:     &[]bool{true}[0],
+					},
+				},
+				ExecutionLogs:       ExecutionLogsConfig{MaxRuns: -1},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"log only applies to script actions", "max_runs cannot be negative"},
+		},
+		{
+			name: "invalid output rules",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"test": {
+						Type:    "script",
+						Command: "go test ./...",
+						OutputRules: []OutputRule{
+							{Match: "^FAIL", Status: "error"},
+							{Match: "("},
+							{Match: "^ok"},
+							{Match: `(?P<failed>[0-9]+) failed`},
+						},
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"invalid output rule status", "invalid regular expression", "output rule has no effect"},
+		},
+		{
+			name: "output rules on detached script",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"test": {
+						Type:        "script",
+						Command:     "go test ./...",
+						Mode:        ScriptModeDetach,
+						OutputRules: []OutputRule{{Match: "^FAIL", Status: OutputStatusFailure}},
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"detached scripts are not reported in notifications"},
+		},
+		{
+			name: "pty with terminal and on app action",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"top": {
+						Type:     "script",
+						Command:  "htop",
+						PTY:      true,
+						Terminal: true,
+					},
+					"editor": {
+						Type:    "app",
+						Command: "code",
+						PTY:     true,
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"pty and terminal are mutually exclusive", "pty only applies to script actions"},
+		},
+		{
+			name: "stdin with two sources and copy_output on detached script",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"translate": {
+						Type:    "script",
+						Command: "trans -b",
+						Stdin:   StdinConfig{Clipboard: true, Prompt: "Text"},
+					},
+					"format": {
+						Type:       "script",
+						Command:    "jq .",
+						Mode:       ScriptModeDetach,
+						CopyOutput: true,
+					},
+					"editor": {
+						Type:    "app",
+						Command: "code",
+						Stdin:   StdinConfig{File: "notes.txt"},
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"stdin has more than one source", "copy_output requires mode: wait", "stdin only applies to script actions"},
+		},
+		{
+			name: "secret referenced in command",
+			config: Config{
+				SecretsFile: "secrets.env",
+				Hotkeys:     HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"deploy": {Type: "script", Command: "curl -H 'Authorization: ${secret:API_TOKEN}' example.com"},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"secrets can only be referenced in env"},
+		},
+		{
+			name: "secret without secrets_file",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"deploy": {Type: "script", Command: "./deploy.sh", Env: map[string]string{"API_TOKEN": "${secret:API_TOKEN}"}},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"no secrets_file is configured"},
+		},
+		{
+			name: "env_file on url action",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"docs": {Type: "url", Command: "https://example.com", EnvFile: StringList{".env"}},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"env_file only applies to app and script actions"},
+		},
+		{
+			name: "invalid limits",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"build": {
+						Type:    "script",
+						Command: "make -j",
+						Limits:  LimitsConfig{Memory: "lots", Nice: 25, IONice: "realtime"},
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"invalid size", "nice must be between 0 and 19", "invalid ionice"},
+		},
+		{
+			name: "sandbox in terminal",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"htop": {
+						Type:     "script",
+						Command:  "htop",
+						Terminal: true,
+						Sandbox:  SandboxConfig{Enabled: true, Env: []string{"NOT-A-NAME"}},
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"cannot be applied to scripts run in a terminal", "invalid environment variable name"},
+		},
+		{
+			name: "limits on app action",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"editor": {Type: "app", Command: "code", Limits: LimitsConfig{Nice: 10}},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"limits and sandbox only apply to script actions"},
+		},
+		{
+			name: "command and script_file",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"backup": {Type: "script", Command: "./backup.sh", ScriptFile: "scripts/backup.sh"},
+					"editor": {Type: "app", ScriptFile: "scripts/editor.sh"},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"command and script_file are mutually exclusive", "script_file only applies to script actions"},
+		},
+		{
+			name: "invalid execution mode",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"build": {
+						Type:    "script",
+						Command: "make build",
+						Mode:    "background",
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"invalid execution mode"},
+		},
+		{
+			name: "detached script with show_output",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"build": {
+						Type:       "script",
+						Command:    "make build",
+						Mode:       ScriptModeDetach,
+						ShowOutput: true,
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"not reported in notifications"},
+		},
+		{
+			name: "daemon with timeout",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"server": {
+						Type:    "script",
+						Command: "python3 -m http.server",
+						Mode:    ScriptModeDaemon,
+						Timeout: 60,
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"daemon scripts have no timeout"},
+		},
+		{
+			name: "mode on app action",
+			config: Config{
+				Hotkeys: HotkeyConfig{
+					Prefix: "alt+space",
+				},
+				Actions: map[string]ActionConfig{
+					"editor": {
+						Type:    "app",
+						Command: "code",
+						Mode:    ScriptModeDetach,
+					},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: []string{"mode only applies to script actions"},
+		},
 		{
 			name: "invalid URL format",
 			config: Config{
@@ -487,6 +790,9 @@ grimoire:
 			if err.Line == 0 {
 				t.Error("Expected line number to be set")
 			}
+			if err.Column == 0 {
+				t.Error("Expected column number to be set")
+			}
 			break
 		}
 	}
@@ -699,6 +1005,325 @@ func TestValidator_UpdaterValidation(t *testing.T) {
 	}
 }
 
+func TestValidator_NotificationValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+		noErr   bool
+	}{
+		{
+			name: "valid notification config",
+			config: Config{
+				Hotkeys:      HotkeyConfig{Prefix: "alt+space"},
+				Notification: NotificationConfig{MaxOutputLength: 2048},
+				Actions: map[string]ActionConfig{
+					"build": {Type: "script", Command: "make", Timeout: 60, TimeoutWarning: 10},
+				},
+				prefixExplicitlySet: true,
+			},
+			noErr: true,
+		},
+		{
+			name: "negative max output length",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{MaxOutputLength: -1},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "max_output_length cannot be negative",
+		},
+		{
+			name: "timeout warning without timeout",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"build": {Type: "script", Command: "make", TimeoutWarning: 10},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "timeout_warning must be shorter than timeout",
+		},
+		{
+			name: "timeout warning longer than timeout",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"build": {Type: "script", Command: "make", Timeout: 5, TimeoutWarning: 5},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "timeout_warning must be shorter than timeout",
+		},
+		{
+			name: "valid quiet hours",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Notification: NotificationConfig{DoNotDisturb: DoNotDisturbConfig{
+					Mode:       DNDModeDowngrade,
+					Allow:      []string{"error", "warning"},
+					QuietHours: []QuietHoursConfig{{Start: "22:00", End: "07:00", Days: []string{"mon", "Friday"}}},
+				}},
+				prefixExplicitlySet: true,
+			},
+			noErr: true,
+		},
+		{
+			name: "invalid do-not-disturb mode",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{DoNotDisturb: DoNotDisturbConfig{Mode: "silent"}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid do-not-disturb mode",
+		},
+		{
+			name: "invalid allowed level",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{DoNotDisturb: DoNotDisturbConfig{Allow: []string{"critical"}}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid notification level",
+		},
+		{
+			name: "invalid quiet hours time",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Notification: NotificationConfig{DoNotDisturb: DoNotDisturbConfig{
+					QuietHours: []QuietHoursConfig{{Start: "25:00", End: "07:00"}},
+				}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "expected HH:MM",
+		},
+		{
+			name: "empty quiet hours period",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Notification: NotificationConfig{DoNotDisturb: DoNotDisturbConfig{
+					QuietHours: []QuietHoursConfig{{Start: "09:00", End: "09:00"}},
+				}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "start and end at the same time",
+		},
+		{
+			name: "negative history size",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{History: HistoryConfig{MaxEntries: -5}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "max_entries cannot be negative",
+		},
+		{
+			name: "negative coalesce window",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Coalesce: CoalesceConfig{Window: -1}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "window cannot be negative",
+		},
+		{
+			name: "invalid notification file level",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{File: FileNotifierConfig{Path: "/tmp/events", Levels: []string{"loud"}}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid notification level",
+		},
+		{
+			name: "sound volume out of range",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Sounds: SoundConfig{Volume: 150}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "volume must be between 1 and 100",
+		},
+		{
+			name: "unknown sound player",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Sounds: SoundConfig{Player: "winamp"}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "unknown sound player",
+		},
+		{
+			name: "misspelt bundled sound",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Sounds: SoundConfig{Success: "chimes"}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "unknown sound",
+		},
+		{
+			name: "valid sound files",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Notification: NotificationConfig{Sounds: SoundConfig{
+					Player: "paplay", Prefix: "none", Success: "~/sounds/done.ogg", Failure: "buzz",
+				}},
+				prefixExplicitlySet: true,
+			},
+			noErr: true,
+		},
+		{
+			name: "unknown quiet hours day",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Notification: NotificationConfig{DoNotDisturb: DoNotDisturbConfig{
+					QuietHours: []QuietHoursConfig{{Start: "09:00", End: "17:00", Days: []string{"someday"}}},
+				}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "unknown day",
+		},
+		{
+			name: "valid notify template",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"deploy": {Type: "script", Command: "make deploy", NotifyTemplate: NotifyTemplateConfig{
+						Success:  MessageTemplate{Title: "{{.Spell}}", Message: "Deployed {{.Captures.version}} in {{.Duration}}"},
+						Failure:  MessageTemplate{Message: "{{tail 5 .Output}}"},
+						Captures: map[string]string{"version": `v[0-9.]+`},
+					}},
+				},
+				prefixExplicitlySet: true,
+			},
+			noErr: true,
+		},
+		{
+			name: "invalid notify template",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"deploy": {Type: "script", Command: "make deploy", NotifyTemplate: NotifyTemplateConfig{
+						Timeout: MessageTemplate{Title: "{{.Spell"},
+					}},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid template",
+		},
+		{
+			name: "invalid notify template capture",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"deploy": {Type: "script", Command: "make deploy", NotifyTemplate: NotifyTemplateConfig{
+						Captures: map[string]string{"version": `(v[0-9.]+`},
+					}},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid regular expression",
+		},
+		{
+			name: "notify template on url action",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Actions: map[string]ActionConfig{
+					"docs": {Type: "url", Command: "https://example.com", NotifyTemplate: NotifyTemplateConfig{
+						Success: MessageTemplate{Message: "opened"},
+					}},
+				},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "notify_template only applies to script actions",
+		},
+		{
+			name: "valid webhooks",
+			config: Config{
+				Hotkeys: HotkeyConfig{Prefix: "alt+space"},
+				Notification: NotificationConfig{Webhooks: []WebhookConfig{
+					{URL: "https://example.com/hook", Levels: []string{"error", "success"}},
+					{Type: WebhookTypeSlack, URL: "https://hooks.slack.com/services/T/B/X", Title: "{{.Spell}}: {{.Title}}"},
+					{Type: WebhookTypeNtfy, URL: "https://ntfy.sh/builds", Timeout: 30},
+				}},
+				prefixExplicitlySet: true,
+			},
+			noErr: true,
+		},
+		{
+			name: "invalid webhook type",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Webhooks: []WebhookConfig{{Type: "discord", URL: "https://example.com"}}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid webhook type",
+		},
+		{
+			name: "webhook URL without scheme",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Webhooks: []WebhookConfig{{URL: "example.com/hook"}}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid webhook URL",
+		},
+		{
+			name: "ntfy URL without topic",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Webhooks: []WebhookConfig{{Type: WebhookTypeNtfy, URL: "https://ntfy.sh/"}}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "ntfy URL has no topic",
+		},
+		{
+			name: "invalid webhook template",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Webhooks: []WebhookConfig{{URL: "https://example.com", Message: "{{.Title"}}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid template",
+		},
+		{
+			name: "invalid webhook level",
+			config: Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Notification:        NotificationConfig{Webhooks: []WebhookConfig{{URL: "https://example.com", Levels: []string{"debug"}}}},
+				prefixExplicitlySet: true,
+			},
+			wantErr: "invalid notification level",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			errors := v.Validate(&tt.config)
+
+			if tt.noErr {
+				if len(errors) > 0 {
+					t.Errorf("Expected no errors, got: %v", errors)
+				}
+				return
+			}
+
+			found := false
+			for _, err := range errors {
+				if strings.Contains(err.Message, tt.wantErr) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("Expected error '%s' not found in %v", tt.wantErr, errors)
+			}
+		})
+	}
+}
+
 func TestValidator_PlatformSpecific(t *testing.T) {
 	if runtime.GOOS != "linux" {
 		t.Skip("Platform-specific test for Linux")
@@ -762,3 +1387,59 @@ func TestValidationError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestValidator_ScriptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, mode := range map[string]os.FileMode{"backup.sh": 0o755, "notes.sh": 0o644} {
+		if err := os.WriteFile(filepath.Join(dir, "scripts", name), []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		action   ActionConfig
+		wantErr  string
+		unixOnly bool
+	}{
+		{name: "executable", action: ActionConfig{ScriptFile: "scripts/backup.sh"}},
+		{name: "absolute", action: ActionConfig{ScriptFile: filepath.Join(dir, "scripts", "backup.sh")}},
+		{name: "run with a shell", action: ActionConfig{ScriptFile: "scripts/notes.sh", Shell: "sh"}},
+		{name: "missing", action: ActionConfig{ScriptFile: "scripts/missing.sh"}, wantErr: "script file does not exist"},
+		{name: "directory", action: ActionConfig{ScriptFile: "scripts"}, wantErr: "script file is a directory"},
+		{name: "interpreter", action: ActionConfig{ScriptFile: "scripts/backup.sh", Interpreter: true}, wantErr: "interpreter cannot be combined with script_file"},
+		{name: "not executable", action: ActionConfig{ScriptFile: "scripts/notes.sh"}, wantErr: "script file is not executable", unixOnly: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.unixOnly && runtime.GOOS == "windows" {
+				t.Skip("Windows has no executable bit")
+			}
+			tt.action.Type = "script"
+			cfg := &Config{
+				Hotkeys:             HotkeyConfig{Prefix: "alt+space"},
+				Actions:             map[string]ActionConfig{"backup": tt.action},
+				prefixExplicitlySet: true,
+			}
+			v := NewValidator()
+			v.SetBaseDir(dir)
+
+			var got []string
+			for _, err := range v.Validate(cfg) {
+				if strings.HasPrefix(err.Field, "grimoire.backup.script_file") {
+					got = append(got, err.Message)
+				}
+			}
+			switch {
+			case tt.wantErr == "" && len(got) > 0:
+				t.Errorf("unexpected errors: %v", got)
+			case tt.wantErr != "" && (len(got) != 1 || got[0] != tt.wantErr):
+				t.Errorf("errors = %v, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// ErrNotRunning is returned when no daemon is listening on the control socket
+var ErrNotRunning = errors.New("silentcast is not running")
+
+// DefaultTimeout bounds a single request to the daemon
+const DefaultTimeout = 5 * time.Second
+
+// Send sends one request to the daemon listening on path and returns its response.
+// A response with OK false is returned as an error.
+func Send(path string, req Request, timeout time.Duration) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w (no control socket at %s)", ErrNotRunning, path)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, fmt.Errorf("failed to set control socket deadline: %w", err)
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	if !resp.OK {
+		return &resp, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// Subscribe sends a streaming request to the daemon and calls fn with each
+// value it streams, until ctx is canceled, the daemon closes the stream, or
+// fn returns an error. Canceling ctx is not an error.
+func Subscribe(ctx context.Context, path string, req Request, fn func(data json.RawMessage) error) error {
+	conn, err := net.DialTimeout("unix", path, DefaultTimeout)
+	if err != nil {
+		return fmt.Errorf("%w (no control socket at %s)", ErrNotRunning, path)
+	}
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read stream: %w", err)
+		}
+		if err := fn(json.RawMessage(line)); err != nil {
+			return err
+		}
+	}
+}
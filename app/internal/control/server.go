@@ -0,0 +1,268 @@
+// Package control implements the local control socket that lets the CLI and
+// scripts talk to a running daemon. Requests and responses are JSON objects,
+// one per line, over a Unix domain socket in the configuration directory.
+// A streaming command answers with one response and then writes one JSON
+// value per line until the client disconnects.
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SocketFile is the file name of the control socket in the configuration directory
+const SocketFile = "silentcast.sock"
+
+// SocketPath returns the control socket path for a configuration directory
+func SocketPath(configDir string) string {
+	return filepath.Join(configDir, SocketFile)
+}
+
+// Request is a command sent to the daemon
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Response is the daemon's reply to a Request
+type Response struct {
+	OK      bool            `json:"ok"`
+	Message string          `json:"message,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// HandlerFunc handles a command. The returned data, if not nil, is encoded as
+// the response's Data field.
+type HandlerFunc func(ctx context.Context, args []string) (message string, data interface{}, err error)
+
+// StreamFunc serves a streaming command. It calls send for each value until
+// ctx is canceled, which happens when the client disconnects or the server
+// closes, or until send fails. An error returned before the first send is
+// reported to the client.
+type StreamFunc func(ctx context.Context, args []string, send func(data interface{}) error) error
+
+// Server accepts control connections and dispatches requests to handlers
+type Server struct {
+	path      string
+	mu        sync.RWMutex
+	handlers  map[string]HandlerFunc
+	streams   map[string]StreamFunc
+	listener  net.Listener
+	wg        sync.WaitGroup
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer creates a server that will listen on path
+func NewServer(path string) *Server {
+	return &Server{
+		path:     path,
+		handlers: make(map[string]HandlerFunc),
+		streams:  make(map[string]StreamFunc),
+		closing:  make(chan struct{}),
+	}
+}
+
+// Path returns the socket path
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Handle registers the handler for a command, replacing any existing one
+func (s *Server) Handle(command string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[command] = handler
+	delete(s.streams, command)
+}
+
+// HandleStream registers a streaming command, replacing any existing handler
+func (s *Server) HandleStream(command string, handler StreamFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams[command] = handler
+	delete(s.handlers, command)
+}
+
+// Commands returns the registered command names in sorted order
+func (s *Server) Commands() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.handlers))
+	for name := range s.handlers {
+		names = append(names, name)
+	}
+	for name := range s.streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start listens on the socket and serves connections until ctx is canceled or
+// Close is called. A stale socket left by a crashed daemon is replaced; a socket
+// that still answers means another daemon is running.
+func (s *Server) Start(ctx context.Context) error {
+	if _, err := os.Stat(s.path); err == nil {
+		if conn, dialErr := net.DialTimeout("unix", s.path, time.Second); dialErr == nil {
+			conn.Close()
+			return fmt.Errorf("control socket %s is in use by another instance", s.path)
+		}
+		if err := os.Remove(s.path); err != nil {
+			return fmt.Errorf("failed to remove stale control socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+	if err := os.Chmod(s.path, 0o600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict control socket permissions: %w", err)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.acceptLoop(ctx)
+	}()
+	go func() {
+		<-ctx.Done()
+		s.listener.Close()
+	}()
+	return nil
+}
+
+// Close stops accepting connections, ends open streams, and waits for the
+// connections to finish
+func (s *Server) Close() error {
+	s.closeOnce.Do(func() { close(s.closing) })
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.wg.Wait()
+	if errors.Is(err, net.ErrClosed) {
+		err = nil
+	}
+	return err
+}
+
+// acceptLoop accepts connections until the listener is closed
+func (s *Server) acceptLoop(ctx context.Context) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn answers requests on a connection until the client closes it
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = Response{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else if stream := s.stream(req.Command); stream != nil {
+			s.serveStream(ctx, conn, encoder, stream, req.Args)
+			return
+		} else {
+			resp = s.dispatch(ctx, req)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// stream returns the streaming handler for a command, if any
+func (s *Server) stream(command string) StreamFunc {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.streams[command]
+}
+
+// serveStream answers a streaming request and then writes values until the
+// client disconnects, the server closes, or the handler returns
+func (s *Server) serveStream(ctx context.Context, conn net.Conn, encoder *json.Encoder, handler StreamFunc, args []string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The client sends nothing more, so a finished read means it has gone
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		cancel()
+	}()
+	go func() {
+		select {
+		case <-s.closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	started := false
+	send := func(data interface{}) error {
+		if !started {
+			started = true
+			if err := encoder.Encode(Response{OK: true}); err != nil {
+				return err
+			}
+		}
+		return encoder.Encode(data)
+	}
+
+	err := handler(ctx, args, send)
+	if err != nil && !started {
+		_ = encoder.Encode(Response{Error: err.Error()})
+	}
+}
+
+// dispatch runs the handler for a request
+func (s *Server) dispatch(ctx context.Context, req Request) Response {
+	s.mu.RLock()
+	handler, ok := s.handlers[req.Command]
+	s.mu.RUnlock()
+	if !ok {
+		return Response{Error: fmt.Sprintf("unknown command %q", req.Command)}
+	}
+
+	message, data, err := handler(ctx, req.Args)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+
+	resp := Response{OK: true, Message: message}
+	if data != nil {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return Response{Error: fmt.Sprintf("failed to encode response: %v", err)}
+		}
+		resp.Data = encoded
+	}
+	return resp
+}
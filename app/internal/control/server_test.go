@@ -0,0 +1,219 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startTestServer(t *testing.T) *Server {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	server := NewServer(SocketPath(t.TempDir()))
+	server.Handle("echo", func(ctx context.Context, args []string) (string, interface{}, error) {
+		return strings.Join(args, " "), map[string]int{"count": len(args)}, nil
+	})
+	server.Handle("fail", func(ctx context.Context, args []string) (string, interface{}, error) {
+		return "", nil, errors.New("handler failed")
+	})
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+func TestServer_RoundTrip(t *testing.T) {
+	server := startTestServer(t)
+
+	resp, err := Send(server.Path(), Request{Command: "echo", Args: []string{"hello", "world"}}, time.Second)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if resp.Message != "hello world" {
+		t.Errorf("Message = %q, want %q", resp.Message, "hello world")
+	}
+
+	var data map[string]int
+	if err := json.Unmarshal(resp.Data, &data); err != nil || data["count"] != 2 {
+		t.Errorf("Data = %s, want count 2 (err %v)", resp.Data, err)
+	}
+
+	info, err := os.Stat(server.Path())
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("socket permissions = %v, want owner only", perm)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	server := startTestServer(t)
+
+	if _, err := Send(server.Path(), Request{Command: "fail"}, time.Second); err == nil || err.Error() != "handler failed" {
+		t.Errorf("Send(fail) error = %v, want handler failed", err)
+	}
+	if _, err := Send(server.Path(), Request{Command: "missing"}, time.Second); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("Send(missing) error = %v, want unknown command", err)
+	}
+	if got := server.Commands(); strings.Join(got, ",") != "echo,fail" {
+		t.Errorf("Commands() = %v", got)
+	}
+}
+
+func TestServer_MultipleRequestsPerConnection(t *testing.T) {
+	server := startTestServer(t)
+
+	conn, err := net.Dial("unix", server.Path())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("{\"command\":\"echo\",\"args\":[\"a\"]}\nnot json\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	decoder := json.NewDecoder(conn)
+	var first, second Response
+	if err := decoder.Decode(&first); err != nil || !first.OK || first.Message != "a" {
+		t.Errorf("first response = %+v (err %v)", first, err)
+	}
+	if err := decoder.Decode(&second); err != nil || second.OK || !strings.Contains(second.Error, "invalid request") {
+		t.Errorf("second response = %+v (err %v)", second, err)
+	}
+}
+
+func TestServer_SocketInUse(t *testing.T) {
+	server := startTestServer(t)
+
+	other := NewServer(server.Path())
+	if err := other.Start(context.Background()); err == nil {
+		other.Close()
+		t.Fatal("Expected error when another server is listening")
+	}
+}
+
+func TestServer_ReplacesStaleSocket(t *testing.T) {
+	path := SocketPath(t.TempDir())
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	server := NewServer(path)
+	server.Handle("ping", func(ctx context.Context, args []string) (string, interface{}, error) {
+		return "pong", nil, nil
+	})
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer server.Close()
+
+	if resp, err := Send(path, Request{Command: "ping"}, time.Second); err != nil || resp.Message != "pong" {
+		t.Errorf("Send(ping) = %+v, %v", resp, err)
+	}
+}
+
+func TestSend_NotRunning(t *testing.T) {
+	_, err := Send(SocketPath(t.TempDir()), Request{Command: "ping"}, time.Second)
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Send() error = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestServer_Stream(t *testing.T) {
+	server := startTestServer(t)
+	finished := make(chan struct{})
+	server.HandleStream("count", func(ctx context.Context, args []string, send func(data interface{}) error) error {
+		if len(args) > 0 {
+			return errors.New("no arguments expected")
+		}
+		defer close(finished)
+		for i := 1; ; i++ {
+			if err := send(map[string]int{"n": i}); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Millisecond):
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []int
+	err := Subscribe(ctx, server.Path(), Request{Command: "count"}, func(data json.RawMessage) error {
+		var value map[string]int
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		got = append(got, value["n"])
+		if len(got) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if len(got) < 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("values = %v, want 1, 2, 3", got)
+	}
+
+	// The handler is canceled once the client disconnects
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream handler did not stop after the client disconnected")
+	}
+
+	err = Subscribe(context.Background(), server.Path(), Request{Command: "count", Args: []string{"x"}}, func(json.RawMessage) error { return nil })
+	if err == nil || err.Error() != "no arguments expected" {
+		t.Errorf("Subscribe() error = %v, want handler error", err)
+	}
+	if got := server.Commands(); strings.Join(got, ",") != "count,echo,fail" {
+		t.Errorf("Commands() = %v", got)
+	}
+}
+
+func TestServer_CloseEndsStreams(t *testing.T) {
+	server := startTestServer(t)
+	server.HandleStream("wait", func(ctx context.Context, args []string, send func(data interface{}) error) error {
+		if err := send("ready"); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	})
+
+	ready := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- Subscribe(context.Background(), server.Path(), Request{Command: "wait"}, func(json.RawMessage) error {
+			close(ready)
+			return nil
+		})
+	}()
+	<-ready
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Subscribe() error = %v, want nil when the stream ends", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribe did not return after Close")
+	}
+}
@@ -0,0 +1,118 @@
+// Package dotenv loads the variables of spells from .env files and a secrets
+// file, and expands ${VAR} references with shell-style defaults and errors.
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseFile reads the variables of a .env file
+func ParseFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vars, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// Parse reads KEY=VALUE lines. Blank lines, # comments and a leading export
+// are ignored. Double-quoted values may span lines and interpret \n, \t, \"
+// and \; single-quoted values are taken literally; unquoted values end at
+// " #". Values are not expanded.
+func Parse(r io.Reader) (map[string]string, error) {
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isName(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, `"`):
+			// A double-quoted value continues until its closing quote
+			start := lineNo
+			for !closedDoubleQuote(value) {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated double quote", start)
+				}
+				lineNo++
+				value += "\n" + scanner.Text()
+			}
+			value = unescape(value[1:strings.LastIndex(value, `"`)])
+		case strings.HasPrefix(value, "'"):
+			end := strings.LastIndex(value, "'")
+			if end == 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", lineNo)
+			}
+			value = value[1:end]
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// closedDoubleQuote reports whether a value starting with " has an unescaped closing quote
+func closedDoubleQuote(value string) bool {
+	escaped := false
+	for _, r := range value[1:] {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return true
+		}
+	}
+	return false
+}
+
+// unescape interprets the escapes of a double-quoted value
+func unescape(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r", `\"`, `"`, `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+// isName reports whether s is a valid variable name
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
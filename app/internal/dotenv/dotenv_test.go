@@ -0,0 +1,195 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `# API settings
+export API_URL=https://example.com/api
+EMPTY=
+SPACED = value with spaces # comment
+HASH=a#b
+SINGLE='literal $HOME \n'
+DOUBLE="tab\there \"quoted\""
+MULTI="first
+second"
+`
+	vars, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]string{
+		"API_URL": "https://example.com/api",
+		"EMPTY":   "",
+		"SPACED":  "value with spaces",
+		"HASH":    "a#b",
+		"SINGLE":  `literal $HOME \n`,
+		"DOUBLE":  "tab\there \"quoted\"",
+		"MULTI":   "first\nsecond",
+	}
+	if len(vars) != len(want) {
+		t.Errorf("Parse() = %v, want %d variables", vars, len(want))
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("Parse()[%s] = %q, want %q", k, vars[k], v)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "missing equals", input: "API_URL\n", want: "line 1"},
+		{name: "invalid name", input: "OK=1\n1BAD=2\n", want: "line 2"},
+		{name: "unterminated double quote", input: "A=\"open\nB=1\n", want: "unterminated double quote"},
+		{name: "unterminated single quote", input: "A='open\n", want: "unterminated single quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	values := map[string]string{"NAME": "cast", "EMPTY": "", "secret:TOKEN": "s3cr3t"}
+	lookup := func(name string) (string, bool, error) {
+		value, ok := values[name]
+		return value, ok, nil
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "plain", input: "$NAME and ${NAME}", want: "cast and cast"},
+		{name: "unset", input: "[${MISSING}]", want: "[]"},
+		{name: "default when unset", input: "${MISSING:-fallback}", want: "fallback"},
+		{name: "default when empty", input: "${EMPTY:-fallback}", want: "fallback"},
+		{name: "no default when set", input: "${NAME:-fallback}", want: "cast"},
+		{name: "dash keeps empty", input: "[${EMPTY-fallback}]", want: "[]"},
+		{name: "dash when unset", input: "${MISSING-fallback}", want: "fallback"},
+		{name: "default is expanded", input: "${MISSING:-$NAME.log}", want: "cast.log"},
+		{name: "error when unset", input: "${MISSING:?set MISSING first}", wantErr: "MISSING: set MISSING first"},
+		{name: "error when empty", input: "${EMPTY:?}", wantErr: "EMPTY: is not set"},
+		{name: "question keeps empty", input: "[${EMPTY?}]", want: "[]"},
+		{name: "secret", input: "Bearer ${secret:TOKEN}", want: "Bearer s3cr3t"},
+		{name: "unset secret", input: "${secret:OTHER}", wantErr: "secret OTHER is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.input, lookup)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Expand(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expand(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScope_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "global.env"), "REGION=eu\nLEVEL=info\n", 0o644)
+	writeFile(t, filepath.Join(dir, "deploy.env"), "LEVEL=debug\n", 0o644)
+	t.Setenv("SILENTCAST_DOTENV_TEST", "from-process")
+
+	scope := NewScope(dir)
+	scope.Configure([]string{"global.env"}, "")
+
+	vars, err := scope.Resolve([]string{filepath.Join(dir, "deploy.env")})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	got, err := vars.Expand("$REGION $LEVEL $SILENTCAST_DOTENV_TEST")
+	if err != nil || got != "eu debug from-process" {
+		t.Errorf("Expand() = %q, %v, want spell files to override global files", got, err)
+	}
+	if environ := strings.Join(vars.Environ(), "\n"); !strings.Contains(environ, "LEVEL=debug") {
+		t.Errorf("Environ() does not contain the env file variables")
+	}
+
+	if _, err := scope.Resolve([]string{"missing.env"}); err == nil {
+		t.Error("Resolve() with a missing env file succeeded, want error")
+	}
+
+	var unset *Scope
+	if vars, err := unset.Resolve(nil); err != nil || vars == nil {
+		t.Errorf("Resolve() on a nil scope = %v, %v, want empty variables", vars, err)
+	}
+}
+
+func TestScope_Secrets(t *testing.T) {
+	dir := t.TempDir()
+	secrets := filepath.Join(dir, "secrets.env")
+	writeFile(t, secrets, "API_TOKEN=s3cr3t\n", 0o600)
+
+	scope := NewScope(dir)
+	scope.Configure(nil, "secrets.env")
+	vars, err := scope.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	got, err := vars.Expand("Bearer ${secret:API_TOKEN}")
+	if err != nil || got != "Bearer s3cr3t" {
+		t.Fatalf("Expand() = %q, %v, want the secret", got, err)
+	}
+	if redacted := vars.Redact("curl -H 'Bearer s3cr3t'"); redacted != "curl -H 'Bearer <redacted>'" {
+		t.Errorf("Redact() = %q, want the secret replaced", redacted)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if err := os.Chmod(secrets, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	vars, err = scope.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if _, err := vars.Expand("${secret:API_TOKEN}"); err == nil || !strings.Contains(err.Error(), "chmod 600") {
+		t.Errorf("Expand() with a readable secrets file error = %v, want permission error", err)
+	}
+}
+
+func TestScope_SecretWithoutSecretsFile(t *testing.T) {
+	vars, err := NewScope(t.TempDir()).Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if _, err := vars.Expand("${secret:API_TOKEN}"); err == nil || !strings.Contains(err.Error(), "no secrets_file") {
+		t.Errorf("Expand() error = %v, want missing secrets_file error", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,83 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretPrefix marks a reference to the secrets file, as in ${secret:NAME}
+const SecretPrefix = "secret:"
+
+// Lookup returns the value of a variable and whether it is set
+type Lookup func(name string) (string, bool, error)
+
+// Expand replaces $VAR and ${VAR} in s like os.ExpandEnv, and also supports
+//
+//	${VAR:-default}  default if VAR is unset or empty
+//	${VAR-default}   default if VAR is unset
+//	${VAR:?message}  an error if VAR is unset or empty
+//	${VAR?message}   an error if VAR is unset
+//
+// Defaults are expanded in turn. Names are resolved with lookup, which also
+// receives ${secret:NAME} references.
+func Expand(s string, lookup Lookup) (string, error) {
+	var firstErr error
+	expanded := os.Expand(s, func(ref string) string {
+		value, err := resolve(ref, lookup)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return value
+	})
+	return expanded, firstErr
+}
+
+// resolve returns the value of the reference between ${ and }
+func resolve(ref string, lookup Lookup) (string, error) {
+	if strings.HasPrefix(ref, SecretPrefix) {
+		value, ok, err := lookup(ref)
+		if err == nil && !ok {
+			err = fmt.Errorf("secret %s is not set", strings.TrimPrefix(ref, SecretPrefix))
+		}
+		return value, err
+	}
+
+	name, op, word := splitReference(ref)
+	value, ok, err := lookup(name)
+	if err != nil {
+		return "", err
+	}
+	unset := !ok || (strings.HasPrefix(op, ":") && value == "")
+
+	switch strings.TrimPrefix(op, ":") {
+	case "-":
+		if unset {
+			return Expand(word, lookup)
+		}
+	case "?":
+		if unset {
+			message, _ := Expand(word, lookup)
+			if message == "" {
+				message = "is not set"
+			}
+			return "", fmt.Errorf("%s: %s", name, message)
+		}
+	}
+	return value, nil
+}
+
+// splitReference splits VAR:-word into the name, the operator and the word
+func splitReference(ref string) (name, op, word string) {
+	i := strings.IndexAny(ref, ":-?")
+	if i < 0 {
+		return ref, "", ""
+	}
+	name, rest := ref[:i], ref[i:]
+	for _, candidate := range []string{":-", ":?", "-", "?"} {
+		if strings.HasPrefix(rest, candidate) {
+			return name, candidate, rest[len(candidate):]
+		}
+	}
+	return ref, "", ""
+}
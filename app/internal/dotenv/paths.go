@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandPath expands a configured path: a leading ~ or ~user to the home
+// directory, then variables of the process environment, see Expand. A
+// relative result is made absolute against dir, unless dir is empty. As in
+// a shell, a ~ that a variable expands to is kept. A path whose variables
+// cannot be expanded is used as written, so that opening it reports it.
+func ExpandPath(path, dir string) string {
+	path = expandHome(path)
+	if expanded, err := Expand(path, lookupEnv); err == nil {
+		path = expanded
+	}
+	if path == "" {
+		return ""
+	}
+	if dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// lookupEnv looks up a variable of the process environment
+func lookupEnv(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	return value, ok, nil
+}
+
+// expandHome replaces a leading ~ or ~user with the home directory, and
+// leaves the path unchanged if it cannot be found
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	name, rest := path[1:], ""
+	if i := strings.IndexAny(name, "/"+string(filepath.Separator)); i >= 0 {
+		name, rest = name[:i], name[i+1:]
+	}
+
+	var home string
+	if name == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		home = dir
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return path
+		}
+		home = u.HomeDir
+	}
+	return filepath.Join(home, rest)
+}
@@ -0,0 +1,42 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	t.Setenv("SILENTCAST_TEST_DIR", "/srv/data")
+	t.Setenv("SILENTCAST_TEST_TILDE", "~/notes")
+	dir := filepath.Join(string(filepath.Separator), "etc", "silentcast")
+
+	tests := []struct {
+		path string
+		dir  string
+		want string
+	}{
+		{path: "", dir: dir, want: ""},
+		{path: "~", want: home},
+		{path: "~/scripts/run.sh", dir: dir, want: filepath.Join(home, "scripts", "run.sh")},
+		{path: "$SILENTCAST_TEST_DIR/out.log", want: filepath.Clean("/srv/data/out.log")},
+		{path: "${SILENTCAST_TEST_UNSET:-/var/tmp}/out.log", want: filepath.Clean("/var/tmp/out.log")},
+		{path: "${SILENTCAST_TEST_DIR:-/var/tmp}/out.log", want: filepath.Clean("/srv/data/out.log")},
+		{path: "scripts/run.sh", dir: dir, want: filepath.Join(dir, "scripts", "run.sh")},
+		{path: "scripts/run.sh", want: filepath.Join("scripts", "run.sh")},
+		// A ~ from a variable is not a home directory, as in a shell
+		{path: "$SILENTCAST_TEST_TILDE", dir: dir, want: filepath.Join(dir, "~", "notes")},
+		// An unknown user is left alone
+		{path: "~silentcast-no-such-user/x", want: filepath.Join("~silentcast-no-such-user", "x")},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandPath(tt.path, tt.dir); got != tt.want {
+			t.Errorf("ExpandPath(%q, %q) = %q, want %q", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Scope resolves the variables of spells: the global env files, then the
+// spell's own env files, then the process environment. Relative paths are
+// resolved from the config directory.
+type Scope struct {
+	dir string
+
+	mu      sync.RWMutex
+	files   []string
+	secrets string
+}
+
+// NewScope creates a scope for a config directory, with no env files until
+// Configure sets them
+func NewScope(dir string) *Scope {
+	return &Scope{dir: dir}
+}
+
+// Configure sets the global env files and the secrets file, e.g. after a
+// config reload
+func (s *Scope) Configure(files []string, secretsFile string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = append([]string(nil), files...)
+	s.secrets = secretsFile
+}
+
+// Resolve loads the global env files and the spell's env files. Later files
+// override earlier ones. The secrets file is only read when a secret is
+// looked up.
+func (s *Scope) Resolve(spellFiles []string) (*Vars, error) {
+	var global []string
+	var secrets string
+	if s != nil {
+		s.mu.RLock()
+		global, secrets = s.files, s.secrets
+		s.mu.RUnlock()
+	}
+
+	vars := &Vars{files: make(map[string]string)}
+	if secrets != "" {
+		vars.secretsPath = s.path(secrets)
+	}
+	for _, file := range append(append([]string(nil), global...), spellFiles...) {
+		loaded, err := ParseFile(s.path(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load env file: %w", err)
+		}
+		for k, v := range loaded {
+			vars.files[k] = v
+		}
+	}
+	return vars, nil
+}
+
+// path expands a configured path and makes it relative to the config directory
+func (s *Scope) path(path string) string {
+	if s == nil {
+		return ExpandPath(path, "")
+	}
+	return ExpandPath(path, s.dir)
+}
+
+// Vars are the variables available to a spell
+type Vars struct {
+	files       map[string]string
+	secretsPath string
+	visible     func(name string) bool
+
+	secretsOnce sync.Once
+	secrets     map[string]string
+	secretsErr  error
+	usedSecrets map[string]string
+}
+
+// Lookup returns a variable from the env files or the process environment,
+// or a secret for a ${secret:NAME} reference
+func (v *Vars) Lookup(name string) (string, bool, error) {
+	if secret, ok := strings.CutPrefix(name, SecretPrefix); ok {
+		return v.secret(secret)
+	}
+	if value, ok := v.files[name]; ok {
+		return value, true, nil
+	}
+	if v.visible != nil && !v.visible(name) {
+		return "", false, nil
+	}
+	value, ok := os.LookupEnv(name)
+	return value, ok, nil
+}
+
+// Restrict limits the variables of the process environment that Lookup
+// sees, e.g. to those a sandboxed script gets
+func (v *Vars) Restrict(visible func(name string) bool) {
+	v.visible = visible
+}
+
+// Expand expands references in s, see Expand
+func (v *Vars) Expand(s string) (string, error) {
+	return Expand(s, v.Lookup)
+}
+
+// ExpandCommand expands references in a script command. Variables the spell
+// sets in env are left to the shell, so that secrets passed in env never
+// appear in the command.
+func (v *Vars) ExpandCommand(command string, env map[string]string) (string, error) {
+	return Expand(command, func(name string) (string, bool, error) {
+		if _, ok := env[name]; ok {
+			return "${" + name + "}", true, nil
+		}
+		return v.Lookup(name)
+	})
+}
+
+// Environ returns the process environment with the variables of the env
+// files added, for a command
+func (v *Vars) Environ() []string {
+	return v.Append(os.Environ())
+}
+
+// Append adds the variables of the env files to an environment, e.g. a
+// restricted one
+func (v *Vars) Append(environ []string) []string {
+	keys := make([]string, 0, len(v.files))
+	for k := range v.files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		environ = append(environ, k+"="+v.files[k])
+	}
+	return environ
+}
+
+// Redact replaces the values of the secrets looked up so far in s, so that
+// expanded text can be shown or logged
+func (v *Vars) Redact(s string) string {
+	for _, value := range v.usedSecrets {
+		if value != "" {
+			s = strings.ReplaceAll(s, value, RedactedValue)
+		}
+	}
+	return s
+}
+
+// RedactedValue replaces secrets in displayed configuration
+const RedactedValue = "<redacted>"
+
+// secret looks up a variable of the secrets file
+func (v *Vars) secret(name string) (string, bool, error) {
+	if v.secretsPath == "" {
+		return "", false, fmt.Errorf("secret %s is referenced, but no secrets_file is configured", name)
+	}
+	v.secretsOnce.Do(func() {
+		v.secrets, v.secretsErr = loadSecrets(v.secretsPath)
+	})
+	if v.secretsErr != nil {
+		return "", false, v.secretsErr
+	}
+	value, ok := v.secrets[name]
+	if ok {
+		if v.usedSecrets == nil {
+			v.usedSecrets = make(map[string]string)
+		}
+		v.usedSecrets[name] = value
+	}
+	return value, ok, nil
+}
+
+// loadSecrets reads the secrets file, which other users must not be able
+// to read or write
+func loadSecrets(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("secrets file %s is accessible by other users (mode %04o); run chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+	secrets, err := ParseFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	return secrets, nil
+}
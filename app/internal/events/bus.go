@@ -0,0 +1,187 @@
+// Package events tracks what the daemon is doing (prefix key, running spells,
+// last result and notification) and streams changes to subscribers such as
+// status bars connected through the control socket.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	TypeState         = "state"          // Current state, sent first to every subscriber
+	TypePrefix        = "prefix"         // Prefix key pressed, or the sequence ended
+	TypeSpellStarted  = "spell_started"  // A spell began executing
+	TypeSpellFinished = "spell_finished" // A spell finished; see Result
+	TypeNotification  = "notification"   // A notification was shown or held
+)
+
+// subscriberBuffer is the number of events queued for a subscriber
+const subscriberBuffer = 64
+
+// Event is a change in the daemon's state. Every event carries the state after
+// the change, so a status bar can render each line on its own.
+type Event struct {
+	Type         string        `json:"type"`
+	Time         time.Time     `json:"time"`
+	Spell        string        `json:"spell,omitempty"`
+	Sequence     string        `json:"sequence,omitempty"`
+	Result       *Result       `json:"result,omitempty"`
+	Notification *Notification `json:"notification,omitempty"`
+	State        State         `json:"state"`
+}
+
+// State is a snapshot of the daemon's state
+type State struct {
+	PrefixActive bool          `json:"prefix_active"`
+	Running      []string      `json:"running"`                // Spells executing now, oldest first
+	Last         *Result       `json:"last,omitempty"`         // Most recent finished spell
+	Notification *Notification `json:"notification,omitempty"` // Most recent notification
+}
+
+// Result is the outcome of a spell execution
+type Result struct {
+	Spell      string    `json:"spell"`
+	OK         bool      `json:"ok"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Time       time.Time `json:"time"`
+}
+
+// Notification is a notification as seen by subscribers
+type Notification struct {
+	Level   string    `json:"level"`
+	Title   string    `json:"title"`
+	Message string    `json:"message,omitempty"`
+	Spell   string    `json:"spell,omitempty"`
+	Held    bool      `json:"held,omitempty"` // Held back by do-not-disturb
+	Time    time.Time `json:"time"`
+}
+
+// Bus keeps the current state and fans out events to subscribers.
+// Publishing never blocks: a subscriber that falls behind loses its oldest
+// queued events, and since each event carries the full state, the newest
+// state still arrives.
+type Bus struct {
+	mu          sync.Mutex
+	state       State
+	subscribers map[chan Event]struct{}
+	now         func() time.Time
+}
+
+// NewBus creates an event bus
+func NewBus() *Bus {
+	return &Bus{
+		state:       State{Running: []string{}},
+		subscribers: make(map[chan Event]struct{}),
+		now:         time.Now,
+	}
+}
+
+// State returns a snapshot of the current state
+func (b *Bus) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.snapshot()
+}
+
+// Subscribe returns a channel that first receives a TypeState event and then
+// every change. Call cancel to unsubscribe; the channel is closed.
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	ch <- Event{Type: TypeState, Time: b.now(), State: b.snapshot()}
+	b.subscribers[ch] = struct{}{}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers, ch)
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// SetPrefix records whether the prefix key is waiting for a sequence
+func (b *Bus) SetPrefix(active bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state.PrefixActive == active {
+		return
+	}
+	b.state.PrefixActive = active
+	b.publish(Event{Type: TypePrefix})
+}
+
+// SpellStarted records that a spell began executing
+func (b *Bus) SpellStarted(spell, sequence string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state.Running = append(b.state.Running, spell)
+	b.publish(Event{Type: TypeSpellStarted, Spell: spell, Sequence: sequence})
+}
+
+// SpellFinished records the outcome of a spell started with SpellStarted
+func (b *Bus) SpellFinished(spell, sequence string, err error, elapsed time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, running := range b.state.Running {
+		if running == spell {
+			b.state.Running = append(b.state.Running[:i:i], b.state.Running[i+1:]...)
+			break
+		}
+	}
+	result := &Result{Spell: spell, OK: err == nil, DurationMs: elapsed.Milliseconds(), Time: b.now()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	b.state.Last = result
+	b.publish(Event{Type: TypeSpellFinished, Spell: spell, Sequence: sequence, Result: result})
+}
+
+// Notify records a notification
+func (b *Bus) Notify(notification Notification) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if notification.Time.IsZero() {
+		notification.Time = b.now()
+	}
+	b.state.Notification = &notification
+	b.publish(Event{Type: TypeNotification, Spell: notification.Spell, Notification: &notification})
+}
+
+// publish sends an event to every subscriber. The caller holds b.mu.
+func (b *Bus) publish(event Event) {
+	event.Time = b.now()
+	event.State = b.snapshot()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Drop the oldest event to make room; the subscriber may have
+			// taken it in the meantime, so the send is still non-blocking
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// snapshot copies the state. The caller holds b.mu.
+func (b *Bus) snapshot() State {
+	state := b.state
+	state.Running = append([]string{}, b.state.Running...)
+	return state
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBus_State(t *testing.T) {
+	bus := NewBus()
+
+	bus.SetPrefix(true)
+	bus.SpellStarted("build", "b")
+	bus.SpellStarted("deploy", "")
+	state := bus.State()
+	if !state.PrefixActive || len(state.Running) != 2 || state.Running[0] != "build" {
+		t.Errorf("state = %+v, want prefix active with build and deploy running", state)
+	}
+
+	bus.SetPrefix(false)
+	bus.SpellFinished("build", "b", errors.New("exit status 1"), 1500*time.Millisecond)
+	bus.Notify(Notification{Level: "error", Title: "Spell Failed", Spell: "build"})
+
+	state = bus.State()
+	if state.PrefixActive || len(state.Running) != 1 || state.Running[0] != "deploy" {
+		t.Errorf("state = %+v, want only deploy running", state)
+	}
+	if state.Last == nil || state.Last.Spell != "build" || state.Last.OK || state.Last.Error != "exit status 1" || state.Last.DurationMs != 1500 {
+		t.Errorf("last = %+v", state.Last)
+	}
+	if state.Notification == nil || state.Notification.Title != "Spell Failed" || state.Notification.Time.IsZero() {
+		t.Errorf("notification = %+v", state.Notification)
+	}
+}
+
+func TestBus_Subscribe(t *testing.T) {
+	bus := NewBus()
+	bus.SpellStarted("build", "")
+
+	stream, cancel := bus.Subscribe()
+	first := <-stream
+	if first.Type != TypeState || len(first.State.Running) != 1 {
+		t.Fatalf("first event = %+v, want state with build running", first)
+	}
+
+	bus.SetPrefix(true)
+	bus.SetPrefix(true) // Unchanged, not published
+	bus.SpellFinished("build", "", nil, time.Second)
+
+	prefix := <-stream
+	if prefix.Type != TypePrefix || !prefix.State.PrefixActive {
+		t.Errorf("prefix event = %+v", prefix)
+	}
+	finished := <-stream
+	if finished.Type != TypeSpellFinished || finished.Result == nil || !finished.Result.OK || len(finished.State.Running) != 0 {
+		t.Errorf("finished event = %+v", finished)
+	}
+
+	cancel()
+	cancel()
+	if _, ok := <-stream; ok {
+		t.Error("Expected the stream to be closed after cancel")
+	}
+	bus.SetPrefix(false) // No subscribers left
+}
+
+func TestBus_SlowSubscriberGetsNewestState(t *testing.T) {
+	bus := NewBus()
+	stream, cancel := bus.Subscribe()
+	defer cancel()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+		bus.SetPrefix(i%2 == 0)
+	}
+	bus.Notify(Notification{Level: "info", Title: "last"})
+
+	var last Event
+	for len(stream) > 0 {
+		last = <-stream
+	}
+	if last.Type != TypeNotification || last.Notification.Title != "last" {
+		t.Errorf("last event = %+v, want the newest notification", last)
+	}
+}
@@ -26,3 +26,10 @@ type Manager interface {
 	// IsRunning returns whether the manager is actively listening
 	IsRunning() bool
 }
+
+// PrefixReporter is implemented by managers that report when the prefix key
+// starts waiting for a sequence and when the sequence ends
+type PrefixReporter interface {
+	// SetPrefixHandler sets the function told about prefix changes; it must not block
+	SetPrefixHandler(handler func(active bool))
+}
@@ -44,6 +44,7 @@ type DefaultManager struct {
 	parser    *Parser
 	validator *Validator
 	handler   Handler
+	onPrefix  func(active bool)
 	running   bool
 	stopChan  chan struct{}
 	eventChan chan hook.Event
@@ -190,6 +191,15 @@ func (m *DefaultManager) SetHandler(handler Handler) {
 	m.handler = handler
 }
 
+// SetPrefixHandler sets the function told when the prefix key starts waiting
+// for a sequence and when the sequence ends. It is called with the manager
+// locked, so it must not block.
+func (m *DefaultManager) SetPrefixHandler(handler func(active bool)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onPrefix = handler
+}
+
 // IsRunning returns whether the manager is actively listening
 func (m *DefaultManager) IsRunning() bool {
 	m.mu.RLock()
@@ -282,6 +292,9 @@ func (m *DefaultManager) handleKeyEvent(ev hook.Event) {
 		m.prefixTime = time.Now()
 		m.currentSequence = []Key{}
 		logger.Info("🔵 Prefix key detected - waiting for command...")
+		if m.onPrefix != nil {
+			m.onPrefix(true)
+		}
 		return
 	}
 
@@ -425,8 +438,12 @@ func (m *DefaultManager) checkTimeouts() {
 
 // resetState resets the current hotkey state
 func (m *DefaultManager) resetState() {
+	wasActive := m.prefixActive
 	m.prefixActive = false
 	m.currentSequence = []Key{}
+	if wasActive && m.onPrefix != nil {
+		m.onPrefix(false)
+	}
 }
 
 // getKeyName converts a gohook event to a readable key name
@@ -2,6 +2,7 @@ package hotkey
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -61,6 +62,26 @@ func (p *Parser) Parse(sequence string) (KeySequence, error) {
 	return KeySequence{Keys: keys}, nil
 }
 
+// KeyNames returns all key names the parser recognizes, sorted alphabetically
+func (p *Parser) KeyNames() []string {
+	names := make([]string, 0, len(p.keyMap))
+	for name := range p.keyMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ModifierNames returns all modifier names (including aliases) the parser recognizes
+func (p *Parser) ModifierNames() []string {
+	names := make([]string, 0, len(p.modifierMap))
+	for name := range p.modifierMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // parseKey parses a single key combination (e.g., "ctrl+a" or "a")
 func (p *Parser) parseKey(keyStr string) (Key, error) {
 	parts := strings.Split(strings.ToLower(keyStr), "+")
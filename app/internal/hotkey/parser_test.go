@@ -224,3 +224,41 @@ func getPlatformModifier() string {
 		return "ctrl"
 	}
 }
+
+func TestParser_KeyNames(t *testing.T) {
+	parser := NewParser()
+
+	names := parser.KeyNames()
+	for _, want := range []string{"a", "z", "0", "f12", "space", "enter"} {
+		found := false
+		for _, name := range names {
+			if name == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("KeyNames() missing %q", want)
+		}
+	}
+
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Fatalf("KeyNames() not sorted: %q before %q", names[i-1], names[i])
+		}
+	}
+
+	modifiers := parser.ModifierNames()
+	for _, want := range []string{"ctrl", "alt", "shift"} {
+		found := false
+		for _, name := range modifiers {
+			if name == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("ModifierNames() missing %q", want)
+		}
+	}
+}
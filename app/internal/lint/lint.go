@@ -0,0 +1,468 @@
+// Package lint performs cross-reference checks on spellbook configurations
+// that go beyond the per-field checks of config.Validator.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SphereStacking/silentcast/internal/action/shell"
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/internal/hotkey"
+)
+
+// Severity of a finding. The values match SARIF result levels.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+)
+
+// Rule identifiers
+const (
+	RuleDanglingSpell     = "dangling-spell"
+	RuleUnusedAction      = "unused-action"
+	RuleShadowedSequence  = "shadowed-sequence"
+	RulePlatformOverride  = "platform-override"
+	RuleMissingExecutable = "missing-executable"
+	RuleOutdatedFormat    = "outdated-format"
+)
+
+// Rule describes a lint check
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+}
+
+// Rules lists all checks performed by the linter
+var Rules = []Rule{
+	{RuleDanglingSpell, "Spell references a grimoire action that does not exist", SeverityError},
+	{RuleUnusedAction, "Grimoire action is not referenced by any spell", SeverityWarning},
+	{RuleShadowedSequence, "Spell can never fire because another sequence takes precedence", SeverityWarning},
+	{RulePlatformOverride, "Platform file binds a base spell to a different action", SeverityNote},
+	{RuleMissingExecutable, "Script command executable was not found on PATH", SeverityWarning},
+	{RuleOutdatedFormat, "Spellbook uses an older format version; run --migrate-config", SeverityNote},
+}
+
+// Finding is a single lint result
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+}
+
+// shellBuiltins are commands that are valid in scripts without being on PATH
+var shellBuiltins = map[string]bool{
+	"cd": true, "export": true, "set": true, "unset": true, "source": true, ".": true,
+	"alias": true, "eval": true, "exec": true, "exit": true, "if": true, "for": true,
+	"while": true, "case": true, "test": true, "[": true, "read": true, "type": true,
+	"echo": true, "printf": true, "pwd": true, "true": true, "false": true,
+	// Windows cmd builtins
+	"dir": true, "start": true, "cls": true, "copy": true, "del": true, "move": true,
+}
+
+// commandShells are shells whose command line starts with an executable name
+var commandShells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true, "fish": true,
+	"cmd": true, "powershell": true, "pwsh": true,
+}
+
+// Linter checks configuration layers for cross-reference problems
+type Linter struct {
+	parser   *hotkey.Parser
+	lookPath func(string) (string, error)
+}
+
+// NewLinter creates a new linter
+func NewLinter() *Linter {
+	return &Linter{
+		parser:   hotkey.NewParser(),
+		lookPath: exec.LookPath,
+	}
+}
+
+// lintState holds the merged view of all layers and where each entry came from
+type lintState struct {
+	layers      []config.Layer
+	positions   []map[string]position
+	spells      map[string]string
+	actions     map[string]config.ActionConfig
+	spellLayer  map[string]int
+	actionLayer map[string]int
+	findings    []Finding
+}
+
+// position is a one-based line and column in a file
+type position struct {
+	line   int
+	column int
+}
+
+// Lint runs all checks over the configuration layers, given in merge order
+func (l *Linter) Lint(layers []config.Layer) []Finding {
+	s := &lintState{
+		layers:      layers,
+		spells:      make(map[string]string),
+		actions:     make(map[string]config.ActionConfig),
+		spellLayer:  make(map[string]int),
+		actionLayer: make(map[string]int),
+	}
+
+	for i, layer := range layers {
+		s.positions = append(s.positions, readPositions(layer.Path))
+		for seq, action := range layer.Config.Shortcuts {
+			s.spells[seq] = action
+			s.spellLayer[seq] = i
+		}
+		for name, action := range layer.Config.Actions {
+			s.actions[name] = action
+			s.actionLayer[name] = i
+		}
+	}
+
+	l.checkDanglingSpells(s)
+	l.checkUnusedActions(s)
+	l.checkShadowedSequences(s)
+	l.checkPlatformOverrides(s)
+	l.checkMissingExecutables(s)
+	l.checkOutdatedFormats(s)
+
+	sort.SliceStable(s.findings, func(i, j int) bool {
+		a, b := s.findings[i], s.findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+	return s.findings
+}
+
+// add records a finding located at a key of the given layer
+func (s *lintState) add(rule string, severity Severity, layer int, key, message string) {
+	f := Finding{Rule: rule, Severity: severity, Message: message}
+	if layer >= 0 && layer < len(s.layers) {
+		f.File = s.layers[layer].Path
+		if pos, ok := s.positions[layer][key]; ok {
+			f.Line = pos.line
+			f.Column = pos.column
+		}
+	}
+	s.findings = append(s.findings, f)
+}
+
+// checkDanglingSpells reports spells bound to missing actions
+func (l *Linter) checkDanglingSpells(s *lintState) {
+	names := make([]string, 0, len(s.actions))
+	for name := range s.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for seq, action := range s.spells {
+		if _, ok := s.actions[action]; ok {
+			continue
+		}
+		message := fmt.Sprintf("spell '%s' references missing grimoire action '%s'", seq, action)
+		if suggestion := closestName(action, names); suggestion != "" {
+			message += fmt.Sprintf("; did you mean '%s'?", suggestion)
+		}
+		s.add(RuleDanglingSpell, SeverityError, s.spellLayer[seq], spellKey(seq), message)
+	}
+}
+
+// checkUnusedActions reports actions no spell refers to
+func (l *Linter) checkUnusedActions(s *lintState) {
+	used := make(map[string]bool, len(s.spells))
+	for _, action := range s.spells {
+		used[action] = true
+	}
+
+	for name := range s.actions {
+		if !used[name] {
+			s.add(RuleUnusedAction, SeverityWarning, s.actionLayer[name], actionKey(name),
+				fmt.Sprintf("grimoire action '%s' is not used by any spell", name))
+		}
+	}
+}
+
+// checkShadowedSequences reports sequences that duplicate another sequence
+// after normalization, or that start with a shorter sequence which fires first
+func (l *Linter) checkShadowedSequences(s *lintState) {
+	normalized := make(map[string]string, len(s.spells))
+	for seq := range s.spells {
+		keySeq, err := l.parser.Parse(seq)
+		if err != nil {
+			// Syntax errors are reported by --validate-config
+			continue
+		}
+		normalized[seq] = keySeq.String()
+	}
+
+	sequences := make([]string, 0, len(normalized))
+	for seq := range normalized {
+		sequences = append(sequences, seq)
+	}
+	sort.Strings(sequences)
+
+	for i, seq := range sequences {
+		parts := strings.Split(normalized[seq], ",")
+		for j, other := range sequences {
+			if i == j {
+				continue
+			}
+			otherParts := strings.Split(normalized[other], ",")
+
+			switch {
+			case normalized[seq] == normalized[other]:
+				// Report the duplicate only once, on the later key
+				if j < i {
+					s.add(RuleShadowedSequence, SeverityWarning, s.spellLayer[seq], spellKey(seq),
+						fmt.Sprintf("spell '%s' is the same sequence as '%s'; only one of them can fire", seq, other))
+				}
+			case len(otherParts) < len(parts) && hasPrefix(parts, otherParts):
+				s.add(RuleShadowedSequence, SeverityWarning, s.spellLayer[seq], spellKey(seq),
+					fmt.Sprintf("spell '%s' is unreachable because '%s' fires first", seq, other))
+			}
+		}
+	}
+}
+
+// checkPlatformOverrides reports overlay spells that rebind a base spell
+func (l *Linter) checkPlatformOverrides(s *lintState) {
+	for i := 1; i < len(s.layers); i++ {
+		for seq, action := range s.layers[i].Config.Shortcuts {
+			for j := i - 1; j >= 0; j-- {
+				previous, ok := s.layers[j].Config.Shortcuts[seq]
+				if !ok {
+					continue
+				}
+				if previous != action {
+					s.add(RulePlatformOverride, SeverityNote, i, spellKey(seq),
+						fmt.Sprintf("spell '%s' overrides %s: '%s' → '%s'",
+							seq, filepath.Base(s.layers[j].Path), previous, action))
+				}
+				break
+			}
+		}
+	}
+}
+
+// checkMissingExecutables reports script actions whose executable is not on
+// PATH or, for a path, does not exist. Commands are expanded with the
+// variables of their env files, and relative paths are resolved against the
+// action's working directory or the spellbook directory.
+func (l *Linter) checkMissingExecutables(s *lintState) {
+	scope := s.envScope()
+	for name, action := range s.actions {
+		if action.Type != "script" || action.Interpreter {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(action.Command), "#!") {
+			// The shebang names the interpreter, which is resolved when the script runs
+			continue
+		}
+		if action.Shell != "" && !commandShells[strings.TrimSuffix(filepath.Base(action.Shell), ".exe")] {
+			// Commands for other interpreters (python, node, ...) are code, not command lines
+			continue
+		}
+
+		vars, err := scope.Resolve(action.EnvFile)
+		if err != nil {
+			// A missing env file fails the spell with its own error
+			continue
+		}
+		command, err := vars.Expand(action.Command)
+		if err != nil {
+			continue
+		}
+		executable := firstWord(command)
+		if executable == "" || shellBuiltins[executable] {
+			continue
+		}
+		if strings.ContainsAny(executable, `/\`) || strings.HasPrefix(executable, "~") {
+			dir := s.layerDir(s.actionLayer[name])
+			if action.WorkingDir != "" {
+				if workingDir, err := vars.Expand(action.WorkingDir); err == nil {
+					dir = dotenv.ExpandPath(workingDir, dir)
+				}
+			}
+			if _, err := os.Stat(dotenv.ExpandPath(executable, dir)); err == nil {
+				continue
+			}
+		} else if _, err := l.lookPath(executable); err == nil {
+			continue
+		}
+
+		key := actionKey(name) + ".command"
+		if _, ok := s.positions[s.actionLayer[name]][key]; !ok {
+			key = actionKey(name)
+		}
+		s.add(RuleMissingExecutable, SeverityWarning, s.actionLayer[name], key,
+			fmt.Sprintf("executable '%s' used by grimoire action '%s' was not found", executable, name))
+	}
+}
+
+// checkOutdatedFormats reports files that use fields of an older spellbook
+// format. They still load, but only --migrate-config brings the file up to
+// date. A file that merely lacks the version key is not reported.
+func (l *Linter) checkOutdatedFormats(s *lintState) {
+	for i, layer := range s.layers {
+		data, err := os.ReadFile(layer.Path)
+		if err != nil {
+			continue
+		}
+		result, err := config.Migrate(data)
+		if err != nil || !result.Outdated {
+			continue
+		}
+		s.add(RuleOutdatedFormat, SeverityNote, i, config.KeyVersion,
+			fmt.Sprintf("spellbook format version %d is outdated (current: %d); run 'silentcast --migrate-config' to upgrade it",
+				result.FromVersion, result.ToVersion))
+	}
+}
+
+// envScope returns the env files of all layers, resolved against the
+// directory of the base spellbook like the running daemon does
+func (s *lintState) envScope() *dotenv.Scope {
+	var files []string
+	for _, layer := range s.layers {
+		files = append(files, layer.Config.EnvFile...)
+	}
+	scope := dotenv.NewScope(s.layerDir(0))
+	scope.Configure(files, "")
+	return scope
+}
+
+// layerDir returns the directory of a layer's file
+func (s *lintState) layerDir(layer int) string {
+	if layer < 0 || layer >= len(s.layers) {
+		return ""
+	}
+	return filepath.Dir(s.layers[layer].Path)
+}
+
+// firstWord returns the executable of a command line, skipping VAR=value
+// assignments. Words are split like a shell would, so quoted paths with
+// spaces stay whole.
+func firstWord(command string) string {
+	words, err := shell.SplitWords(command)
+	if err != nil {
+		return ""
+	}
+	for _, word := range words {
+		if strings.Contains(word, "=") && !strings.ContainsAny(word, `/\`) {
+			continue
+		}
+		return word
+	}
+	return ""
+}
+
+// hasPrefix reports whether prefix is a leading part of parts
+func hasPrefix(parts, prefix []string) bool {
+	for i := range prefix {
+		if parts[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// closestName returns the candidate within a small edit distance of name, if any
+func closestName(name string, candidates []string) string {
+	maxDistance := max(2, len(name)/3)
+
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range candidates {
+		if d := levenshtein(strings.ToLower(name), strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// spellKey and actionKey build the position lookup keys
+func spellKey(seq string) string   { return config.KeyShortcuts + "." + seq }
+func actionKey(name string) string { return config.KeyActions + "." + name }
+
+// readPositions maps spell and grimoire keys of a file to their positions
+func readPositions(path string) map[string]position {
+	positions := make(map[string]position)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return positions
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return positions
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return positions
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		section, value := root.Content[i].Value, root.Content[i+1]
+		positions[section] = position{root.Content[i].Line, root.Content[i].Column}
+		if (section != config.KeyShortcuts && section != config.KeyActions) || value.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			key := value.Content[j]
+			positions[section+"."+key.Value] = position{key.Line, key.Column}
+
+			entry := value.Content[j+1]
+			if section != config.KeyActions || entry.Kind != yaml.MappingNode {
+				continue
+			}
+			for k := 0; k+1 < len(entry.Content); k += 2 {
+				field := entry.Content[k]
+				positions[section+"."+key.Value+"."+field.Value] = position{field.Line, field.Column}
+			}
+		}
+	}
+	return positions
+}
@@ -0,0 +1,294 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+const lintBase = `spells:
+  e: editor
+  g: git_menu
+  "g,s": git_status
+  "G,S": git_status
+  x: git_stauts
+  b: build
+grimoire:
+  editor:
+    type: app
+    command: vi
+  git_menu:
+    type: script
+    command: git
+  git_status:
+    type: script
+    command: git status
+  build:
+    type: script
+    command: FOO=1 not-a-real-tool --all
+  unused:
+    type: script
+    command: echo hi
+  report:
+    type: script
+    command: |
+      #!/usr/bin/env python3
+      print("hi")
+`
+
+const lintOverlay = `spells:
+  e: code
+grimoire:
+  code:
+    type: app
+    command: code
+`
+
+func loadLintLayers(t *testing.T, files map[string]string) []config.Layer {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	layers, err := config.NewLoader(dir).LoadLayers()
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	return layers
+}
+
+func newTestLinter() *Linter {
+	l := NewLinter()
+	l.lookPath = func(name string) (string, error) {
+		if name == "git" {
+			return "/usr/bin/git", nil
+		}
+		return "", errors.New("not found")
+	}
+	return l
+}
+
+func findRule(findings []Finding, rule, substr string) *Finding {
+	for i := range findings {
+		if findings[i].Rule == rule && strings.Contains(findings[i].Message, substr) {
+			return &findings[i]
+		}
+	}
+	return nil
+}
+
+func TestLinter_Lint(t *testing.T) {
+	layers := loadLintLayers(t, map[string]string{
+		config.ConfigName + ".yml":                           lintBase,
+		config.GetPlatformResolver().GetPlatformConfigFile(): lintOverlay,
+	})
+	findings := newTestLinter().Lint(layers)
+
+	tests := []struct {
+		rule     string
+		substr   string
+		severity Severity
+		file     string
+		line     int
+	}{
+		{RuleDanglingSpell, "did you mean 'git_status'", SeverityError, config.ConfigName + ".yml", 6},
+		{RuleUnusedAction, "'unused'", SeverityWarning, config.ConfigName + ".yml", 21},
+		{RuleUnusedAction, "'editor'", SeverityWarning, config.ConfigName + ".yml", 9},
+		{RuleShadowedSequence, "'g,s' is unreachable because 'g'", SeverityWarning, config.ConfigName + ".yml", 4},
+		{RuleShadowedSequence, "same sequence as", SeverityWarning, config.ConfigName + ".yml", 0},
+		{RulePlatformOverride, "'editor' → 'code'", SeverityNote, config.GetPlatformResolver().GetPlatformConfigFile(), 2},
+		{RuleMissingExecutable, "'not-a-real-tool'", SeverityWarning, config.ConfigName + ".yml", 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+"/"+tt.substr, func(t *testing.T) {
+			f := findRule(findings, tt.rule, tt.substr)
+			if f == nil {
+				t.Fatalf("missing %s finding containing %q in %+v", tt.rule, tt.substr, findings)
+			}
+			if f.Severity != tt.severity {
+				t.Errorf("Severity = %s, want %s", f.Severity, tt.severity)
+			}
+			if filepath.Base(f.File) != tt.file {
+				t.Errorf("File = %s, want %s", f.File, tt.file)
+			}
+			if tt.line != 0 && f.Line != tt.line {
+				t.Errorf("Line = %d, want %d", f.Line, tt.line)
+			}
+		})
+	}
+
+	if f := findRule(findings, RuleMissingExecutable, "'git'"); f != nil {
+		t.Errorf("git is on PATH but was reported: %+v", f)
+	}
+	if f := findRule(findings, RuleMissingExecutable, "'echo'"); f != nil {
+		t.Errorf("shell builtin was reported: %+v", f)
+	}
+	if f := findRule(findings, RuleMissingExecutable, "#!"); f != nil {
+		t.Errorf("shebang script was reported: %+v", f)
+	}
+}
+
+func TestLinter_CleanConfig(t *testing.T) {
+	layers := loadLintLayers(t, map[string]string{
+		config.ConfigName + ".yml": "spells:\n  s: status\ngrimoire:\n  status:\n    type: script\n    command: git status\n",
+	})
+	if findings := newTestLinter().Lint(layers); len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+}
+
+func TestLinter_OutdatedFormat(t *testing.T) {
+	layers := loadLintLayers(t, map[string]string{
+		config.ConfigName + ".yml": "spells:\n  g: github\ngrimoire:\n  github:\n    type: url\n    url: https://github.com\n",
+	})
+	f := findRule(newTestLinter().Lint(layers), RuleOutdatedFormat, "--migrate-config")
+	if f == nil || f.Severity != SeverityNote {
+		t.Fatalf("expected an outdated-format note, got %+v", f)
+	}
+
+	// A file that only lacks the version key is up to date
+	layers = loadLintLayers(t, map[string]string{
+		config.ConfigName + ".yml": "spells:\n  g: github\ngrimoire:\n  github:\n    type: url\n    command: https://github.com\n",
+	})
+	if f := findRule(newTestLinter().Lint(layers), RuleOutdatedFormat, ""); f != nil {
+		t.Errorf("unversioned file was reported: %+v", f)
+	}
+}
+
+func TestClosestName(t *testing.T) {
+	candidates := []string{"editor", "git_status", "terminal"}
+	tests := map[string]string{
+		"git_stauts": "git_status",
+		"Editor":     "editor",
+		"termnal":    "terminal",
+		"browser":    "",
+	}
+	for name, want := range tests {
+		if got := closestName(name, candidates); got != want {
+			t.Errorf("closestName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWriters(t *testing.T) {
+	findings := []Finding{
+		{Rule: RuleDanglingSpell, Severity: SeverityError, Message: "broken", File: "spellbook.yml", Line: 3, Column: 3},
+		{Rule: RuleUnusedAction, Severity: SeverityWarning, Message: "unused", File: "spellbook.yml"},
+	}
+
+	var human bytes.Buffer
+	if err := WriteHuman(&human, findings); err != nil {
+		t.Fatalf("WriteHuman() error = %v", err)
+	}
+	if !strings.Contains(human.String(), "spellbook.yml:3:3: broken [dangling-spell]") ||
+		!strings.Contains(human.String(), "1 error(s), 1 warning(s)") {
+		t.Errorf("unexpected human output:\n%s", human.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteJSON(&jsonOut, findings); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded struct {
+		Findings []Finding `json:"findings"`
+		Summary  Summary   `json:"summary"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Findings) != 2 || decoded.Summary.Errors != 1 {
+		t.Errorf("unexpected JSON output: %+v", decoded)
+	}
+
+	var sarif bytes.Buffer
+	if err := WriteSARIF(&sarif, findings, "1.0.0"); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(Rules) {
+		t.Fatalf("unexpected SARIF structure: %+v", log)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 || results[0].Level != "error" || results[0].Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("unexpected SARIF results: %+v", results)
+	}
+	if results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("finding without line should not have a region")
+	}
+
+	human.Reset()
+	if err := WriteHuman(&human, nil); err != nil || !strings.Contains(human.String(), "No lint problems") {
+		t.Errorf("unexpected output for no findings: %q, %v", human.String(), err)
+	}
+}
+
+func TestLinter_MissingExecutablePaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, tool := range []string{filepath.Join("My Tools", "run"), filepath.Join("bin", "deploy"), filepath.Join("project", "build.sh")} {
+		path := filepath.Join(dir, tool)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tools.env"), []byte("TOOLS="+filepath.Join(dir, "bin")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	content := fmt.Sprintf(`spells:
+  q: quoted
+  e: from_env_file
+  w: in_working_dir
+  s: in_spellbook_dir
+  m: missing
+grimoire:
+  quoted:
+    type: script
+    command: '"%s" --x'
+  from_env_file:
+    type: script
+    command: $TOOLS/deploy --prod
+    env_file: tools.env
+  in_working_dir:
+    type: script
+    command: ./build.sh
+    working_dir: project
+  in_spellbook_dir:
+    type: script
+    command: bin/deploy
+  missing:
+    type: script
+    command: ./missing.sh
+`, filepath.ToSlash(filepath.Join(dir, "My Tools", "run")))
+	if err := os.WriteFile(filepath.Join(dir, config.ConfigName+".yml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	layers, err := config.NewLoader(dir).LoadLayers()
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+
+	var reported []string
+	for _, f := range newTestLinter().Lint(layers) {
+		if f.Rule == RuleMissingExecutable {
+			reported = append(reported, f.Message)
+		}
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0], "'missing'") {
+		t.Errorf("missing executables = %q, want only the missing action", reported)
+	}
+}
@@ -0,0 +1,210 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Summary counts findings by severity
+type Summary struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Notes    int `json:"notes"`
+}
+
+// Summarize counts the findings by severity
+func Summarize(findings []Finding) Summary {
+	var s Summary
+	for _, f := range findings {
+		switch f.Severity {
+		case SeverityError:
+			s.Errors++
+		case SeverityWarning:
+			s.Warnings++
+		default:
+			s.Notes++
+		}
+	}
+	return s
+}
+
+// WriteHuman writes findings in a compiler-like format
+func WriteHuman(w io.Writer, findings []Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(w, "✅ No lint problems found")
+		return err
+	}
+
+	for _, f := range findings {
+		icon := "ℹ️ "
+		switch f.Severity {
+		case SeverityError:
+			icon = "❌"
+		case SeverityWarning:
+			icon = "⚠️ "
+		}
+		if _, err := fmt.Fprintf(w, "%s %s: %s [%s]\n", icon, location(f), f.Message, f.Rule); err != nil {
+			return err
+		}
+	}
+
+	s := Summarize(findings)
+	_, err := fmt.Fprintf(w, "\n📊 %d problem(s): %d error(s), %d warning(s), %d note(s)\n",
+		len(findings), s.Errors, s.Warnings, s.Notes)
+	return err
+}
+
+// location formats file:line:column, omitting unknown parts
+func location(f Finding) string {
+	var sb strings.Builder
+	sb.WriteString(f.File)
+	if f.Line > 0 {
+		sb.WriteString(fmt.Sprintf(":%d", f.Line))
+		if f.Column > 0 {
+			sb.WriteString(fmt.Sprintf(":%d", f.Column))
+		}
+	}
+	return sb.String()
+}
+
+// WriteJSON writes findings and their summary as JSON
+func WriteJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Findings []Finding `json:"findings"`
+		Summary  Summary   `json:"summary"`
+	}{findings, Summarize(findings)})
+}
+
+// SARIF 2.1.0 output, as consumed by code scanning tools
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// WriteSARIF writes findings as a SARIF 2.1.0 log
+func WriteSARIF(w io.Writer, findings []Finding, toolVersion string) error {
+	driver := sarifDriver{
+		Name:           "silentcast-lint",
+		Version:        toolVersion,
+		InformationURI: "https://github.com/SphereStacking/silentcast",
+		Rules:          make([]sarifRule, 0, len(Rules)),
+	}
+	for _, rule := range Rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: string(rule.Severity)},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		result := sarifResult{
+			RuleID:  f.Rule,
+			Level:   string(f.Severity),
+			Message: sarifMessage{Text: f.Message},
+		}
+		if f.File != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: artifactURI(f.File)},
+			}}
+			if f.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+			}
+			result.Locations = []sarifLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// artifactURI returns a path relative to the working directory when the file
+// lives below it, so that results map onto repository files, or a file URI otherwise
+func artifactURI(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs
+	}
+	return "file://" + abs
+}
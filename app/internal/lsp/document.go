@@ -0,0 +1,322 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// document holds the text and parsed YAML tree of a spellbook file
+type document struct {
+	uri      string
+	path     string
+	text     string
+	lines    []string
+	root     *yaml.Node // top-level mapping of the last successful parse
+	parseErr error      // error of the most recent parse, if any
+}
+
+// newDocument creates a document and parses its content
+func newDocument(uri, text string) *document {
+	d := &document{
+		uri:  uri,
+		path: uriToPath(uri),
+	}
+	d.update(text)
+	return d
+}
+
+// update replaces the document text and re-parses it.
+// The previous tree is kept when the new text does not parse, so that
+// completion keeps working while the user is in the middle of an edit.
+func (d *document) update(text string) {
+	d.text = text
+	d.lines = strings.Split(text, "\n")
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &node); err != nil {
+		d.parseErr = err
+		return
+	}
+
+	d.parseErr = nil
+	d.root = nil
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 && node.Content[0].Kind == yaml.MappingNode {
+		d.root = node.Content[0]
+	}
+}
+
+// isPlatformOverlay reports whether the document is the platform-specific spellbook
+func (d *document) isPlatformOverlay() bool {
+	return filepath.Base(d.path) == config.GetPlatformResolver().GetPlatformConfigFile()
+}
+
+// section returns the value node of a top-level key
+func (d *document) section(key string) *yaml.Node {
+	if d.root == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(d.root.Content); i += 2 {
+		if d.root.Content[i].Value == key {
+			return d.root.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// grimoireEntry returns the key node of a grimoire action
+func (d *document) grimoireEntry(name string) *yaml.Node {
+	grimoire := d.section(config.KeyActions)
+	if grimoire == nil || grimoire.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(grimoire.Content); i += 2 {
+		if grimoire.Content[i].Value == name {
+			return grimoire.Content[i]
+		}
+	}
+	return nil
+}
+
+// grimoireNames returns the names of all grimoire actions in the document
+func (d *document) grimoireNames() []string {
+	grimoire := d.section(config.KeyActions)
+	if grimoire == nil || grimoire.Kind != yaml.MappingNode {
+		return nil
+	}
+	names := make([]string, 0, len(grimoire.Content)/2)
+	for i := 0; i+1 < len(grimoire.Content); i += 2 {
+		names = append(names, grimoire.Content[i].Value)
+	}
+	return names
+}
+
+// spellAt returns the key and value nodes of the spell under the position
+func (d *document) spellAt(pos Position) (keyNode, valueNode *yaml.Node) {
+	spells := d.section(config.KeyShortcuts)
+	if spells == nil || spells.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(spells.Content); i += 2 {
+		k, v := spells.Content[i], spells.Content[i+1]
+		if d.contains(k, pos) || d.contains(v, pos) {
+			return k, v
+		}
+	}
+	return nil, nil
+}
+
+// grimoireKeyAt returns the grimoire action key node under the position
+func (d *document) grimoireKeyAt(pos Position) *yaml.Node {
+	grimoire := d.section(config.KeyActions)
+	if grimoire == nil || grimoire.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(grimoire.Content); i += 2 {
+		if d.contains(grimoire.Content[i], pos) {
+			return grimoire.Content[i]
+		}
+	}
+	return nil
+}
+
+// contains reports whether a scalar node covers the position
+func (d *document) contains(node *yaml.Node, pos Position) bool {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return false
+	}
+	r := d.nodeRange(node)
+	if pos.Line != r.Start.Line {
+		return false
+	}
+	return pos.Character >= r.Start.Character && pos.Character <= r.End.Character
+}
+
+// nodeRange returns the range covered by a single-line scalar node
+func (d *document) nodeRange(node *yaml.Node) Range {
+	line := node.Line - 1
+	if line < 0 || line >= len(d.lines) {
+		return Range{}
+	}
+	text := d.lines[line]
+
+	startByte := runeColumnToByte(text, node.Column-1)
+	endByte := startByte + len(node.Value)
+	switch node.Style {
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		// Find the closing quote instead of guessing the escaped length
+		quote := text[startByte]
+		if idx := strings.IndexByte(text[startByte+1:], quote); idx >= 0 {
+			endByte = startByte + idx + 2
+		}
+	}
+	if endByte > len(text) {
+		endByte = len(text)
+	}
+
+	return Range{
+		Start: Position{Line: line, Character: byteToUTF16(text, startByte)},
+		End:   Position{Line: line, Character: byteToUTF16(text, endByte)},
+	}
+}
+
+// lineRange returns a range from the given rune column to the end of a one-based line
+func (d *document) lineRange(line, column int) Range {
+	if line < 1 || line > len(d.lines) {
+		return Range{}
+	}
+	text := d.lines[line-1]
+	start := 0
+	if column > 0 {
+		start = byteToUTF16(text, runeColumnToByte(text, column-1))
+	}
+	return Range{
+		Start: Position{Line: line - 1, Character: start},
+		End:   Position{Line: line - 1, Character: byteToUTF16(text, len(strings.TrimRight(text, " \r\t")))},
+	}
+}
+
+// completionContext describes where the cursor is for completion purposes
+type completionContext struct {
+	section string // top-level key the cursor line belongs to
+	inValue bool   // true when the cursor is after the mapping colon
+	prefix  string // partial word being typed
+}
+
+// completionContextAt inspects the raw text around the position.
+// It works on lines instead of the YAML tree because the document is
+// usually not valid YAML while the user is typing.
+func (d *document) completionContextAt(pos Position) completionContext {
+	var ctx completionContext
+	if pos.Line < 0 || pos.Line >= len(d.lines) {
+		return ctx
+	}
+
+	current := strings.TrimRight(d.lines[pos.Line], "\r")
+	if indentation(current) == 0 {
+		return ctx
+	}
+
+	for i := pos.Line - 1; i >= 0; i-- {
+		line := strings.TrimRight(d.lines[i], "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || indentation(line) > 0 {
+			continue
+		}
+		if key, _, found := strings.Cut(trimmed, ":"); found {
+			ctx.section = strings.Trim(strings.TrimSpace(key), `"'`)
+		}
+		break
+	}
+
+	before := current[:utf16ToByte(current, pos.Character)]
+	if colon := unquotedColon(before); colon >= 0 {
+		ctx.inValue = true
+		ctx.prefix = strings.TrimLeft(before[colon+1:], ` "'`)
+		return ctx
+	}
+
+	key := strings.TrimLeft(before, ` "'`)
+	if idx := strings.LastIndexAny(key, ",+"); idx >= 0 {
+		key = key[idx+1:]
+	}
+	ctx.prefix = strings.TrimSpace(key)
+	return ctx
+}
+
+// keyContext returns the portion of the spell key typed before the cursor
+func (d *document) keyContext(pos Position) string {
+	if pos.Line < 0 || pos.Line >= len(d.lines) {
+		return ""
+	}
+	current := strings.TrimRight(d.lines[pos.Line], "\r")
+	return strings.TrimLeft(current[:utf16ToByte(current, pos.Character)], ` "'`)
+}
+
+// unquotedColon returns the index of the first mapping colon outside quotes
+func unquotedColon(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':':
+			return i
+		}
+	}
+	return -1
+}
+
+// indentation counts the leading spaces of a line
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// runeColumnToByte converts a zero-based rune column into a byte offset
+func runeColumnToByte(text string, column int) int {
+	offset := 0
+	for i := 0; i < column && offset < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// byteToUTF16 converts a byte offset into a UTF-16 code unit offset
+func byteToUTF16(text string, offset int) int {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	units := 0
+	for _, r := range text[:offset] {
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return units
+}
+
+// utf16ToByte converts a UTF-16 code unit offset into a byte offset
+func utf16ToByte(text string, character int) int {
+	units := 0
+	for i, r := range text {
+		if units >= character {
+			return i
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return len(text)
+}
+
+// uriToPath converts a file:// URI into a local file path
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/path -> C:/path
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path)
+}
+
+// pathToURI converts a local file path into a file:// URI
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
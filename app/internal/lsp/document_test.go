@@ -0,0 +1,76 @@
+package lsp
+
+import (
+	"testing"
+)
+
+func TestDocument_ParseErrorKeepsPreviousTree(t *testing.T) {
+	doc := newDocument("file:///tmp/spellbook.yml", testSpellbook)
+	if doc.parseErr != nil {
+		t.Fatalf("unexpected parse error: %v", doc.parseErr)
+	}
+
+	doc.update(testSpellbook + "  bad: [unclosed\n")
+	if doc.parseErr == nil {
+		t.Fatal("expected parse error for broken YAML")
+	}
+	if names := doc.grimoireNames(); len(names) != 2 {
+		t.Errorf("grimoireNames() = %v, want names from previous parse", names)
+	}
+	if line := yamlErrorLine(doc.parseErr); line < 14 {
+		t.Errorf("yamlErrorLine() = %d, want the broken line", line)
+	}
+}
+
+func TestDocument_CompletionContext(t *testing.T) {
+	text := "spells:\n  e: edi\n  \"g,s\n  ctrl+\ngrimoire:\n  editor:\n    type: app\n"
+	doc := newDocument("file:///tmp/spellbook.yml", text)
+
+	tests := []struct {
+		name    string
+		pos     Position
+		section string
+		inValue bool
+		prefix  string
+	}{
+		{"spell value", Position{Line: 1, Character: 8}, "spells", true, "edi"},
+		{"sequence key", Position{Line: 2, Character: 6}, "spells", false, "s"},
+		{"modifier key", Position{Line: 3, Character: 7}, "spells", false, ""},
+		{"grimoire field", Position{Line: 6, Character: 12}, "grimoire", true, "ap"},
+		{"top level", Position{Line: 0, Character: 3}, "", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := doc.completionContextAt(tt.pos)
+			if ctx.section != tt.section || ctx.inValue != tt.inValue || ctx.prefix != tt.prefix {
+				t.Errorf("completionContextAt() = %+v, want {%s %v %s}", ctx, tt.section, tt.inValue, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestDocument_NodeRangeUTF16(t *testing.T) {
+	text := "spells:\n  \"🎯\": editor\n"
+	doc := newDocument("file:///tmp/spellbook.yml", text)
+
+	keyNode, valueNode := doc.spellAt(Position{Line: 1, Character: 8})
+	if keyNode == nil || valueNode.Value != "editor" {
+		t.Fatalf("spellAt() did not find the spell")
+	}
+
+	r := doc.nodeRange(valueNode)
+	if r.Start.Character != 8 || r.End.Character != 14 {
+		t.Errorf("nodeRange() = %+v, want characters 8-14", r)
+	}
+}
+
+func TestURIPathRoundTrip(t *testing.T) {
+	uri := pathToURI("/home/user/my config/spellbook.yml")
+	if uri != "file:///home/user/my%20config/spellbook.yml" {
+		t.Errorf("pathToURI() = %s", uri)
+	}
+	if path := uriToPath(uri); path != "/home/user/my config/spellbook.yml" {
+		t.Errorf("uriToPath() = %s", path)
+	}
+}
@@ -0,0 +1,351 @@
+package lsp
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+)
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// layers returns the spellbook layers the document participates in, in load order.
+// The base spellbook and the platform overlay are merged at runtime, so each is
+// analyzed together with its sibling. Unsaved buffers take precedence over disk.
+func (s *Server) layers(doc *document) []*document {
+	base := filepath.Base(doc.path)
+	platformFile := config.GetPlatformResolver().GetPlatformConfigFile()
+	if base != config.ConfigName+".yml" && base != platformFile {
+		return []*document{doc}
+	}
+
+	dir := filepath.Dir(doc.path)
+	var layers []*document
+	for _, name := range []string{config.ConfigName + ".yml", platformFile} {
+		path := filepath.Join(dir, name)
+		if path == doc.path {
+			layers = append(layers, doc)
+			continue
+		}
+
+		uri := pathToURI(path)
+		if open, ok := s.documents[uri]; ok {
+			layers = append(layers, open)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		layers = append(layers, newDocument(uri, string(data)))
+	}
+	return layers
+}
+
+// mergedConfig decodes all layers into a single configuration, later layers
+// overriding earlier ones the same way the loader merges files
+func (s *Server) mergedConfig(doc *document) (*config.Config, error) {
+	cfg := &config.Config{
+		Shortcuts: make(map[string]string),
+		Actions:   make(map[string]config.ActionConfig),
+	}
+
+	for _, layer := range s.layers(doc) {
+		if layer.parseErr != nil && layer != doc {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(layer.text), cfg); err != nil {
+			if layer == doc {
+				return nil, err
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// diagnose validates a document and converts the results into diagnostics
+func (s *Server) diagnose(doc *document) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0)
+
+	if doc.parseErr != nil {
+		return append(diagnostics, Diagnostic{
+			Range:    doc.lineRange(yamlErrorLine(doc.parseErr), 0),
+			Severity: SeverityError,
+			Source:   diagnosticSource,
+			Message:  doc.parseErr.Error(),
+		})
+	}
+
+	cfg, err := s.mergedConfig(doc)
+	if err != nil {
+		// Type errors are reported against a re-encoded document, so their
+		// line numbers are unreliable; anchor them at the top of the file
+		return append(diagnostics, Diagnostic{
+			Range:    doc.lineRange(1, 0),
+			Severity: SeverityError,
+			Source:   diagnosticSource,
+			Message:  err.Error(),
+		})
+	}
+
+	validator := config.NewValidator()
+	validator.SetBaseDir(filepath.Dir(doc.path))
+	for _, ve := range validator.ValidateWithYAML(cfg, []byte(doc.text)) {
+		// Problems without a location in an overlay belong to the base spellbook
+		if ve.Line == 0 && doc.isPlatformOverlay() {
+			continue
+		}
+
+		line := ve.Line
+		if line == 0 {
+			line = 1
+		}
+
+		message := fmt.Sprintf("%s: %s", ve.Field, ve.Message)
+		if ve.Suggestion != "" {
+			message += "\n" + ve.Suggestion
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    doc.lineRange(line, ve.Column),
+			Severity: SeverityError,
+			Source:   diagnosticSource,
+			Message:  message,
+		})
+	}
+
+	return diagnostics
+}
+
+// yamlErrorLine returns the one-based line of a YAML error, or 1 if unknown
+func yamlErrorLine(err error) int {
+	if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+		if line, convErr := strconv.Atoi(match[1]); convErr == nil && line > 0 {
+			return line
+		}
+	}
+	return 1
+}
+
+// hover describes the spell or grimoire action under the cursor
+func (s *Server) hover(doc *document, pos Position) *Hover {
+	cfg, err := s.mergedConfig(doc)
+	if err != nil {
+		return nil
+	}
+
+	if keyNode, valueNode := doc.spellAt(pos); keyNode != nil {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("**%s** → `%s`\n\n", keyNode.Value, valueNode.Value))
+		if action, ok := cfg.Actions[valueNode.Value]; ok {
+			sb.WriteString(describeAction(cfg, &action, filepath.Dir(doc.path)))
+		} else {
+			sb.WriteString(fmt.Sprintf("⚠️ Grimoire action `%s` is not defined", valueNode.Value))
+		}
+
+		hitNode := keyNode
+		if doc.contains(valueNode, pos) {
+			hitNode = valueNode
+		}
+		r := doc.nodeRange(hitNode)
+		return &Hover{
+			Contents: MarkupContent{Kind: "markdown", Value: sb.String()},
+			Range:    &r,
+		}
+	}
+
+	if keyNode := doc.grimoireKeyAt(pos); keyNode != nil {
+		action, ok := cfg.Actions[keyNode.Value]
+		if !ok {
+			return nil
+		}
+		r := doc.nodeRange(keyNode)
+		return &Hover{
+			Contents: MarkupContent{
+				Kind:  "markdown",
+				Value: fmt.Sprintf("**%s**\n\n%s", keyNode.Value, describeAction(cfg, &action, filepath.Dir(doc.path))),
+			},
+			Range: &r,
+		}
+	}
+
+	return nil
+}
+
+// describeAction renders the action details shown on hover; script files
+// and env files are resolved against dir
+func describeAction(cfg *config.Config, action *config.ActionConfig, dir string) string {
+	var sb strings.Builder
+
+	// Variables are expanded like the executor does, with secrets redacted;
+	// without readable env files, values are shown as written
+	scope := dotenv.NewScope(dir)
+	scope.Configure(cfg.EnvFile, cfg.SecretsFile)
+	vars, _ := scope.Resolve(action.EnvFile)
+
+	sb.WriteString(fmt.Sprintf("Type: `%s`\n\n", action.Type))
+	if action.Description != "" {
+		sb.WriteString(action.Description + "\n\n")
+	}
+
+	sb.WriteString("```sh\n" + resolveCommand(action, dir, vars) + "\n```\n")
+
+	if action.Shell != "" {
+		sb.WriteString(fmt.Sprintf("\n- Shell: `%s`", action.Shell))
+	}
+	if action.WorkingDir != "" {
+		sb.WriteString(fmt.Sprintf("\n- Working directory: `%s`", expandForHover(vars, action.WorkingDir)))
+	}
+	if action.Timeout > 0 {
+		sb.WriteString(fmt.Sprintf("\n- Timeout: %d seconds", action.Timeout))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// resolveCommand returns the command as it would be executed: variables
+// expanded from vars, arguments appended as written and, for app actions,
+// the executable resolved through PATH. Secrets are redacted.
+func resolveCommand(action *config.ActionConfig, dir string, vars *dotenv.Vars) string {
+	command := strings.TrimSpace(action.Command)
+	if action.ScriptFile != "" {
+		command = action.ScriptPath(dir)
+	} else if vars != nil {
+		expand := vars.Expand
+		if action.Type == "script" {
+			expand = func(s string) (string, error) { return vars.ExpandCommand(s, action.Env) }
+		}
+		if expanded, err := expand(command); err == nil {
+			command = expanded
+		}
+	}
+
+	switch action.Type {
+	case "url":
+		if !strings.Contains(command, "://") {
+			command = "https://" + command
+		}
+		return redact(vars, command)
+	case "app":
+		if !filepath.IsAbs(command) {
+			if fullPath, err := exec.LookPath(command); err == nil {
+				command = fullPath
+			}
+		}
+	}
+
+	parts := append([]string{command}, action.Args...)
+	return redact(vars, strings.Join(parts, " "))
+}
+
+// expandForHover expands the variables of a value with secrets redacted,
+// or returns it as written when it cannot be expanded
+func expandForHover(vars *dotenv.Vars, value string) string {
+	if vars == nil {
+		return value
+	}
+	expanded, err := vars.Expand(value)
+	if err != nil {
+		return value
+	}
+	return vars.Redact(expanded)
+}
+
+// redact replaces the secrets looked up in vars, which may be nil
+func redact(vars *dotenv.Vars, value string) string {
+	if vars == nil {
+		return value
+	}
+	return vars.Redact(value)
+}
+
+// definition locates the grimoire entry referenced by the spell under the cursor.
+// When several layers define the action, the one that wins at runtime is returned.
+func (s *Server) definition(doc *document, pos Position) *Location {
+	_, valueNode := doc.spellAt(pos)
+	if valueNode == nil {
+		return nil
+	}
+
+	layers := s.layers(doc)
+	for i := len(layers) - 1; i >= 0; i-- {
+		if keyNode := layers[i].grimoireEntry(valueNode.Value); keyNode != nil {
+			return &Location{URI: layers[i].uri, Range: layers[i].nodeRange(keyNode)}
+		}
+	}
+	return nil
+}
+
+// completion proposes grimoire names for spell values and key names for spell keys
+func (s *Server) completion(doc *document, pos Position) CompletionList {
+	list := CompletionList{Items: make([]CompletionItem, 0)}
+
+	ctx := doc.completionContextAt(pos)
+	if ctx.section != config.KeyShortcuts {
+		return list
+	}
+
+	if ctx.inValue {
+		seen := make(map[string]bool)
+		var names []string
+		for _, layer := range s.layers(doc) {
+			for _, name := range layer.grimoireNames() {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if strings.HasPrefix(name, ctx.prefix) {
+				list.Items = append(list.Items, CompletionItem{
+					Label:  name,
+					Kind:   CompletionKindFunction,
+					Detail: "grimoire action",
+				})
+			}
+		}
+		return list
+	}
+
+	prefix := strings.ToLower(ctx.prefix)
+	for _, name := range s.parser.KeyNames() {
+		if strings.HasPrefix(name, prefix) {
+			list.Items = append(list.Items, CompletionItem{
+				Label:  name,
+				Kind:   CompletionKindConstant,
+				Detail: "key",
+			})
+		}
+	}
+
+	// Modifiers are only valid on single-key spells, not in sequences
+	if !strings.Contains(doc.keyContext(pos), ",") {
+		for _, name := range s.parser.ModifierNames() {
+			if strings.HasPrefix(name, prefix) {
+				list.Items = append(list.Items, CompletionItem{
+					Label:      name,
+					Kind:       CompletionKindKeyword,
+					Detail:     "modifier",
+					InsertText: name + "+",
+				})
+			}
+		}
+	}
+
+	return list
+}
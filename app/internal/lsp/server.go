@@ -0,0 +1,224 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/SphereStacking/silentcast/internal/hotkey"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// diagnosticSource is reported as the origin of every diagnostic
+const diagnosticSource = "silentcast"
+
+// Server is a Language Server Protocol server for spellbook files.
+// It speaks JSON-RPC over a reader/writer pair (normally stdin/stdout)
+// and processes one message at a time.
+type Server struct {
+	reader    *bufio.Reader
+	writer    io.Writer
+	version   string
+	parser    *hotkey.Parser
+	documents map[string]*document
+	shutdown  bool
+}
+
+// NewServer creates a new language server reading from in and writing to out
+func NewServer(in io.Reader, out io.Writer, version string) *Server {
+	return &Server{
+		reader:    bufio.NewReader(in),
+		writer:    out,
+		version:   version,
+		parser:    hotkey.NewParser(),
+		documents: make(map[string]*document),
+	}
+}
+
+// Run processes messages until the client sends exit or closes the stream
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		body, err := readMessage(s.reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if s.shutdown {
+					return nil
+				}
+				return fmt.Errorf("client closed the connection without shutdown")
+			}
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if writeErr := s.replyError(json.RawMessage("null"), codeParseError, err.Error()); writeErr != nil {
+				return writeErr
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			if s.shutdown {
+				return nil
+			}
+			return fmt.Errorf("exit received before shutdown")
+		}
+
+		if err := s.handle(&req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a single request or notification.
+// Only transport failures are returned; protocol errors are sent to the client.
+func (s *Server) handle(req *request) error {
+	logger.Debug("LSP message: %s", req.Method)
+
+	if s.shutdown && !req.isNotification() {
+		return s.replyError(req.ID, codeInvalidRequest, "server is shutting down")
+	}
+
+	switch req.Method {
+	case "initialize":
+		return s.reply(req.ID, s.initialize())
+	case "initialized":
+		return nil
+	case "shutdown":
+		s.shutdown = true
+		return s.reply(req.ID, nil)
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		doc := newDocument(params.TextDocument.URI, params.TextDocument.Text)
+		s.documents[doc.uri] = doc
+		return s.publishDiagnostics(doc)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		if doc, ok := s.documents[params.TextDocument.URI]; ok && len(params.ContentChanges) > 0 {
+			doc.update(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+		return nil
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok {
+			return nil
+		}
+		if params.Text != nil {
+			doc.update(*params.Text)
+		}
+		return s.publishDiagnostics(doc)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	case "textDocument/hover":
+		return s.handlePositionRequest(req, func(doc *document, pos Position) interface{} {
+			if hover := s.hover(doc, pos); hover != nil {
+				return hover
+			}
+			return nil
+		})
+	case "textDocument/definition":
+		return s.handlePositionRequest(req, func(doc *document, pos Position) interface{} {
+			if location := s.definition(doc, pos); location != nil {
+				return location
+			}
+			return nil
+		})
+	case "textDocument/completion":
+		return s.handlePositionRequest(req, func(doc *document, pos Position) interface{} {
+			return s.completion(doc, pos)
+		})
+	default:
+		if req.isNotification() {
+			return nil
+		}
+		return s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method not supported: %s", req.Method))
+	}
+}
+
+// handlePositionRequest decodes position params and replies with the handler result
+func (s *Server) handlePositionRequest(req *request, handler func(*document, Position) interface{}) error {
+	var params TextDocumentPositionParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.replyError(req.ID, codeInvalidParams, err.Error())
+	}
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return s.reply(req.ID, nil)
+	}
+	return s.reply(req.ID, handler(doc, params.Position))
+}
+
+// initialize returns the server capabilities
+func (s *Server) initialize() InitializeResult {
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    1, // Full document sync
+				Save:      SaveOptions{IncludeText: true},
+			},
+			HoverProvider:      true,
+			DefinitionProvider: true,
+			CompletionProvider: CompletionOptions{
+				TriggerCharacters: []string{":", ",", "+", " "},
+			},
+		},
+		ServerInfo: ServerInfo{
+			Name:    "silentcast",
+			Version: s.version,
+		},
+	}
+}
+
+// publishDiagnostics validates a document and sends the results to the client
+func (s *Server) publishDiagnostics(doc *document) error {
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: s.diagnose(doc),
+	})
+}
+
+// reply sends a successful response
+func (s *Server) reply(id json.RawMessage, result interface{}) error {
+	return writeMessage(s.writer, response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// replyError sends an error response
+func (s *Server) replyError(id json.RawMessage, code int, message string) error {
+	return writeMessage(s.writer, errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   responseError{Code: code, Message: message},
+	})
+}
+
+// notify sends a notification to the client
+func (s *Server) notify(method string, params interface{}) error {
+	return writeMessage(s.writer, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
@@ -0,0 +1,345 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+const testSpellbook = `hotkeys:
+  prefix: "alt+space"
+spells:
+  e: "editor"
+  "g,s": git_status
+grimoire:
+  editor:
+    type: script
+    command: "echo $HOME"
+    description: "Open editor"
+  git_status:
+    type: script
+    command: git
+    args: ["status"]
+`
+
+// lspSession builds a stream of framed client messages
+type lspSession struct {
+	buf    bytes.Buffer
+	nextID int
+}
+
+func (s *lspSession) request(t *testing.T, method string, params interface{}) int {
+	t.Helper()
+	s.nextID++
+	s.write(t, map[string]interface{}{"jsonrpc": "2.0", "id": s.nextID, "method": method, "params": params})
+	return s.nextID
+}
+
+func (s *lspSession) notify(t *testing.T, method string, params interface{}) {
+	t.Helper()
+	s.write(t, map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (s *lspSession) write(t *testing.T, msg interface{}) {
+	t.Helper()
+	if err := writeMessage(&s.buf, msg); err != nil {
+		t.Fatalf("writeMessage() error = %v", err)
+	}
+}
+
+// serverMessage is a decoded message written by the server
+type serverMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+// runSession runs the server over the session input and returns all output messages
+func runSession(t *testing.T, session *lspSession) []serverMessage {
+	t.Helper()
+
+	var out bytes.Buffer
+	server := NewServer(&session.buf, &out, "test")
+	if err := server.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var messages []serverMessage
+	reader := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+		var msg serverMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("invalid server message %s: %v", body, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func findResponse(t *testing.T, messages []serverMessage, id int) serverMessage {
+	t.Helper()
+	for _, msg := range messages {
+		if msg.ID != nil && *msg.ID == id {
+			return msg
+		}
+	}
+	t.Fatalf("no response for request %d", id)
+	return serverMessage{}
+}
+
+func writeSpellbook(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestServer_Lifecycle(t *testing.T) {
+	session := &lspSession{}
+	initID := session.request(t, "initialize", map[string]interface{}{})
+	session.notify(t, "initialized", map[string]interface{}{})
+	unknownID := session.request(t, "workspace/symbol", map[string]interface{}{})
+	shutdownID := session.request(t, "shutdown", nil)
+	session.notify(t, "exit", nil)
+
+	messages := runSession(t, session)
+
+	var result InitializeResult
+	if err := json.Unmarshal(findResponse(t, messages, initID).Result, &result); err != nil {
+		t.Fatalf("invalid initialize result: %v", err)
+	}
+	if !result.Capabilities.HoverProvider || !result.Capabilities.DefinitionProvider {
+		t.Errorf("expected hover and definition capabilities, got %+v", result.Capabilities)
+	}
+	if result.ServerInfo.Name != "silentcast" {
+		t.Errorf("ServerInfo.Name = %q, want silentcast", result.ServerInfo.Name)
+	}
+
+	if resp := findResponse(t, messages, unknownID); resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Errorf("expected method not found error, got %+v", resp.Error)
+	}
+
+	if resp := findResponse(t, messages, shutdownID); resp.Error != nil {
+		t.Errorf("shutdown returned error: %+v", resp.Error)
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	session := &lspSession{}
+	session.notify(t, "exit", nil)
+
+	server := NewServer(&session.buf, &bytes.Buffer{}, "test")
+	if err := server.Run(context.Background()); err == nil {
+		t.Error("expected error when exiting without shutdown")
+	}
+}
+
+func TestServer_DiagnosticsOnOpenAndSave(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSpellbook(t, dir, config.ConfigName+".yml", testSpellbook)
+	uri := pathToURI(path)
+
+	broken := strings.Replace(testSpellbook, `"g,s": git_status`, `"g,s": missing_action`, 1)
+
+	session := &lspSession{}
+	session.request(t, "initialize", map[string]interface{}{})
+	session.notify(t, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "yaml", "version": 1, "text": testSpellbook},
+	})
+	session.notify(t, "textDocument/didSave", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"text":         broken,
+	})
+	session.request(t, "shutdown", nil)
+	session.notify(t, "exit", nil)
+
+	var published []PublishDiagnosticsParams
+	for _, msg := range runSession(t, session) {
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			t.Fatalf("invalid diagnostics: %v", err)
+		}
+		published = append(published, params)
+	}
+
+	if len(published) != 2 {
+		t.Fatalf("expected 2 diagnostic publications, got %d", len(published))
+	}
+	if len(published[0].Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics for valid spellbook, got %+v", published[0].Diagnostics)
+	}
+
+	if len(published[1].Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic after save, got %+v", published[1].Diagnostics)
+	}
+	diag := published[1].Diagnostics[0]
+	if !strings.Contains(diag.Message, "missing_action") {
+		t.Errorf("unexpected diagnostic message: %q", diag.Message)
+	}
+	if diag.Range.Start.Line != 4 {
+		t.Errorf("diagnostic line = %d, want 4", diag.Range.Start.Line)
+	}
+}
+
+func TestServer_HoverDefinitionCompletion(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSpellbook(t, dir, config.ConfigName+".yml", testSpellbook)
+	uri := pathToURI(path)
+
+	position := func(line, character int) map[string]interface{} {
+		return map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"position":     map[string]interface{}{"line": line, "character": character},
+		}
+	}
+
+	session := &lspSession{}
+	session.request(t, "initialize", map[string]interface{}{})
+	session.notify(t, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "yaml", "version": 1, "text": testSpellbook},
+	})
+	hoverID := session.request(t, "textDocument/hover", position(3, 7))
+	definitionID := session.request(t, "textDocument/definition", position(4, 12))
+	valueCompletionID := session.request(t, "textDocument/completion", position(3, 6))
+	session.notify(t, "textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []map[string]interface{}{{"text": strings.Replace(testSpellbook, "git_status\n", "git_status\n  \"g,sp\n", 1)}},
+	})
+	keyCompletionID := session.request(t, "textDocument/completion", position(5, 7))
+	session.request(t, "shutdown", nil)
+	session.notify(t, "exit", nil)
+
+	messages := runSession(t, session)
+
+	var hover Hover
+	if err := json.Unmarshal(findResponse(t, messages, hoverID).Result, &hover); err != nil {
+		t.Fatalf("invalid hover: %v", err)
+	}
+	for _, want := range []string{"editor", "Open editor", os.Getenv("HOME")} {
+		if !strings.Contains(hover.Contents.Value, want) {
+			t.Errorf("hover missing %q:\n%s", want, hover.Contents.Value)
+		}
+	}
+
+	var location Location
+	if err := json.Unmarshal(findResponse(t, messages, definitionID).Result, &location); err != nil {
+		t.Fatalf("invalid definition: %v", err)
+	}
+	if location.URI != uri || location.Range.Start.Line != 10 {
+		t.Errorf("definition = %+v, want line 10 of %s", location, uri)
+	}
+
+	var values CompletionList
+	if err := json.Unmarshal(findResponse(t, messages, valueCompletionID).Result, &values); err != nil {
+		t.Fatalf("invalid completion: %v", err)
+	}
+	labels := make([]string, 0, len(values.Items))
+	for _, item := range values.Items {
+		labels = append(labels, item.Label)
+	}
+	if strings.Join(labels, ",") != "editor,git_status" {
+		t.Errorf("value completion = %v, want [editor git_status]", labels)
+	}
+
+	var keys CompletionList
+	if err := json.Unmarshal(findResponse(t, messages, keyCompletionID).Result, &keys); err != nil {
+		t.Fatalf("invalid completion: %v", err)
+	}
+	foundSpace := false
+	for _, item := range keys.Items {
+		if item.Label == "space" {
+			foundSpace = true
+		}
+		if item.Detail == "modifier" {
+			t.Errorf("modifiers should not be offered inside sequences: %+v", item)
+		}
+	}
+	if !foundSpace {
+		t.Errorf("key completion missing 'space': %+v", keys.Items)
+	}
+}
+
+func TestDescribeAction_ExpandsLikeExecutor(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "deploy.env"), []byte("REGION=eu-west-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{EnvFile: config.StringList{"deploy.env"}}
+	action := &config.ActionConfig{
+		Type:       "script",
+		Command:    "deploy --region $REGION --token $TOKEN",
+		Args:       []string{"$HOME"},
+		Env:        map[string]string{"TOKEN": "abc"},
+		WorkingDir: "${SILENTCAST_TEST_UNSET:-/srv/app}",
+	}
+
+	got := describeAction(cfg, action, dir)
+	// Env variables are left to the shell and arguments are passed as written
+	for _, want := range []string{"deploy --region eu-west-1 --token ${TOKEN} $HOME", "Working directory: `/srv/app`"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("describeAction() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestServer_DefinitionInPlatformOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeSpellbook(t, dir, config.ConfigName+".yml", testSpellbook)
+
+	overlay := "spells:\n  t: git_status\n"
+	overlayPath := writeSpellbook(t, dir, config.GetPlatformResolver().GetPlatformConfigFile(), overlay)
+	uri := pathToURI(overlayPath)
+
+	session := &lspSession{}
+	session.request(t, "initialize", map[string]interface{}{})
+	session.notify(t, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "yaml", "version": 1, "text": overlay},
+	})
+	definitionID := session.request(t, "textDocument/definition", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": 1, "character": 6},
+	})
+	session.request(t, "shutdown", nil)
+	session.notify(t, "exit", nil)
+
+	messages := runSession(t, session)
+
+	for _, msg := range messages {
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			t.Fatalf("invalid diagnostics: %v", err)
+		}
+		if len(params.Diagnostics) != 0 {
+			t.Errorf("overlay referencing base grimoire should be valid, got %+v", params.Diagnostics)
+		}
+	}
+
+	var location Location
+	if err := json.Unmarshal(findResponse(t, messages, definitionID).Result, &location); err != nil {
+		t.Fatalf("invalid definition: %v", err)
+	}
+	if location.URI != pathToURI(filepath.Join(dir, config.ConfigName+".yml")) {
+		t.Errorf("definition URI = %s, want base spellbook", location.URI)
+	}
+}
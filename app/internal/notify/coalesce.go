@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCoalesceWindow is the period during which repeated notifications are merged
+const DefaultCoalesceWindow = 10 * time.Second
+
+// maxReplacementGroups bounds the groups whose notification IDs are remembered
+const maxReplacementGroups = 100
+
+// ReplaceNotifier is implemented by notifiers that can update a notification in place
+type ReplaceNotifier interface {
+	Notifier
+
+	// Show displays a notification and returns its ID. A non-zero replacesID
+	// updates that notification instead of showing a new one.
+	Show(ctx context.Context, notification Notification, replacesID uint32) (uint32, error)
+}
+
+// groupKey is the context key for the notification group
+type groupKey struct{}
+
+// groupCounter makes group IDs unique within the process
+var groupCounter atomic.Uint64
+
+// NewGroup returns a unique group ID for the notifications of one execution of a spell
+func NewGroup(spell string) string {
+	return fmt.Sprintf("%s#%d", spell, groupCounter.Add(1))
+}
+
+// WithGroup returns a context whose spell notifications (see ForSpell) belong to group
+func WithGroup(ctx context.Context, group string) context.Context {
+	return context.WithValue(ctx, groupKey{}, group)
+}
+
+// GroupFromContext returns the group set with WithGroup, if any
+func GroupFromContext(ctx context.Context) string {
+	group, _ := ctx.Value(groupKey{}).(string)
+	return group
+}
+
+// coalescedNotification is a notification whose repeats are being counted
+type coalescedNotification struct {
+	notification Notification
+	count        int
+	flush        func(Notification)
+}
+
+// Coalescer merges repeated notifications and remembers the notification IDs
+// of groups so that later notifications of a group replace earlier ones.
+// Like DoNotDisturb it lives outside Manager so that it survives reloads.
+//
+// The first notification with a given level, title and spell is shown right
+// away. Repeats within the window are held back and counted; when the window
+// ends, one summary such as "Spell Failed ×5" is shown in their place.
+type Coalescer struct {
+	mu      sync.Mutex
+	showMu  sync.Mutex // Orders showing a group's notification with remembering its ID
+	window  time.Duration
+	pending map[string]*coalescedNotification
+	ids     map[string]map[ReplaceNotifier]uint32
+	order   []string // Groups in the order they were first seen
+}
+
+// NewCoalescer creates a coalescer that merges repeats within window; zero disables merging
+func NewCoalescer(window time.Duration) *Coalescer {
+	return &Coalescer{
+		window:  window,
+		pending: make(map[string]*coalescedNotification),
+		ids:     make(map[string]map[ReplaceNotifier]uint32),
+	}
+}
+
+// SetWindow changes the merge window; zero disables merging
+func (c *Coalescer) SetWindow(window time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.window = window
+}
+
+// admit reports whether a notification should be shown now. A repeat within the
+// window is counted instead, and flush is called with the summary when the window
+// ends. Ungrouped notifications are put in a group of their own repeats, so the
+// summary replaces the first one where the notifier supports it.
+func (c *Coalescer) admit(notification Notification, flush func(Notification)) (Notification, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.window <= 0 {
+		return notification, true
+	}
+
+	key := coalesceKey(notification)
+	if notification.Group == "" {
+		notification.Group = "repeat:" + key
+	}
+	if entry, ok := c.pending[key]; ok {
+		// Keep the first notification's group so that the summary replaces it
+		group := entry.notification.Group
+		entry.count++
+		entry.notification = notification
+		entry.notification.Group = group
+		entry.flush = flush
+		return notification, false
+	}
+
+	c.pending[key] = &coalescedNotification{notification: notification, count: 1, flush: flush}
+	time.AfterFunc(c.window, func() { c.flush(key) })
+	return notification, true
+}
+
+// flush ends the window for key and shows a summary if there were repeats
+func (c *Coalescer) flush(key string) {
+	c.mu.Lock()
+	entry, ok := c.pending[key]
+	delete(c.pending, key)
+	c.mu.Unlock()
+
+	if !ok || entry.count < 2 {
+		return
+	}
+	summary := entry.notification
+	summary.Title = fmt.Sprintf("%s ×%d", summary.Title, entry.count)
+	entry.flush(summary)
+}
+
+// show displays a notification of group on notifier, replacing the group's
+// previous notification there. Shows are serialized so that the ID a
+// notification replaces is always the one remembered by the show before it.
+func (c *Coalescer) show(ctx context.Context, notifier ReplaceNotifier, notification Notification) error {
+	c.showMu.Lock()
+	defer c.showMu.Unlock()
+
+	id, err := notifier.Show(ctx, notification, c.replacementID(notification.Group, notifier))
+	if err != nil {
+		return err
+	}
+	c.setReplacementID(notification.Group, notifier, id)
+	return nil
+}
+
+// replacementID returns the ID of the notification last shown by notifier for group
+func (c *Coalescer) replacementID(group string, notifier ReplaceNotifier) uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ids[group][notifier]
+}
+
+// setReplacementID remembers the notification shown by notifier for group
+func (c *Coalescer) setReplacementID(group string, notifier ReplaceNotifier, id uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids, ok := c.ids[group]
+	if !ok {
+		ids = make(map[ReplaceNotifier]uint32)
+		c.ids[group] = ids
+		c.order = append(c.order, group)
+		if len(c.order) > maxReplacementGroups {
+			delete(c.ids, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	ids[notifier] = id
+}
+
+// coalesceKey identifies repeats of a notification
+func coalesceKey(notification Notification) string {
+	return strings.Join([]string{notification.Level.String(), notification.Spell, notification.Title}, "\x00")
+}
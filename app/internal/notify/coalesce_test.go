@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// replaceRecorder is a ReplaceNotifier that records the replaced IDs
+type replaceRecorder struct {
+	mu         sync.Mutex
+	nextID     uint32
+	titles     []string
+	replaceIDs []uint32
+}
+
+func (r *replaceRecorder) Notify(ctx context.Context, notification Notification) error {
+	_, err := r.Show(ctx, notification, 0)
+	return err
+}
+
+func (r *replaceRecorder) Show(_ context.Context, notification Notification, replacesID uint32) (uint32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.titles = append(r.titles, notification.Title)
+	r.replaceIDs = append(r.replaceIDs, replacesID)
+	if replacesID != 0 {
+		return replacesID, nil
+	}
+	r.nextID++
+	return r.nextID, nil
+}
+
+func (r *replaceRecorder) IsAvailable() bool {
+	return true
+}
+
+func (r *replaceRecorder) calls() ([]string, []uint32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.titles...), append([]uint32(nil), r.replaceIDs...)
+}
+
+func TestManager_CoalescesRepeats(t *testing.T) {
+	system := NewMockNotifier(true)
+	manager := &Manager{notifiers: []Notifier{system}}
+	manager.SetCoalescer(NewCoalescer(50 * time.Millisecond))
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := manager.Error(ctx, "Spell Failed", "exit 1"); err != nil {
+			t.Fatalf("Error() error = %v", err)
+		}
+	}
+	if err := manager.Info(ctx, "Spell Cast", "other"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if got := len(system.GetNotifications()); got != 2 {
+		t.Fatalf("notifications before the window ends = %d, want 2", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(system.GetNotifications()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	notifications := system.GetNotifications()
+	if len(notifications) != 3 {
+		t.Fatalf("notifications = %d, want 3", len(notifications))
+	}
+	if notifications[2].Title != "Spell Failed ×5" {
+		t.Errorf("summary title = %q, want Spell Failed ×5", notifications[2].Title)
+	}
+
+	// A single notification is not followed by a summary
+	time.Sleep(100 * time.Millisecond)
+	if got := len(system.GetNotifications()); got != 3 {
+		t.Errorf("notifications = %d, want 3", got)
+	}
+}
+
+func TestManager_CoalesceDisabled(t *testing.T) {
+	system := NewMockNotifier(true)
+	manager := &Manager{notifiers: []Notifier{system}}
+	manager.SetCoalescer(NewCoalescer(0))
+
+	for i := 0; i < 3; i++ {
+		if err := manager.Error(context.Background(), "Spell Failed", "exit 1"); err != nil {
+			t.Fatalf("Error() error = %v", err)
+		}
+	}
+	if got := len(system.GetNotifications()); got != 3 {
+		t.Errorf("notifications = %d, want 3", got)
+	}
+}
+
+func TestManager_GroupReplacesNotification(t *testing.T) {
+	system := &replaceRecorder{}
+	manager := &Manager{notifiers: []Notifier{system}}
+	manager.SetCoalescer(NewCoalescer(0))
+
+	ctx := WithGroup(context.Background(), NewGroup("build"))
+	sender := ForSpell(manager, "build")
+	if err := sender.Info(ctx, "Spell Cast", "build"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if err := sender.Success(ctx, "Spell Succeeded", "build"); err != nil {
+		t.Fatalf("Success() error = %v", err)
+	}
+	// Another execution of the same spell gets its own notification
+	if err := ForSpell(manager, "build").Info(WithGroup(context.Background(), NewGroup("build")), "Spell Cast", "build"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+
+	titles, ids := system.calls()
+	if strings.Join(titles, ",") != "Spell Cast,Spell Succeeded,Spell Cast" {
+		t.Errorf("titles = %v", titles)
+	}
+	if len(ids) != 3 || ids[0] != 0 || ids[1] != 1 || ids[2] != 0 {
+		t.Errorf("replaced IDs = %v, want [0 1 0]", ids)
+	}
+}
+
+func TestCoalescer_ReplacementGroupsBounded(t *testing.T) {
+	coalescer := NewCoalescer(0)
+	system := &replaceRecorder{}
+
+	for i := 0; i <= maxReplacementGroups; i++ {
+		coalescer.setReplacementID(NewGroup("spell"), system, uint32(i+1))
+	}
+	if got := len(coalescer.ids); got != maxReplacementGroups {
+		t.Errorf("remembered groups = %d, want %d", got, maxReplacementGroups)
+	}
+}
+
+func TestGroupFromContext(t *testing.T) {
+	if group := GroupFromContext(context.Background()); group != "" {
+		t.Errorf("GroupFromContext() = %q, want empty", group)
+	}
+	first, second := NewGroup("build"), NewGroup("build")
+	if first == second || !strings.HasPrefix(first, "build#") {
+		t.Errorf("NewGroup() = %q, %q, want unique build# IDs", first, second)
+	}
+	if group := GroupFromContext(WithGroup(context.Background(), first)); group != first {
+		t.Errorf("GroupFromContext() = %q, want %q", group, first)
+	}
+}
@@ -16,6 +16,7 @@ import (
 type DarwinNotifier struct {
 	appName         string
 	maxOutputLength int
+	muted           bool
 }
 
 // NewDarwinNotifier creates a new macOS notifier
@@ -26,6 +27,11 @@ func NewDarwinNotifier(appName string) *DarwinNotifier {
 	}
 }
 
+// SetSound enables or disables notification sounds
+func (n *DarwinNotifier) SetSound(enabled bool) {
+	n.muted = !enabled
+}
+
 // Notify sends a notification using macOS notification center
 func (n *DarwinNotifier) Notify(ctx context.Context, notification Notification) error {
 	// Try osascript first (most reliable)
@@ -119,8 +125,11 @@ func (n *DarwinNotifier) sendOSAScriptNotification(ctx context.Context, notifica
 		sound = "Purr"
 	}
 
-	script := fmt.Sprintf(`display notification "%s" with title "%s" subtitle "%s" sound name "%s"`,
-		message, n.appName, title, sound)
+	script := fmt.Sprintf(`display notification "%s" with title "%s" subtitle "%s"`,
+		message, n.appName, title)
+	if !n.muted {
+		script += fmt.Sprintf(` sound name "%s"`, sound)
+	}
 
 	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
 	if err := cmd.Run(); err != nil {
@@ -150,12 +159,13 @@ func (n *DarwinNotifier) sendTerminalNotification(ctx context.Context, notificat
 	}
 
 	// Add sound based on level
-	switch notification.Level {
-	case LevelError:
+	switch {
+	case n.muted:
+	case notification.Level == LevelError:
 		args = append(args, "-sound", "Basso")
-	case LevelWarning:
+	case notification.Level == LevelWarning:
 		args = append(args, "-sound", "Hero")
-	case LevelSuccess:
+	case notification.Level == LevelSuccess:
 		args = append(args, "-sound", "Glass")
 	}
 
@@ -0,0 +1,258 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// maxHeldNotifications bounds the notifications kept for review while do-not-disturb is active
+const maxHeldNotifications = 100
+
+// HeldNotification is a notification that was held back by do-not-disturb
+type HeldNotification struct {
+	Title   string    `json:"title"`
+	Message string    `json:"message,omitempty"`
+	Level   string    `json:"level"`
+	Spell   string    `json:"spell,omitempty"`
+	HeldAt  time.Time `json:"held_at"`
+}
+
+// DNDStatus is a snapshot of the do-not-disturb state
+type DNDStatus struct {
+	Enabled    bool               `json:"enabled"`     // Turned on manually
+	QuietHours bool               `json:"quiet_hours"` // Inside configured quiet hours
+	Active     bool               `json:"active"`      // Notifications are being held
+	Mode       string             `json:"mode"`
+	Held       []HeldNotification `json:"held"`
+}
+
+// quietPeriod is a parsed quiet_hours entry
+type quietPeriod struct {
+	start, end int                   // Minutes after midnight
+	days       map[time.Weekday]bool // Days the period starts on; nil means every day
+}
+
+// contains reports whether t falls inside the period
+func (p quietPeriod) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	startsOn := func(day time.Weekday) bool {
+		return p.days == nil || p.days[day]
+	}
+
+	if p.start < p.end {
+		return startsOn(t.Weekday()) && minute >= p.start && minute < p.end
+	}
+	// The period spans midnight: late today, or early on the day after it started
+	if minute >= p.start && startsOn(t.Weekday()) {
+		return true
+	}
+	return minute < p.end && startsOn((t.Weekday()+6)%7)
+}
+
+// DoNotDisturb tracks whether notifications should be held back, either because
+// do-not-disturb was turned on manually or because of configured quiet hours.
+// It lives outside Manager so that the state survives configuration reloads.
+type DoNotDisturb struct {
+	mu      sync.Mutex
+	manual  bool
+	mode    string
+	allow   map[Level]bool
+	periods []quietPeriod
+	held    []HeldNotification
+	now     func() time.Time
+}
+
+// NewDoNotDisturb creates a do-not-disturb state that is off, has no quiet hours,
+// and lets only errors through while active
+func NewDoNotDisturb() *DoNotDisturb {
+	return &DoNotDisturb{
+		mode:  config.DNDModeSuppress,
+		allow: map[Level]bool{LevelError: true},
+		now:   time.Now,
+	}
+}
+
+// Configure applies the do_not_disturb section of the configuration.
+// Invalid quiet hours are skipped; the validator reports them.
+func (d *DoNotDisturb) Configure(cfg config.DoNotDisturbConfig) {
+	mode := cfg.Mode
+	if mode == "" {
+		mode = config.DNDModeSuppress
+	}
+
+	allow := map[Level]bool{LevelError: true}
+	if cfg.Allow != nil {
+		allow = make(map[Level]bool, len(cfg.Allow))
+		for _, name := range cfg.Allow {
+			if level, ok := parseLevel(name); ok {
+				allow[level] = true
+			}
+		}
+	}
+
+	var periods []quietPeriod
+	for _, entry := range cfg.QuietHours {
+		start, end, err := entry.Minutes()
+		if err != nil || start == end {
+			continue
+		}
+		days, err := entry.Weekdays()
+		if err != nil {
+			continue
+		}
+		periods = append(periods, quietPeriod{start: start, end: end, days: days})
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.mode = mode
+	d.allow = allow
+	d.periods = periods
+}
+
+// SetEnabled turns manual do-not-disturb on or off
+func (d *DoNotDisturb) SetEnabled(enabled bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.manual = enabled
+}
+
+// Toggle flips manual do-not-disturb and returns the new state
+func (d *DoNotDisturb) Toggle() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.manual = !d.manual
+	return d.manual
+}
+
+// Enabled reports whether do-not-disturb was turned on manually
+func (d *DoNotDisturb) Enabled() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.manual
+}
+
+// InQuietHours reports whether the current time falls inside configured quiet hours
+func (d *DoNotDisturb) InQuietHours() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inQuietHoursLocked()
+}
+
+// Active reports whether notifications are currently held back
+func (d *DoNotDisturb) Active() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.manual || d.inQuietHoursLocked()
+}
+
+// Status returns a snapshot of the do-not-disturb state
+func (d *DoNotDisturb) Status() DNDStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	quiet := d.inQuietHoursLocked()
+	held := make([]HeldNotification, len(d.held))
+	copy(held, d.held)
+	return DNDStatus{
+		Enabled:    d.manual,
+		QuietHours: quiet,
+		Active:     d.manual || quiet,
+		Mode:       d.mode,
+		Held:       held,
+	}
+}
+
+// Apply runs a do-not-disturb command: on, off, toggle, status, or clear.
+// It backs the tray item, the control socket, and the --dnd flag.
+func (d *DoNotDisturb) Apply(command string) (DNDStatus, error) {
+	switch strings.ToLower(command) {
+	case "on":
+		d.SetEnabled(true)
+	case "off":
+		d.SetEnabled(false)
+	case "toggle":
+		d.Toggle()
+	case "clear":
+		d.ClearHeld()
+	case "", "status":
+	default:
+		return DNDStatus{}, fmt.Errorf("unknown do-not-disturb command %q (use on, off, toggle, status, or clear)", command)
+	}
+	return d.Status(), nil
+}
+
+// Held returns the notifications held back since they were last cleared, oldest first
+func (d *DoNotDisturb) Held() []HeldNotification {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	held := make([]HeldNotification, len(d.held))
+	copy(held, d.held)
+	return held
+}
+
+// ClearHeld discards held notifications and returns how many there were
+func (d *DoNotDisturb) ClearHeld() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	count := len(d.held)
+	d.held = nil
+	return count
+}
+
+// intercept decides how a notification is delivered. When do-not-disturb is
+// active and the level is not allowed, the notification is held and consoleOnly
+// reports whether it may still be shown on the console.
+func (d *DoNotDisturb) intercept(notification Notification) (held, consoleOnly bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.manual && !d.inQuietHoursLocked() {
+		return false, false
+	}
+	if d.allow[notification.Level] {
+		return false, false
+	}
+
+	d.held = append(d.held, HeldNotification{
+		Title:   notification.Title,
+		Message: notification.Message,
+		Level:   strings.ToLower(notification.Level.String()),
+		Spell:   notification.Spell,
+		HeldAt:  d.now(),
+	})
+	if len(d.held) > maxHeldNotifications {
+		d.held = d.held[len(d.held)-maxHeldNotifications:]
+	}
+	return true, d.mode == config.DNDModeDowngrade
+}
+
+// inQuietHoursLocked checks quiet hours; d.mu must be held
+func (d *DoNotDisturb) inQuietHoursLocked() bool {
+	now := d.now()
+	for _, period := range d.periods {
+		if period.contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLevel converts a configuration level name to a Level
+func parseLevel(name string) (Level, bool) {
+	switch strings.ToLower(name) {
+	case "info":
+		return LevelInfo, true
+	case "success":
+		return LevelSuccess, true
+	case "warning":
+		return LevelWarning, true
+	case "error":
+		return LevelError, true
+	default:
+		return 0, false
+	}
+}
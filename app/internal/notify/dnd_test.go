@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestDoNotDisturb_QuietHours(t *testing.T) {
+	// 2025-07-21 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 7, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name   string
+		period config.QuietHoursConfig
+		now    time.Time
+		want   bool
+	}{
+		{"inside daytime period", config.QuietHoursConfig{Start: "09:00", End: "17:00"}, at(21, 12, 0), true},
+		{"end is exclusive", config.QuietHoursConfig{Start: "09:00", End: "17:00"}, at(21, 17, 0), false},
+		{"before daytime period", config.QuietHoursConfig{Start: "09:00", End: "17:00"}, at(21, 8, 59), false},
+		{"overnight before midnight", config.QuietHoursConfig{Start: "22:00", End: "07:00"}, at(21, 23, 30), true},
+		{"overnight after midnight", config.QuietHoursConfig{Start: "22:00", End: "07:00"}, at(22, 6, 59), true},
+		{"overnight outside", config.QuietHoursConfig{Start: "22:00", End: "07:00"}, at(22, 12, 0), false},
+		{"day filter matches", config.QuietHoursConfig{Start: "09:00", End: "17:00", Days: []string{"mon"}}, at(21, 10, 0), true},
+		{"day filter excludes", config.QuietHoursConfig{Start: "09:00", End: "17:00", Days: []string{"tue"}}, at(21, 10, 0), false},
+		{"overnight uses start day", config.QuietHoursConfig{Start: "22:00", End: "07:00", Days: []string{"fri"}}, at(26, 6, 0), true},
+		{"overnight start day excluded", config.QuietHoursConfig{Start: "22:00", End: "07:00", Days: []string{"fri"}}, at(25, 6, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dnd := NewDoNotDisturb()
+			dnd.now = func() time.Time { return tt.now }
+			dnd.Configure(config.DoNotDisturbConfig{QuietHours: []config.QuietHoursConfig{tt.period}})
+
+			if got := dnd.InQuietHours(); got != tt.want {
+				t.Errorf("InQuietHours() = %v, want %v", got, tt.want)
+			}
+			if dnd.Enabled() {
+				t.Error("Quiet hours should not turn on manual do-not-disturb")
+			}
+		})
+	}
+}
+
+func TestDoNotDisturb_Apply(t *testing.T) {
+	dnd := NewDoNotDisturb()
+
+	steps := []struct {
+		command string
+		want    bool
+	}{
+		{"on", true},
+		{"status", true},
+		{"toggle", false},
+		{"TOGGLE", true},
+		{"off", false},
+	}
+	for _, step := range steps {
+		status, err := dnd.Apply(step.command)
+		if err != nil {
+			t.Fatalf("Apply(%q) error = %v", step.command, err)
+		}
+		if status.Enabled != step.want || status.Active != step.want {
+			t.Errorf("Apply(%q) enabled = %v, active = %v, want %v", step.command, status.Enabled, status.Active, step.want)
+		}
+	}
+
+	if _, err := dnd.Apply("later"); err == nil {
+		t.Error("Expected error for unknown command")
+	}
+}
+
+func TestManager_DoNotDisturb(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.DoNotDisturbConfig
+		level       Level
+		wantSystem  bool
+		wantConsole bool
+		wantHeld    int
+	}{
+		{"suppress holds info", config.DoNotDisturbConfig{}, LevelInfo, false, false, 1},
+		{"errors allowed by default", config.DoNotDisturbConfig{}, LevelError, true, true, 0},
+		{"allow list replaces default", config.DoNotDisturbConfig{Allow: []string{"warning"}}, LevelError, false, false, 1},
+		{"allowed level shown", config.DoNotDisturbConfig{Allow: []string{"warning"}}, LevelWarning, true, true, 0},
+		{"downgrade keeps console", config.DoNotDisturbConfig{Mode: config.DNDModeDowngrade}, LevelSuccess, false, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			console := NewConsoleNotifier()
+			system := NewMockNotifier(true)
+
+			manager := &Manager{notifiers: []Notifier{console, system}}
+			dnd := NewDoNotDisturb()
+			dnd.Configure(tt.cfg)
+			dnd.SetEnabled(true)
+			manager.SetDoNotDisturb(dnd)
+
+			gotConsole, gotSystem := false, false
+			targets, isHeld := manager.targets(Notification{Title: "Spell Cast", Level: tt.level})
+			if isHeld != (tt.wantHeld > 0) {
+				t.Errorf("targets held = %v, want %v", isHeld, tt.wantHeld > 0)
+			}
+			for _, target := range targets {
+				switch target {
+				case Notifier(console):
+					gotConsole = true
+				case Notifier(system):
+					gotSystem = true
+				}
+			}
+			if gotConsole != tt.wantConsole || gotSystem != tt.wantSystem {
+				t.Errorf("targets console = %v, system = %v, want %v, %v", gotConsole, gotSystem, tt.wantConsole, tt.wantSystem)
+			}
+			dnd.ClearHeld()
+
+			if err := manager.Notify(context.Background(), Notification{Title: "Spell Cast", Level: tt.level, Spell: "editor"}); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if got := len(system.GetNotifications()) > 0; got != tt.wantSystem {
+				t.Errorf("system notified = %v, want %v", got, tt.wantSystem)
+			}
+			held := dnd.Held()
+			if len(held) != tt.wantHeld {
+				t.Fatalf("held = %d, want %d", len(held), tt.wantHeld)
+			}
+			if tt.wantHeld > 0 && held[0].Spell != "editor" {
+				t.Errorf("held spell = %q, want editor", held[0].Spell)
+			}
+		})
+	}
+}
+
+func TestManager_DoNotDisturbOff(t *testing.T) {
+	system := NewMockNotifier(true)
+	manager := &Manager{notifiers: []Notifier{system}}
+	dnd := NewDoNotDisturb()
+	manager.SetDoNotDisturb(dnd)
+
+	if err := manager.Info(context.Background(), "Spell Cast", "editor"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if len(system.GetNotifications()) != 1 {
+		t.Errorf("Expected notification while do-not-disturb is off")
+	}
+	if len(dnd.Held()) != 0 {
+		t.Errorf("Expected nothing held, got %d", len(dnd.Held()))
+	}
+}
+
+func TestDoNotDisturb_HeldLimit(t *testing.T) {
+	dnd := NewDoNotDisturb()
+	dnd.SetEnabled(true)
+
+	for i := 0; i < maxHeldNotifications+5; i++ {
+		dnd.intercept(Notification{Title: "n", Level: LevelInfo})
+	}
+	if got := len(dnd.Held()); got != maxHeldNotifications {
+		t.Errorf("held = %d, want %d", got, maxHeldNotifications)
+	}
+	if cleared := dnd.ClearHeld(); cleared != maxHeldNotifications {
+		t.Errorf("ClearHeld() = %d, want %d", cleared, maxHeldNotifications)
+	}
+	if len(dnd.Held()) != 0 {
+		t.Error("Expected no held notifications after clear")
+	}
+}
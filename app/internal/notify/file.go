@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/internal/errors"
+)
+
+// FileRecord is the JSON line written for each notification
+type FileRecord struct {
+	Time           time.Time `json:"time"`
+	Level          string    `json:"level"`
+	Title          string    `json:"title"`
+	Message        string    `json:"message,omitempty"`
+	Spell          string    `json:"spell,omitempty"`
+	Group          string    `json:"group,omitempty"`
+	Output         string    `json:"output,omitempty"`
+	TruncatedBytes int       `json:"truncated_bytes,omitempty"`
+	ExitCode       *int      `json:"exit_code,omitempty"`
+}
+
+// defaultFileMaxSize is the size in bytes at which the file is rotated
+const defaultFileMaxSize = 1024 * 1024
+
+// FileNotifier appends every notification as a JSON line to a file, or writes
+// it to a named pipe (FIFO) for a status bar to read. Writes to a pipe that
+// nobody is reading are dropped instead of blocking. A file that would grow
+// beyond its maximum size is renamed to <path>.1, replacing the previous one.
+type FileNotifier struct {
+	mu              sync.Mutex
+	path            string
+	levels          map[Level]bool // nil writes every level
+	maxOutputLength int
+	maxSize         int64
+	now             func() time.Time
+}
+
+// NewFileNotifier creates a notifier from the file section of the notification configuration
+func NewFileNotifier(cfg config.FileNotifierConfig) (*FileNotifier, error) {
+	path := dotenv.ExpandPath(cfg.Path, "")
+	if path == "" {
+		return nil, errors.New(errors.ErrorTypeConfig, "notification file path is required")
+	}
+
+	var levels map[Level]bool
+	if len(cfg.Levels) > 0 {
+		levels = make(map[Level]bool, len(cfg.Levels))
+		for _, name := range cfg.Levels {
+			if level, ok := parseLevel(name); ok {
+				levels[level] = true
+			}
+		}
+	}
+
+	maxSize := int64(defaultFileMaxSize)
+	if cfg.MaxSize > 0 {
+		maxSize = int64(cfg.MaxSize) * 1024
+	}
+
+	return &FileNotifier{
+		path:            path,
+		levels:          levels,
+		maxOutputLength: 1024,
+		maxSize:         maxSize,
+		now:             time.Now,
+	}, nil
+}
+
+// Path returns the expanded path written to
+func (f *FileNotifier) Path() string {
+	return f.path
+}
+
+// Notify writes a notification
+func (f *FileNotifier) Notify(_ context.Context, notification Notification) error {
+	return f.write(f.newRecord(notification))
+}
+
+// ShowWithOutput writes a notification with command output
+func (f *FileNotifier) ShowWithOutput(_ context.Context, notification OutputNotification) error {
+	record := f.newRecord(notification.Notification)
+	if record == nil {
+		return nil
+	}
+	record.Output = notification.Output
+	record.TruncatedBytes = notification.TruncatedBytes
+	if len(record.Output) > f.maxOutputLength {
+		// Cut on a rune boundary so that no invalid UTF-8 reaches the JSON
+		output := truncateOutput(record.Output, f.maxOutputLength)
+		record.TruncatedBytes += len(record.Output) - len(output)
+		record.Output = output
+	}
+	if notification.ExitCode >= 0 {
+		exitCode := notification.ExitCode
+		record.ExitCode = &exitCode
+	}
+	return f.write(record)
+}
+
+// SetMaxOutputLength sets the maximum output length written
+func (f *FileNotifier) SetMaxOutputLength(maxLength int) int {
+	if maxLength > 0 {
+		f.maxOutputLength = maxLength
+	}
+	return f.maxOutputLength
+}
+
+// SupportsRichContent returns false; records are plain JSON
+func (f *FileNotifier) SupportsRichContent() bool {
+	return false
+}
+
+// IsAvailable always returns true; write errors are reported per notification
+func (f *FileNotifier) IsAvailable() bool {
+	return true
+}
+
+// newRecord converts a notification to a record
+func (f *FileNotifier) newRecord(notification Notification) *FileRecord {
+	if f.levels != nil && !f.levels[notification.Level] {
+		return nil
+	}
+	return &FileRecord{
+		Time:    f.now(),
+		Level:   strings.ToLower(notification.Level.String()),
+		Title:   notification.Title,
+		Message: notification.Message,
+		Spell:   notification.Spell,
+		Group:   notification.Group,
+	}
+}
+
+// write appends a record as one line; a nil record is skipped
+func (f *FileNotifier) write(record *FileRecord) error {
+	if record == nil {
+		return nil
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to encode notification record", err)
+	}
+	line = append(line, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := f.open(len(line))
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeIO, "failed to open notification file", err).
+			WithContext("path", f.path)
+	}
+	if file == nil {
+		return nil // Nobody is reading the pipe
+	}
+	defer file.Close()
+
+	// A single write keeps lines whole for pipe readers and concurrent appenders
+	if _, err := file.Write(line); err != nil {
+		return errors.Wrap(errors.ErrorTypeIO, "failed to write notification file", err).
+			WithContext("path", f.path)
+	}
+	return nil
+}
+
+// open opens the path for appending size more bytes, rotating a file that
+// would exceed its maximum size. Named pipes are opened without blocking;
+// nil is returned when no reader has the pipe open.
+func (f *FileNotifier) open(size int) (*os.File, error) {
+	info, err := os.Stat(f.path)
+	if err == nil && info.Mode()&os.ModeNamedPipe != 0 {
+		return openPipe(f.path)
+	}
+	if err == nil && info.Size() > 0 && info.Size()+int64(size) > f.maxSize {
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+}
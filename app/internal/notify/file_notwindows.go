@@ -0,0 +1,19 @@
+//go:build !windows
+
+package notify
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openPipe opens a named pipe for writing without blocking. It returns nil
+// when no reader has the pipe open.
+func openPipe(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if errors.Is(err, syscall.ENXIO) {
+		return nil, nil
+	}
+	return file, err
+}
@@ -0,0 +1,54 @@
+//go:build !windows
+
+package notify
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestFileNotifier_FIFO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.fifo")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skipf("Mkfifo() error = %v", err)
+	}
+	notifier, err := NewFileNotifier(config.FileNotifierConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewFileNotifier() error = %v", err)
+	}
+
+	// Without a reader the notification is dropped instead of blocking
+	if err := notifier.Notify(context.Background(), Notification{Title: "dropped"}); err != nil {
+		t.Fatalf("Notify() without reader error = %v", err)
+	}
+
+	reader, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer reader.Close()
+
+	if err := notifier.Notify(context.Background(), Notification{Title: "Spell Cast", Level: LevelInfo}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	line := readLine(t, bufio.NewReader(reader))
+	if !strings.Contains(line, `"title":"Spell Cast"`) {
+		t.Errorf("line = %q", line)
+	}
+}
+
+func readLine(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("ReadString() error = %v", err)
+	}
+	return line
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/events"
+)
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status", "events.jsonl")
+	notifier, err := NewFileNotifier(config.FileNotifierConfig{Path: path, Levels: []string{"error", "success"}})
+	if err != nil {
+		t.Fatalf("NewFileNotifier() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := notifier.Notify(ctx, Notification{Title: "Spell Cast", Level: LevelInfo}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if err := notifier.Notify(ctx, Notification{Title: "Spell Succeeded", Level: LevelSuccess, Spell: "build", Group: "build#1"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	notifier.SetMaxOutputLength(4)
+	err = notifier.ShowWithOutput(ctx, OutputNotification{
+		Notification: Notification{Title: "Spell Failed", Level: LevelError},
+		Output:       "undefined: foo",
+		ExitCode:     2,
+	})
+	if err != nil {
+		t.Fatalf("ShowWithOutput() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want 2 (info filtered):\n%s", len(lines), data)
+	}
+
+	var success, failure FileRecord
+	if err := json.Unmarshal([]byte(lines[0]), &success); err != nil {
+		t.Fatal(err)
+	}
+	if success.Level != "success" || success.Spell != "build" || success.Group != "build#1" || success.ExitCode != nil {
+		t.Errorf("success record = %+v", success)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &failure); err != nil {
+		t.Fatal(err)
+	}
+	if failure.Output != "unde" || failure.TruncatedBytes != 10 || failure.ExitCode == nil || *failure.ExitCode != 2 {
+		t.Errorf("failure record = %+v", failure)
+	}
+}
+
+func TestFileNotifier_TruncatesOnRuneBoundary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	notifier, err := NewFileNotifier(config.FileNotifierConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewFileNotifier() error = %v", err)
+	}
+	notifier.SetMaxOutputLength(4)
+
+	// "é" takes two bytes; a cut after four bytes would split the second one
+	err = notifier.ShowWithOutput(context.Background(), OutputNotification{
+		Notification: Notification{Title: "Spell Failed", Level: LevelError},
+		Output:       "aééé",
+	})
+	if err != nil {
+		t.Fatalf("ShowWithOutput() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var record FileRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Output != "aé" || record.TruncatedBytes != 4 {
+		t.Errorf("record = %+v, want output \"aé\" with 4 bytes truncated", record)
+	}
+}
+
+func TestFileNotifier_RotatesAtMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	notifier, err := NewFileNotifier(config.FileNotifierConfig{Path: path, MaxSize: 1})
+	if err != nil {
+		t.Fatalf("NewFileNotifier() error = %v", err)
+	}
+
+	ctx := context.Background()
+	message := strings.Repeat("x", 300)
+	for i := 0; i < 6; i++ {
+		if err := notifier.Notify(ctx, Notification{Title: "Spell Cast", Message: message, Level: LevelInfo}); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+
+	current, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	rotated, err := os.Stat(path + ".1")
+	if err != nil {
+		t.Fatalf("rotated file missing: %v", err)
+	}
+	if current.Size() > 1024 || rotated.Size() > 1024 {
+		t.Errorf("sizes = %d and %d, want at most 1024 bytes each", current.Size(), rotated.Size())
+	}
+	// Rotation only happens between whole lines
+	data, err := os.ReadFile(path + ".1")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record FileRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Errorf("rotated file has a partial line: %v", err)
+		}
+	}
+}
+
+func TestFileNotifier_ExpandsPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SILENTCAST_TEST_DIR", dir)
+	notifier, err := NewFileNotifier(config.FileNotifierConfig{Path: "$SILENTCAST_TEST_DIR/events.jsonl"})
+	if err != nil {
+		t.Fatalf("NewFileNotifier() error = %v", err)
+	}
+	if notifier.Path() != filepath.Join(dir, "events.jsonl") {
+		t.Errorf("Path() = %q", notifier.Path())
+	}
+
+	if _, err := NewFileNotifier(config.FileNotifierConfig{}); err == nil {
+		t.Error("Expected error without a path")
+	}
+}
+
+func TestManager_PublishesEvents(t *testing.T) {
+	bus := events.NewBus()
+	manager := &Manager{notifiers: []Notifier{NewMockNotifier(true)}}
+	manager.SetEvents(bus)
+
+	if err := ForSpell(manager, "build").Error(context.Background(), "Spell Failed", "exit 1"); err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+	n := bus.State().Notification
+	if n == nil || n.Title != "Spell Failed" || n.Level != "error" || n.Spell != "build" {
+		t.Errorf("published notification = %+v", n)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package notify
+
+import (
+	"os"
+)
+
+// openPipe opens a named pipe for writing. Named pipes in the file system are
+// a Unix feature, so this is only reached for unusual paths.
+func openPipe(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY, 0)
+}
@@ -0,0 +1,205 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HistoryFile is the file name of the notification history in the config directory
+const HistoryFile = "notification-history.jsonl"
+
+// DefaultHistoryEntries is the number of notifications kept when no limit is configured
+const DefaultHistoryEntries = 500
+
+// maxHistoryText bounds the message and output stored per entry
+const maxHistoryText = 64 * 1024
+
+// HistoryEntry is a recorded notification
+type HistoryEntry struct {
+	ID             int64     `json:"id"`
+	Time           time.Time `json:"time"`
+	Level          string    `json:"level"`
+	Title          string    `json:"title"`
+	Message        string    `json:"message,omitempty"`
+	Spell          string    `json:"spell,omitempty"`
+	Output         string    `json:"output,omitempty"`
+	TruncatedBytes int       `json:"truncated_bytes,omitempty"`
+	ExitCode       *int      `json:"exit_code,omitempty"`
+	Held           bool      `json:"held,omitempty"` // Held back by do-not-disturb
+}
+
+// Matches reports whether the entry contains query in any of its text fields, ignoring case
+func (e HistoryEntry) Matches(query string) bool {
+	if query == "" {
+		return true
+	}
+	query = strings.ToLower(query)
+	for _, field := range []string{e.Level, e.Title, e.Message, e.Spell, e.Output} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// History appends notifications to a JSON Lines file and keeps it bounded.
+// The file is rewritten with the newest entries once it grows past the limit
+// by a tenth, so most writes are a single append.
+type History struct {
+	mu         sync.Mutex
+	path       string
+	maxEntries int
+	count      int
+	nextID     int64
+	loaded     bool
+	now        func() time.Time
+}
+
+// NewHistory creates a history stored at path that keeps up to maxEntries notifications
+func NewHistory(path string, maxEntries int) *History {
+	if maxEntries <= 0 {
+		maxEntries = DefaultHistoryEntries
+	}
+	return &History{
+		path:       path,
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// SetMaxEntries changes the number of notifications kept
+func (h *History) SetMaxEntries(maxEntries int) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultHistoryEntries
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxEntries = maxEntries
+}
+
+// Record stores an entry, assigning its ID and, if unset, its time
+func (h *History) Record(entry HistoryEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.loaded {
+		entries, err := ReadHistory(h.path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		h.count = len(entries)
+		if len(entries) > 0 {
+			h.nextID = entries[len(entries)-1].ID
+		}
+		h.loaded = true
+	}
+
+	h.nextID++
+	entry.ID = h.nextID
+	if entry.Time.IsZero() {
+		entry.Time = h.now()
+	}
+	if len(entry.Message) > maxHistoryText {
+		entry.Message = entry.Message[:maxHistoryText]
+	}
+	if len(entry.Output) > maxHistoryText {
+		entry.TruncatedBytes += len(entry.Output) - maxHistoryText
+		entry.Output = entry.Output[:maxHistoryText]
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification history entry: %w", err)
+	}
+
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification history: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write notification history: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write notification history: %w", err)
+	}
+
+	h.count++
+	if h.count > h.maxEntries+h.maxEntries/10 {
+		return h.compactLocked()
+	}
+	return nil
+}
+
+// compactLocked rewrites the file with the newest maxEntries entries; h.mu must be held
+func (h *History) compactLocked() error {
+	entries, err := ReadHistory(h.path)
+	if err != nil {
+		return err
+	}
+	if len(entries) > h.maxEntries {
+		entries = entries[len(entries)-h.maxEntries:]
+	}
+
+	var sb strings.Builder
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode notification history entry: %w", err)
+		}
+		sb.Write(data)
+		sb.WriteByte('\n')
+	}
+
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(sb.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to compact notification history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to compact notification history: %w", err)
+	}
+	h.count = len(entries)
+	return nil
+}
+
+// ReadHistory loads the entries stored at path, oldest first.
+// Lines that cannot be decoded, such as a partially written last line, are skipped.
+func ReadHistory(path string) ([]HistoryEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read notification history: %w", err)
+	}
+	return entries, nil
+}
+
+// newHistoryEntry converts a notification into a history entry
+func newHistoryEntry(notification Notification, held bool) HistoryEntry {
+	return HistoryEntry{
+		Level:   strings.ToLower(notification.Level.String()),
+		Title:   notification.Title,
+		Message: notification.Message,
+		Spell:   notification.Spell,
+		Held:    held,
+	}
+}
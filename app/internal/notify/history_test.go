@@ -0,0 +1,162 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistory_RecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+	history := NewHistory(path, 10)
+
+	for i := 1; i <= 3; i++ {
+		if err := history.Record(HistoryEntry{Title: fmt.Sprintf("n%d", i), Level: "info"}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	entries, err := ReadHistory(path)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("len(entries) = %d, want 3", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.ID != int64(i+1) || entry.Time.IsZero() {
+			t.Errorf("entry %d: ID = %d, Time = %v", i, entry.ID, entry.Time)
+		}
+	}
+
+	// A new History continues numbering after the existing entries
+	if err := NewHistory(path, 10).Record(HistoryEntry{Title: "n4"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	entries, _ = ReadHistory(path)
+	if last := entries[len(entries)-1]; last.ID != 4 {
+		t.Errorf("resumed ID = %d, want 4", last.ID)
+	}
+}
+
+func TestHistory_Compaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+	history := NewHistory(path, 10)
+
+	for i := 0; i < 25; i++ {
+		if err := history.Record(HistoryEntry{Title: "n"}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	entries, err := ReadHistory(path)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(entries) > 11 {
+		t.Errorf("history not bounded: %d entries", len(entries))
+	}
+	if last := entries[len(entries)-1]; last.ID != 25 {
+		t.Errorf("newest entry ID = %d, want 25", last.ID)
+	}
+}
+
+func TestReadHistory_SkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+	content := `{"id":1,"title":"ok","level":"info"}
+not json
+{"id":2,"title":"partial`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	entries, err := ReadHistory(path)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Title != "ok" {
+		t.Errorf("entries = %+v, want only the valid one", entries)
+	}
+}
+
+func TestHistoryEntry_Matches(t *testing.T) {
+	entry := HistoryEntry{Level: "error", Title: "Spell Failed", Spell: "deploy", Output: "connection REFUSED"}
+
+	for query, want := range map[string]bool{
+		"":        true,
+		"refused": true,
+		"DEPLOY":  true,
+		"error":   true,
+		"success": false,
+	} {
+		if got := entry.Matches(query); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestManager_RecordsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+	manager := &Manager{
+		notifiers: []Notifier{NewMockNotifier(true)},
+		options:   NotificationOptions{MaxOutputLength: 5, Levels: map[Level]bool{LevelInfo: false}},
+	}
+	manager.SetHistory(NewHistory(path, 10))
+	dnd := NewDoNotDisturb()
+	manager.SetDoNotDisturb(dnd)
+	ctx := context.Background()
+
+	if err := manager.NotifyWithOutput(ctx, OutputNotification{
+		Notification: Notification{Title: "Script Output", Level: LevelSuccess, Spell: "build"},
+		Output:       "hello world",
+		ExitCode:     0,
+	}); err != nil {
+		t.Fatalf("NotifyWithOutput() error = %v", err)
+	}
+	// Disabled levels are not recorded
+	if err := manager.Info(ctx, "Spell Cast", "editor"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	dnd.SetEnabled(true)
+	if err := manager.Warning(ctx, "Timeout soon", "build"); err != nil {
+		t.Fatalf("Warning() error = %v", err)
+	}
+
+	entries, err := ReadHistory(path)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2: %+v", len(entries), entries)
+	}
+
+	output := entries[0]
+	if output.Spell != "build" || output.Output != "hello" || output.TruncatedBytes != 6 || output.ExitCode == nil || *output.ExitCode != 0 {
+		t.Errorf("unexpected output entry: %+v", output)
+	}
+	if held := entries[1]; !held.Held || held.Level != "warning" {
+		t.Errorf("unexpected held entry: %+v", held)
+	}
+}
+
+func TestHistory_BoundsText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+	long := make([]byte, maxHistoryText+10)
+	for i := range long {
+		long[i] = 'x'
+	}
+
+	if err := NewHistory(path, 10).Record(HistoryEntry{Message: string(long), Output: string(long), TruncatedBytes: 5}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	entries, err := ReadHistory(path)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	entry := entries[0]
+	if len(entry.Message) != maxHistoryText || len(entry.Output) != maxHistoryText || entry.TruncatedBytes != 15 {
+		t.Errorf("message %d, output %d, truncated %d", len(entry.Message), len(entry.Output), entry.TruncatedBytes)
+	}
+}
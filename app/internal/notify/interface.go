@@ -3,6 +3,11 @@ package notify
 import (
 	"context"
 	"fmt"
+	"unicode/utf8"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/events"
+	"github.com/SphereStacking/silentcast/pkg/logger"
 )
 
 // Level represents the notification level
@@ -40,6 +45,17 @@ type Notification struct {
 	Title   string
 	Message string
 	Level   Level
+	Spell   string   // Grimoire action that raised the notification, if any
+	Actions []Action // Buttons, shown by notifiers that support them
+	Group   string   // Notifications of a group replace each other where supported
+	Cue     string   // Sound cue event; empty plays the cue of the level, if any
+}
+
+// Action is a button on a notification. Notifiers that cannot show buttons ignore it.
+type Action struct {
+	Key     string // Identifier reported when the button is pressed
+	Label   string // Button text
+	Handler func() // Called when the button is pressed
 }
 
 // OutputNotification represents a notification with command output
@@ -50,6 +66,19 @@ type OutputNotification struct {
 	ExitCode       int    // Command exit code (-1 if not applicable)
 }
 
+// truncateOutput returns at most maxLength bytes of output, cut on a rune
+// boundary so that no partial UTF-8 sequence is shown
+func truncateOutput(output string, maxLength int) string {
+	if len(output) <= maxLength {
+		return output
+	}
+	cut := maxLength
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+	return output[:cut]
+}
+
 // TimeoutNotification represents a timeout-specific notification
 type TimeoutNotification struct {
 	Notification
@@ -58,6 +87,8 @@ type TimeoutNotification struct {
 	ElapsedTime     int    // Actual elapsed time in seconds
 	WasGraceful     bool   // Whether process exited gracefully during grace period
 	Output          string // Partial output captured before timeout
+	Warning         bool   // Sent before the timeout expires rather than after it
+	Rendered        bool   // Title and Message are already written, e.g. from a notify_template
 }
 
 // UpdateNotification represents an update-related notification
@@ -92,6 +123,47 @@ type NotificationOptions struct {
 	Priority string // "low", "normal", "high", "critical"
 	// Sound plays notification sound if supported
 	Sound bool
+	// Levels disables individual levels; levels missing from the map are shown
+	Levels map[Level]bool
+	// SpellLevels overrides Levels for notifications raised by a spell
+	SpellLevels map[string]map[Level]bool
+	// Timeouts shows a notification when a script times out
+	Timeouts bool
+	// TimeoutWarnings shows a notification shortly before a script times out
+	TimeoutWarnings bool
+}
+
+// Allows reports whether a notification of the given level raised by spell is shown
+func (o NotificationOptions) Allows(level Level, spell string) bool {
+	if enabled, ok := o.SpellLevels[spell][level]; ok {
+		return enabled
+	}
+	if enabled, ok := o.Levels[level]; ok {
+		return enabled
+	}
+	return true
+}
+
+// UpdateActionNotifier is implemented by notifiers that receive button presses on
+// update notifications and pass them to a handler
+type UpdateActionNotifier interface {
+	UpdateNotifier
+
+	// SetUpdateActionHandler sets the function called when an update action is chosen
+	SetUpdateActionHandler(handler func(action UpdateAction, updateInfo *UpdateNotification) error)
+}
+
+// SoundNotifier is implemented by notifiers whose sound can be turned off
+type SoundNotifier interface {
+	Notifier
+
+	// SetSound enables or disables notification sounds
+	SetSound(enabled bool)
+}
+
+// SoundPlayer plays the sound cue of an event, such as config.SoundEventSuccess
+type SoundPlayer interface {
+	Play(event string)
 }
 
 // Notifier is the interface for sending notifications
@@ -132,10 +204,26 @@ type UpdateNotifier interface {
 	OnUpdateAction(action UpdateAction, updateInfo *UpdateNotification) error
 }
 
+// Sender is implemented by Manager, which delivers notifications synchronously,
+// and by NotificationQueue, which delivers them in the background
+type Sender interface {
+	Notify(ctx context.Context, notification Notification) error
+	Info(ctx context.Context, title, message string) error
+	Warning(ctx context.Context, title, message string) error
+	Error(ctx context.Context, title, message string) error
+	Success(ctx context.Context, title, message string) error
+	NotifyTimeout(ctx context.Context, notification *TimeoutNotification) error
+}
+
 // Manager manages multiple notifiers
 type Manager struct {
 	notifiers []Notifier
 	options   NotificationOptions
+	dnd       *DoNotDisturb
+	history   *History
+	coalescer *Coalescer
+	events    *events.Bus
+	sounds    SoundPlayer
 }
 
 // NewManager creates a new notification manager
@@ -146,6 +234,8 @@ func NewManager() *Manager {
 			MaxOutputLength: 1024, // Default 1KB
 			FormatAsCode:    true,
 			Sound:           true,
+			Timeouts:        true,
+			TimeoutWarnings: true,
 		},
 	}
 
@@ -168,13 +258,132 @@ func (m *Manager) AddNotifier(notifier Notifier) {
 	}
 }
 
+// SetDoNotDisturb makes the manager hold back notifications while d is active
+func (m *Manager) SetDoNotDisturb(d *DoNotDisturb) {
+	m.dnd = d
+}
+
+// SetHistory makes the manager record every notification it delivers or holds
+func (m *Manager) SetHistory(h *History) {
+	m.history = h
+}
+
+// SetCoalescer makes the manager merge repeated notifications and replace
+// notifications of the same group
+func (m *Manager) SetCoalescer(c *Coalescer) {
+	m.coalescer = c
+}
+
+// SetEvents makes the manager publish every notification it delivers or holds
+func (m *Manager) SetEvents(bus *events.Bus) {
+	m.events = bus
+}
+
+// SetSounds makes the manager play a sound cue for notifications that are shown
+func (m *Manager) SetSounds(p SoundPlayer) {
+	m.sounds = p
+}
+
+// playCue plays the sound cue of a notification, if it has one
+func (m *Manager) playCue(notification Notification) {
+	if m.sounds == nil {
+		return
+	}
+	cue := notification.Cue
+	if cue == "" {
+		switch notification.Level {
+		case LevelSuccess:
+			cue = config.SoundEventSuccess
+		case LevelError:
+			cue = config.SoundEventFailure
+		default:
+			return
+		}
+	}
+	m.sounds.Play(cue)
+}
+
+// record stores a notification in the history and publishes it, if either is set
+func (m *Manager) record(entry HistoryEntry) {
+	if m.events != nil {
+		m.events.Notify(events.Notification{
+			Level:   entry.Level,
+			Title:   entry.Title,
+			Message: entry.Message,
+			Spell:   entry.Spell,
+			Held:    entry.Held,
+		})
+	}
+	if m.history == nil {
+		return
+	}
+	if err := m.history.Record(entry); err != nil {
+		logger.Warn("Failed to record notification history: %v", err)
+	}
+}
+
+// targets returns the notifiers that should show the notification, taking
+// do-not-disturb into account, and whether do-not-disturb held it back
+func (m *Manager) targets(notification Notification) ([]Notifier, bool) {
+	if m.dnd == nil {
+		return m.notifiers, false
+	}
+	held, consoleOnly := m.dnd.intercept(notification)
+	if !held {
+		return m.notifiers, false
+	}
+	if !consoleOnly {
+		return nil, true
+	}
+
+	var consoles []Notifier
+	for _, notifier := range m.notifiers {
+		if _, ok := notifier.(*ConsoleNotifier); ok {
+			consoles = append(consoles, notifier)
+		}
+	}
+	return consoles, true
+}
+
 // Notify sends a notification through all available notifiers
 func (m *Manager) Notify(ctx context.Context, notification Notification) error {
+	if !m.options.Allows(notification.Level, notification.Spell) {
+		return nil
+	}
+
+	targets, held := m.targets(notification)
+	m.record(newHistoryEntry(notification, held))
+	if m.coalescer != nil && len(targets) > 0 {
+		var show bool
+		notification, show = m.coalescer.admit(notification, func(summary Notification) {
+			if err := m.deliver(context.Background(), targets, summary); err != nil {
+				logger.Warn("Failed to send notification summary: %v", err)
+			}
+		})
+		if !show {
+			return nil
+		}
+	}
+	if !held {
+		m.playCue(notification)
+	}
+	return m.deliver(ctx, targets, notification)
+}
+
+// deliver shows a notification on each target, replacing the group's previous
+// notification on notifiers that support it
+func (m *Manager) deliver(ctx context.Context, targets []Notifier, notification Notification) error {
 	var lastError error
 	notified := false
 
-	for _, notifier := range m.notifiers {
-		if err := notifier.Notify(ctx, notification); err != nil {
+	for _, notifier := range targets {
+		var err error
+		if replacer, ok := notifier.(ReplaceNotifier); ok && m.coalescer != nil && notification.Group != "" {
+			err = m.coalescer.show(ctx, replacer, notification)
+		} else {
+			err = notifier.Notify(ctx, notification)
+		}
+		if err != nil {
 			lastError = err
 		} else {
 			notified = true
@@ -254,6 +463,21 @@ var getSystemNotifier func() Notifier
 // SetOptions updates the notification options
 func (m *Manager) SetOptions(options NotificationOptions) {
 	m.options = options
+
+	for _, notifier := range m.notifiers {
+		if soundNotifier, ok := notifier.(SoundNotifier); ok {
+			soundNotifier.SetSound(options.Sound)
+		}
+	}
+}
+
+// SetUpdateActionHandler sets the handler for update actions on every notifier that supports them
+func (m *Manager) SetUpdateActionHandler(handler func(action UpdateAction, updateInfo *UpdateNotification) error) {
+	for _, notifier := range m.notifiers {
+		if actionNotifier, ok := notifier.(UpdateActionNotifier); ok {
+			actionNotifier.SetUpdateActionHandler(handler)
+		}
+	}
 }
 
 // GetOptions returns the current notification options
@@ -263,16 +487,34 @@ func (m *Manager) GetOptions() NotificationOptions {
 
 // NotifyWithOutput sends an output notification through all capable notifiers
 func (m *Manager) NotifyWithOutput(ctx context.Context, notification OutputNotification) error {
+	if !m.options.Allows(notification.Level, notification.Spell) {
+		return nil
+	}
+
 	var lastError error
 	notified := false
 
 	// Truncate output if needed
 	if m.options.MaxOutputLength > 0 && len(notification.Output) > m.options.MaxOutputLength {
-		notification.TruncatedBytes = len(notification.Output) - m.options.MaxOutputLength
-		notification.Output = notification.Output[:m.options.MaxOutputLength]
+		output := truncateOutput(notification.Output, m.options.MaxOutputLength)
+		notification.TruncatedBytes = len(notification.Output) - len(output)
+		notification.Output = output
 	}
 
-	for _, notifier := range m.notifiers {
+	targets, held := m.targets(notification.Notification)
+	entry := newHistoryEntry(notification.Notification, held)
+	entry.Output = notification.Output
+	entry.TruncatedBytes = notification.TruncatedBytes
+	if notification.ExitCode >= 0 {
+		exitCode := notification.ExitCode
+		entry.ExitCode = &exitCode
+	}
+	m.record(entry)
+	if !held {
+		m.playCue(notification.Notification)
+	}
+
+	for _, notifier := range targets {
 		// Try to use OutputNotifier interface if available
 		if outputNotifier, ok := notifier.(OutputNotifier); ok {
 			if err := outputNotifier.ShowWithOutput(ctx, notification); err != nil {
@@ -326,6 +568,19 @@ func (m *Manager) GetOutputNotifiers() []OutputNotifier {
 
 // NotifyTimeout sends a timeout-specific notification
 func (m *Manager) NotifyTimeout(ctx context.Context, notification *TimeoutNotification) error {
+	if notification.Warning {
+		return m.notifyTimeoutWarning(ctx, notification)
+	}
+	if !m.options.Timeouts {
+		return nil
+	}
+
+	notification.Notification.Cue = config.SoundEventTimeout
+	if notification.Rendered {
+		notification.Notification.Level = LevelWarning
+		return m.Notify(ctx, notification.Notification)
+	}
+
 	// Set the title to include the action name
 	notification.Notification.Title = fmt.Sprintf("⏱️ Timeout: %s", notification.ActionName)
 
@@ -366,8 +621,25 @@ func (m *Manager) NotifyTimeout(ctx context.Context, notification *TimeoutNotifi
 	return m.Notify(ctx, notification.Notification)
 }
 
+// notifyTimeoutWarning warns that a script is about to be stopped
+func (m *Manager) notifyTimeoutWarning(ctx context.Context, notification *TimeoutNotification) error {
+	if !m.options.TimeoutWarnings {
+		return nil
+	}
+
+	notification.Notification.Title = fmt.Sprintf("⏳ Timeout soon: %s", notification.ActionName)
+	notification.Notification.Message = fmt.Sprintf("Script will be stopped in %d seconds (timeout: %d seconds)",
+		notification.TimeoutDuration-notification.ElapsedTime, notification.TimeoutDuration)
+	notification.Notification.Level = LevelWarning
+	return m.Notify(ctx, notification.Notification)
+}
+
 // NotifyUpdate sends an update notification through all capable notifiers
 func (m *Manager) NotifyUpdate(ctx context.Context, notification *UpdateNotification) error {
+	if !m.options.Allows(notification.Level, notification.Spell) {
+		return nil
+	}
+
 	var lastError error
 	notified := false
 
@@ -377,7 +649,9 @@ func (m *Manager) NotifyUpdate(ctx context.Context, notification *UpdateNotifica
 			notification.CurrentVersion, notification.NewVersion)
 	}
 
-	for _, notifier := range m.notifiers {
+	targets, held := m.targets(notification.Notification)
+	m.record(newHistoryEntry(notification.Notification, held))
+	for _, notifier := range targets {
 		// Try to use UpdateNotifier interface if available
 		if updateNotifier, ok := notifier.(UpdateNotifier); ok {
 			if err := updateNotifier.ShowUpdateNotification(ctx, notification); err != nil {
@@ -364,3 +364,44 @@ func TestManager_GetUpdateNotifiers(t *testing.T) {
 		t.Error("GetUpdateNotifiers() should return ConsoleNotifier")
 	}
 }
+
+// cueRecorder is a SoundPlayer that records the cues played
+type cueRecorder struct {
+	cues []string
+}
+
+func (r *cueRecorder) Play(event string) {
+	r.cues = append(r.cues, event)
+}
+
+func TestManager_SoundCues(t *testing.T) {
+	ctx := context.Background()
+	m := &Manager{options: NotificationOptions{Timeouts: true}}
+	m.AddNotifier(&mockNotifier{name: "test", available: true})
+	cues := &cueRecorder{}
+	m.SetSounds(cues)
+
+	_ = m.Info(ctx, "Executing", "no cue for info")
+	_ = m.Success(ctx, "Done", "")
+	_ = m.Error(ctx, "Failed", "")
+	_ = m.NotifyWithOutput(ctx, OutputNotification{Notification: Notification{Title: "Done", Level: LevelSuccess}, Output: "ok"})
+	_ = m.NotifyTimeout(ctx, &TimeoutNotification{Notification: Notification{Title: "Slow"}, ActionName: "slow", TimeoutDuration: 5})
+	_ = m.Notify(ctx, Notification{Title: "Custom", Level: LevelInfo, Cue: "prefix"})
+
+	want := "success failure success timeout prefix"
+	if got := strings.Join(cues.cues, " "); got != want {
+		t.Errorf("cues = %q, want %q", got, want)
+	}
+
+	// Notifications held by do-not-disturb stay silent
+	cues.cues = nil
+	dnd := NewDoNotDisturb()
+	if _, err := dnd.Apply("on"); err != nil {
+		t.Fatal(err)
+	}
+	m.SetDoNotDisturb(dnd)
+	_ = m.Success(ctx, "Done", "")
+	if len(cues.cues) != 0 {
+		t.Errorf("cues during do-not-disturb = %v, want none", cues.cues)
+	}
+}
@@ -273,7 +273,12 @@ func (n *LinuxNotifier) IsAvailable() bool {
 
 func init() {
 	// Register Linux notifier factory
+	// Prefer talking to the notification server directly, which supports
+	// action buttons; fall back to command-line tools without a session bus
 	getSystemNotifier = func() Notifier {
+		if notifier := getDBusNotifier(); notifier != nil && notifier.IsAvailable() {
+			return notifier
+		}
 		return NewLinuxNotifier("SilentCast")
 	}
 }
@@ -0,0 +1,403 @@
+//go:build linux
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/SphereStacking/silentcast/internal/errors"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// Desktop Notifications service on the session bus
+const (
+	dbusNotificationsName  = "org.freedesktop.Notifications"
+	dbusNotificationsPath  = dbus.ObjectPath("/org/freedesktop/Notifications")
+	dbusNotificationsIface = "org.freedesktop.Notifications"
+)
+
+// Urgency levels defined by the Desktop Notifications specification
+const (
+	dbusUrgencyLow      byte = 0
+	dbusUrgencyNormal   byte = 1
+	dbusUrgencyCritical byte = 2
+)
+
+// updateActionLabels are the button labels for update actions
+var updateActionLabels = map[UpdateAction]string{
+	UpdateActionUpdate:  "Update",
+	UpdateActionView:    "Release notes",
+	UpdateActionRemind:  "Remind me later",
+	UpdateActionDismiss: "Dismiss",
+}
+
+// DBusNotifier talks to org.freedesktop.Notifications over the session bus.
+// Unlike LinuxNotifier it shows action buttons, replaces notifications in place,
+// and receives ActionInvoked signals.
+type DBusNotifier struct {
+	appName         string
+	conn            *dbus.Conn
+	obj             dbus.BusObject
+	capabilities    map[string]bool
+	signals         chan *dbus.Signal
+	maxOutputLength int
+
+	mu            sync.Mutex
+	muted         bool
+	actions       map[uint32]map[string]func()
+	updateHandler func(action UpdateAction, updateInfo *UpdateNotification) error
+}
+
+// NewDBusNotifier connects to the session bus and checks that a notification
+// server is running. It never starts a bus itself.
+func NewDBusNotifier(appName string) (*DBusNotifier, error) {
+	address := sessionBusAddress()
+	if address == "" {
+		return nil, errors.New(errors.ErrorTypeSystem, "no D-Bus session bus found").
+			WithContext("platform", "linux").
+			WithContext("suggested_action", "set DBUS_SESSION_BUS_ADDRESS")
+	}
+
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrorTypeSystem, "failed to connect to D-Bus session bus", err).
+			WithContext("address", address)
+	}
+
+	notifier, err := newDBusNotifier(conn, appName)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return notifier, nil
+}
+
+// newDBusNotifier creates a notifier on an established connection
+func newDBusNotifier(conn *dbus.Conn, appName string) (*DBusNotifier, error) {
+	obj := conn.Object(dbusNotificationsName, dbusNotificationsPath)
+
+	var capabilities []string
+	if err := obj.Call(dbusNotificationsIface+".GetCapabilities", 0).Store(&capabilities); err != nil {
+		return nil, errors.Wrap(errors.ErrorTypeSystem, "notification server not available on D-Bus", err).
+			WithContext("service", dbusNotificationsName).
+			WithContext("suggested_action", "check if a notification daemon is running")
+	}
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(dbusNotificationsPath),
+		dbus.WithMatchInterface(dbusNotificationsIface),
+	); err != nil {
+		return nil, errors.Wrap(errors.ErrorTypeSystem, "failed to subscribe to notification signals", err)
+	}
+
+	n := &DBusNotifier{
+		appName:         appName,
+		conn:            conn,
+		obj:             obj,
+		capabilities:    make(map[string]bool, len(capabilities)),
+		signals:         make(chan *dbus.Signal, 16),
+		maxOutputLength: 500,
+		actions:         make(map[uint32]map[string]func()),
+	}
+	for _, capability := range capabilities {
+		n.capabilities[capability] = true
+	}
+
+	conn.Signal(n.signals)
+	go n.dispatchSignals()
+	return n, nil
+}
+
+// sessionBusAddress returns the session bus address from the environment or
+// the user's runtime directory, or "" if there is none
+func sessionBusAddress() string {
+	if address := os.Getenv("DBUS_SESSION_BUS_ADDRESS"); address != "" && address != "autolaunch:" {
+		return address
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		path := filepath.Join(runtimeDir, "bus")
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return "unix:path=" + path
+		}
+	}
+	return ""
+}
+
+// Close disconnects from the session bus
+func (n *DBusNotifier) Close() error {
+	return n.conn.Close()
+}
+
+// Notify sends a notification
+func (n *DBusNotifier) Notify(ctx context.Context, notification Notification) error {
+	_, err := n.Show(ctx, notification, 0)
+	return err
+}
+
+// Show displays a notification and returns its ID. A non-zero replacesID
+// updates that notification in place instead of showing a new one.
+func (n *DBusNotifier) Show(ctx context.Context, notification Notification, replacesID uint32) (uint32, error) {
+	return n.send(ctx, notification, notification.Message, replacesID)
+}
+
+// CloseNotification removes a notification from the screen
+func (n *DBusNotifier) CloseNotification(ctx context.Context, id uint32) error {
+	if err := n.obj.CallWithContext(ctx, dbusNotificationsIface+".CloseNotification", 0, id).Err; err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to close notification", err).
+			WithContext("notification_id", id)
+	}
+	n.mu.Lock()
+	delete(n.actions, id)
+	n.mu.Unlock()
+	return nil
+}
+
+// ShowWithOutput implements OutputNotifier
+func (n *DBusNotifier) ShowWithOutput(ctx context.Context, notification OutputNotification) error {
+	body := notification.Message
+	if notification.Output != "" {
+		output := notification.Output
+		if len(output) > n.maxOutputLength {
+			output = truncateOutput(output, n.maxOutputLength) + "..."
+		}
+		if body != "" {
+			body += "\n\n"
+		}
+		body += "Output:\n" + output
+		if notification.ExitCode > 0 {
+			body += fmt.Sprintf("\nExit code: %d", notification.ExitCode)
+		}
+		if notification.TruncatedBytes > 0 {
+			body += fmt.Sprintf("\n... (%d bytes truncated)", notification.TruncatedBytes)
+		}
+	}
+	_, err := n.send(ctx, notification.Notification, body, 0)
+	return err
+}
+
+// SetMaxOutputLength sets the maximum output length shown in a notification
+func (n *DBusNotifier) SetMaxOutputLength(maxLength int) int {
+	if maxLength > 0 {
+		n.maxOutputLength = maxLength
+	}
+	return n.maxOutputLength
+}
+
+// SupportsRichContent reports whether the server renders body markup
+func (n *DBusNotifier) SupportsRichContent() bool {
+	return n.capabilities["body-markup"]
+}
+
+// ShowUpdateNotification implements UpdateNotifier with a button per update action
+func (n *DBusNotifier) ShowUpdateNotification(ctx context.Context, notification *UpdateNotification) error {
+	base := notification.Notification
+	base.Actions = nil
+	for _, name := range notification.Actions {
+		action := UpdateAction(name)
+		label, ok := updateActionLabels[action]
+		if !ok {
+			label = name
+		}
+		base.Actions = append(base.Actions, Action{
+			Key:   name,
+			Label: label,
+			Handler: func() {
+				if err := n.OnUpdateAction(action, notification); err != nil {
+					logger.Warn("Update action %s failed: %v", action, err)
+				}
+			},
+		})
+	}
+
+	body := base.Message
+	if notification.ReleaseNotes != "" {
+		notes := notification.ReleaseNotes
+		if len(notes) > 200 {
+			notes = notes[:197] + "..."
+		}
+		body += "\n\n" + notes
+	}
+	_, err := n.send(ctx, base, body, 0)
+	return err
+}
+
+// SupportsUpdateActions reports whether the server shows action buttons
+func (n *DBusNotifier) SupportsUpdateActions() bool {
+	return n.capabilities["actions"]
+}
+
+// SetUpdateActionHandler sets the function called when an update action button is pressed
+func (n *DBusNotifier) SetUpdateActionHandler(handler func(action UpdateAction, updateInfo *UpdateNotification) error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.updateHandler = handler
+}
+
+// OnUpdateAction passes an update action to the handler set with SetUpdateActionHandler
+func (n *DBusNotifier) OnUpdateAction(action UpdateAction, updateInfo *UpdateNotification) error {
+	n.mu.Lock()
+	handler := n.updateHandler
+	n.mu.Unlock()
+
+	if handler == nil {
+		return errors.New(errors.ErrorTypeSystem, "no handler for update actions").
+			WithContext("notifier_type", "dbus").
+			WithContext("action", action)
+	}
+	return handler(action, updateInfo)
+}
+
+// SetSound enables or disables notification sounds
+func (n *DBusNotifier) SetSound(enabled bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.muted = !enabled
+}
+
+// IsAvailable reports whether the session bus connection is still open
+func (n *DBusNotifier) IsAvailable() bool {
+	return n.conn.Connected()
+}
+
+// send calls Notify on the server and remembers the action handlers for the returned ID
+func (n *DBusNotifier) send(ctx context.Context, notification Notification, body string, replacesID uint32) (uint32, error) {
+	title := notification.Title
+	if title == "" {
+		title = n.appName
+	}
+	if n.capabilities["body-markup"] {
+		body = escapeMarkup(body)
+	}
+
+	n.mu.Lock()
+	muted := n.muted
+	n.mu.Unlock()
+
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(dbusUrgency(notification.Level)),
+	}
+	if muted {
+		hints["suppress-sound"] = dbus.MakeVariant(true)
+	}
+
+	var actions []string
+	handlers := make(map[string]func())
+	if n.capabilities["actions"] {
+		for _, action := range notification.Actions {
+			actions = append(actions, action.Key, action.Label)
+			if action.Handler != nil {
+				handlers[action.Key] = action.Handler
+			}
+		}
+	}
+	if actions == nil {
+		actions = []string{}
+	}
+
+	var id uint32
+	call := n.obj.CallWithContext(ctx, dbusNotificationsIface+".Notify", 0,
+		n.appName, replacesID, dbusIcon(notification.Level), title, body, actions, hints, int32(-1))
+	if err := call.Store(&id); err != nil {
+		return 0, errors.Wrap(errors.ErrorTypeSystem, "D-Bus notification failed", err).
+			WithContext("notification_title", notification.Title).
+			WithContext("method", "dbus")
+	}
+
+	n.mu.Lock()
+	if replacesID != 0 {
+		delete(n.actions, replacesID)
+	}
+	if len(handlers) > 0 {
+		n.actions[id] = handlers
+	} else {
+		delete(n.actions, id)
+	}
+	n.mu.Unlock()
+	return id, nil
+}
+
+// dispatchSignals runs action handlers until the connection is closed
+func (n *DBusNotifier) dispatchSignals() {
+	for signal := range n.signals {
+		if signal.Path != dbusNotificationsPath || len(signal.Body) < 2 {
+			continue
+		}
+		id, ok := signal.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+
+		switch signal.Name {
+		case dbusNotificationsIface + ".ActionInvoked":
+			key, _ := signal.Body[1].(string)
+			n.mu.Lock()
+			handler := n.actions[id][key]
+			n.mu.Unlock()
+			if handler != nil {
+				go handler()
+			}
+		case dbusNotificationsIface + ".NotificationClosed":
+			n.mu.Lock()
+			delete(n.actions, id)
+			n.mu.Unlock()
+		}
+	}
+}
+
+// dbusIcon returns the freedesktop icon name for a level
+func dbusIcon(level Level) string {
+	switch level {
+	case LevelError:
+		return "dialog-error"
+	case LevelWarning:
+		return "dialog-warning"
+	case LevelSuccess:
+		return "emblem-default"
+	default:
+		return "dialog-information"
+	}
+}
+
+// dbusUrgency maps a level to a notification urgency
+func dbusUrgency(level Level) byte {
+	switch level {
+	case LevelError:
+		return dbusUrgencyCritical
+	case LevelInfo:
+		return dbusUrgencyLow
+	default:
+		return dbusUrgencyNormal
+	}
+}
+
+// escapeMarkup escapes text for servers that interpret body markup
+func escapeMarkup(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+var (
+	sharedDBusOnce     sync.Once
+	sharedDBusNotifier *DBusNotifier
+)
+
+// getDBusNotifier returns the process-wide D-Bus notifier, or nil if there is no
+// notification server. Managers are rebuilt on every config reload, so they
+// share one connection and its action handlers.
+func getDBusNotifier() *DBusNotifier {
+	sharedDBusOnce.Do(func() {
+		notifier, err := NewDBusNotifier("SilentCast")
+		if err != nil {
+			logger.Debug("D-Bus notifications unavailable, falling back to notify-send: %v", err)
+			return
+		}
+		sharedDBusNotifier = notifier
+	})
+	return sharedDBusNotifier
+}
@@ -0,0 +1,339 @@
+//go:build linux
+
+package notify
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakeNotificationServer records Notify calls on a private session bus
+type fakeNotificationServer struct {
+	capabilities []string
+
+	mu     sync.Mutex
+	nextID uint32
+	calls  []fakeNotifyCall
+	closed []uint32
+}
+
+type fakeNotifyCall struct {
+	replacesID uint32
+	summary    string
+	body       string
+	actions    []string
+	hints      map[string]dbus.Variant
+}
+
+func (s *fakeNotificationServer) GetCapabilities() ([]string, *dbus.Error) {
+	return s.capabilities, nil
+}
+
+func (s *fakeNotificationServer) Notify(appName string, replacesID uint32, icon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, timeout int32,
+) (uint32, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, fakeNotifyCall{replacesID: replacesID, summary: summary, body: body, actions: actions, hints: hints})
+	if replacesID != 0 {
+		return replacesID, nil
+	}
+	s.nextID++
+	return s.nextID, nil
+}
+
+func (s *fakeNotificationServer) CloseNotification(id uint32) *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = append(s.closed, id)
+	return nil
+}
+
+func (s *fakeNotificationServer) lastCall(t *testing.T) fakeNotifyCall {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.calls) == 0 {
+		t.Fatal("Expected a Notify call")
+	}
+	return s.calls[len(s.calls)-1]
+}
+
+// startPrivateBus runs a dbus-daemon for the test and returns its address
+func startPrivateBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "session.conf")
+	busConfig := fmt.Sprintf(`<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`, filepath.Join(dir, "bus"))
+	if err := os.WriteFile(configFile, []byte(busConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(daemon, "--config-file="+configFile, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Failed to start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Skipf("dbus-daemon did not print an address: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+// setupDBusNotifier connects a fake notification server and a notifier to a private bus
+func setupDBusNotifier(t *testing.T, capabilities ...string) (*DBusNotifier, *fakeNotificationServer, *dbus.Conn) {
+	t.Helper()
+	address := startPrivateBus(t)
+
+	serverConn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("Failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverConn.Close() })
+
+	server := &fakeNotificationServer{capabilities: capabilities}
+	if err := serverConn.Export(server, dbusNotificationsPath, dbusNotificationsIface); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := serverConn.RequestName(dbusNotificationsName, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("Failed to own %s: %v (reply %d)", dbusNotificationsName, err, reply)
+	}
+
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", address)
+	notifier, err := NewDBusNotifier("SilentCast")
+	if err != nil {
+		t.Fatalf("NewDBusNotifier() error = %v", err)
+	}
+	t.Cleanup(func() { notifier.Close() })
+	return notifier, server, serverConn
+}
+
+func TestDBusNotifier_Show(t *testing.T) {
+	notifier, server, _ := setupDBusNotifier(t, "body", "actions", "body-markup")
+	ctx := context.Background()
+
+	id, err := notifier.Show(ctx, Notification{
+		Title:   "Spell Failed",
+		Message: "exit <1>",
+		Level:   LevelError,
+		Actions: []Action{{Key: "rerun", Label: "Re-run"}},
+	}, 0)
+	if err != nil {
+		t.Fatalf("Show() error = %v", err)
+	}
+	if id == 0 {
+		t.Fatal("Expected a notification ID")
+	}
+
+	call := server.lastCall(t)
+	if call.summary != "Spell Failed" || call.body != "exit &lt;1&gt;" {
+		t.Errorf("summary = %q, body = %q", call.summary, call.body)
+	}
+	if strings.Join(call.actions, ",") != "rerun,Re-run" {
+		t.Errorf("actions = %v, want [rerun Re-run]", call.actions)
+	}
+	if urgency, _ := call.hints["urgency"].Value().(byte); urgency != dbusUrgencyCritical {
+		t.Errorf("urgency = %v, want critical", call.hints["urgency"])
+	}
+
+	updated, err := notifier.Show(ctx, Notification{Title: "Spell Succeeded", Level: LevelSuccess}, id)
+	if err != nil {
+		t.Fatalf("Show() error = %v", err)
+	}
+	if updated != id {
+		t.Errorf("Show() id = %d, want %d", updated, id)
+	}
+	if call = server.lastCall(t); call.replacesID != id {
+		t.Errorf("replaces id = %d, want %d", call.replacesID, id)
+	}
+
+	notifier.SetSound(false)
+	if err := notifier.Notify(ctx, Notification{Title: "Quiet"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if _, ok := server.lastCall(t).hints["suppress-sound"]; !ok {
+		t.Error("Expected suppress-sound hint when sound is off")
+	}
+
+	if err := notifier.CloseNotification(ctx, id); err != nil {
+		t.Fatalf("CloseNotification() error = %v", err)
+	}
+	server.mu.Lock()
+	closed := len(server.closed) == 1 && server.closed[0] == id
+	server.mu.Unlock()
+	if !closed {
+		t.Errorf("Expected notification %d to be closed", id)
+	}
+}
+
+func TestDBusNotifier_ActionInvoked(t *testing.T) {
+	notifier, _, serverConn := setupDBusNotifier(t, "body", "actions")
+	ctx := context.Background()
+
+	invoked := make(chan string, 1)
+	id, err := notifier.Show(ctx, Notification{
+		Title: "Spell Failed",
+		Actions: []Action{
+			{Key: "rerun", Label: "Re-run", Handler: func() { invoked <- "rerun" }},
+			{Key: "output", Label: "Show output", Handler: func() { invoked <- "output" }},
+		},
+	}, 0)
+	if err != nil {
+		t.Fatalf("Show() error = %v", err)
+	}
+
+	// Signals for other notifications are ignored
+	if err := serverConn.Emit(dbusNotificationsPath, dbusNotificationsIface+".ActionInvoked", id+1, "rerun"); err != nil {
+		t.Fatal(err)
+	}
+	if err := serverConn.Emit(dbusNotificationsPath, dbusNotificationsIface+".ActionInvoked", id, "output"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case key := <-invoked:
+		if key != "output" {
+			t.Errorf("invoked %q, want output", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Action handler was not called")
+	}
+
+	// Handlers are dropped once the notification is closed
+	if err := serverConn.Emit(dbusNotificationsPath, dbusNotificationsIface+".NotificationClosed", id, uint32(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := serverConn.Emit(dbusNotificationsPath, dbusNotificationsIface+".ActionInvoked", id, "rerun"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case key := <-invoked:
+		t.Errorf("Unexpected action %q after close", key)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestDBusNotifier_UpdateActions(t *testing.T) {
+	notifier, server, serverConn := setupDBusNotifier(t, "body", "actions")
+
+	if !notifier.SupportsUpdateActions() {
+		t.Fatal("Expected update actions to be supported")
+	}
+	if err := notifier.OnUpdateAction(UpdateActionView, nil); err == nil {
+		t.Error("Expected error without an update action handler")
+	}
+
+	handled := make(chan UpdateAction, 1)
+	notifier.SetUpdateActionHandler(func(action UpdateAction, updateInfo *UpdateNotification) error {
+		if updateInfo.NewVersion != "1.2.0" {
+			t.Errorf("NewVersion = %q, want 1.2.0", updateInfo.NewVersion)
+		}
+		handled <- action
+		return nil
+	})
+
+	err := notifier.ShowUpdateNotification(context.Background(), &UpdateNotification{
+		Notification: Notification{Title: "Update Available", Message: "Version 1.2.0"},
+		NewVersion:   "1.2.0",
+		Actions:      []string{"update", "view"},
+	})
+	if err != nil {
+		t.Fatalf("ShowUpdateNotification() error = %v", err)
+	}
+	call := server.lastCall(t)
+	if strings.Join(call.actions, ",") != "update,Update,view,Release notes" {
+		t.Errorf("actions = %v", call.actions)
+	}
+
+	if err := serverConn.Emit(dbusNotificationsPath, dbusNotificationsIface+".ActionInvoked", uint32(1), "view"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case action := <-handled:
+		if action != UpdateActionView {
+			t.Errorf("action = %q, want view", action)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Update action handler was not called")
+	}
+}
+
+func TestDBusNotifier_NoActionsCapability(t *testing.T) {
+	notifier, server, _ := setupDBusNotifier(t, "body")
+
+	if notifier.SupportsUpdateActions() {
+		t.Error("Expected update actions to be unsupported")
+	}
+	err := notifier.Notify(context.Background(), Notification{
+		Title:   "Spell Failed",
+		Actions: []Action{{Key: "rerun", Label: "Re-run"}},
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if call := server.lastCall(t); len(call.actions) != 0 {
+		t.Errorf("actions = %v, want none", call.actions)
+	}
+}
+
+func TestDBusNotifier_ShowWithOutputCutsOnRuneBoundary(t *testing.T) {
+	notifier, server, _ := setupDBusNotifier(t, "body")
+	notifier.SetMaxOutputLength(4)
+
+	err := notifier.ShowWithOutput(context.Background(), OutputNotification{
+		Notification: Notification{Title: "Spell Cast"},
+		Output:       "ab→cd",
+		ExitCode:     -1,
+	})
+	if err != nil {
+		t.Fatalf("ShowWithOutput() error = %v", err)
+	}
+	if body := server.lastCall(t).body; body != "Output:\nab..." {
+		t.Errorf("body = %q, want %q", body, "Output:\nab...")
+	}
+}
+
+func TestNewDBusNotifier_NoBus(t *testing.T) {
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "")
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	if _, err := NewDBusNotifier("SilentCast"); err == nil {
+		t.Error("Expected error without a session bus")
+	}
+}
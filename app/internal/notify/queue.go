@@ -16,14 +16,29 @@ const (
 	PriorityCritical = 3
 )
 
+// PriorityForLevel returns the default queue priority for a notification level.
+// Failures are delivered before routine feedback such as spell casts.
+func PriorityForLevel(level Level) int {
+	switch level {
+	case LevelError:
+		return PriorityHigh
+	case LevelWarning, LevelSuccess:
+		return PriorityNormal
+	default:
+		return PriorityLow
+	}
+}
+
 // QueueItem represents a notification in the queue
 type QueueItem struct {
-	Notification interface{} // Can be Notification or OutputNotification
+	Notification interface{} // Notification, OutputNotification, *TimeoutNotification or *UpdateNotification
 	Priority     int
 	Timestamp    time.Time
 	RetryCount   int
 	MaxRetries   int
-	index        int // Used by heap interface
+	index        int    // Used by heap interface
+	group        string // Notification group, delivered in order
+	sequence     uint64 // Position of the item within its group
 }
 
 // NotificationQueue manages asynchronous notification delivery
@@ -31,6 +46,7 @@ type NotificationQueue struct {
 	mu      sync.Mutex
 	items   priorityQueue
 	ch      chan *QueueItem
+	ordered chan *QueueItem // Grouped notifications, delivered first in, first out
 	manager *Manager
 	ctx     context.Context //nolint:containedctx // Required for queue lifecycle management
 	cancel  context.CancelFunc
@@ -43,6 +59,10 @@ type NotificationQueue struct {
 	retryBackoff time.Duration
 	maxRetries   int
 
+	// Latest sequence number enqueued for each group with items in flight
+	sequences map[string]uint64
+	sequence  uint64
+
 	// Metrics
 	processed      uint64
 	failed         uint64
@@ -53,7 +73,7 @@ type NotificationQueue struct {
 // QueueOptions configures the notification queue
 type QueueOptions struct {
 	MaxQueueSize int           // Maximum items in queue (default: 1000)
-	Workers      int           // Number of worker goroutines (default: 2)
+	Workers      int           // Number of worker goroutines for ungrouped notifications (default: 2)
 	RateLimit    time.Duration // Minimum time between notifications (default: 100ms)
 	RetryBackoff time.Duration // Base backoff for retries (default: 1s)
 	MaxRetries   int           // Maximum retry attempts (default: 3)
@@ -93,6 +113,7 @@ func NewNotificationQueue(manager *Manager, opts QueueOptions) *NotificationQueu
 	q := &NotificationQueue{
 		items:        make(priorityQueue, 0),
 		ch:           make(chan *QueueItem, opts.MaxQueueSize),
+		ordered:      make(chan *QueueItem, opts.MaxQueueSize),
 		manager:      manager,
 		ctx:          ctx,
 		cancel:       cancel,
@@ -101,6 +122,7 @@ func NewNotificationQueue(manager *Manager, opts QueueOptions) *NotificationQueu
 		rateLimit:    opts.RateLimit,
 		retryBackoff: opts.RetryBackoff,
 		maxRetries:   opts.MaxRetries,
+		sequences:    make(map[string]uint64),
 	}
 
 	heap.Init(&q.items)
@@ -118,6 +140,11 @@ func (q *NotificationQueue) Start() {
 		q.wg.Add(1)
 		go q.worker(i)
 	}
+
+	// Notifications of a group replace each other, so a single worker
+	// delivers them in the order they were queued regardless of priority
+	q.wg.Add(1)
+	go q.orderedWorker()
 }
 
 // Stop gracefully shuts down the queue
@@ -152,18 +179,43 @@ func (q *NotificationQueue) EnqueueWithRetries(notification interface{}, priorit
 		Priority:     priority,
 		Timestamp:    time.Now(),
 		MaxRetries:   maxRetries,
+		group:        groupOf(notification),
 	}
 
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	// Check total queue size (heap + channel)
-	totalSize := len(q.items) + len(q.ch)
+	if err := q.push(item); err != nil {
+		return err
+	}
+	if item.group != "" {
+		q.sequence++
+		item.sequence = q.sequence
+		q.sequences[item.group] = item.sequence
+	}
+	return nil
+}
+
+// push adds an item to the heap or one of the channels; q.mu must be held
+func (q *NotificationQueue) push(item *QueueItem) error {
+	if q.ctx.Err() != nil {
+		q.dropped++
+		return fmt.Errorf("queue is stopped")
+	}
+
+	// Check total queue size (heap + channels)
+	totalSize := len(q.items) + len(q.ch) + len(q.ordered)
 	if totalSize >= q.maxQueueSize {
 		q.dropped++
 		return fmt.Errorf("queue is full (%d items)", q.maxQueueSize)
 	}
 
+	if item.group != "" {
+		// Never blocks: the channel holds as many items as the queue
+		q.ordered <- item
+		return nil
+	}
+
 	// Try to send directly to channel first
 	select {
 	case q.ch <- item:
@@ -193,6 +245,7 @@ func (q *NotificationQueue) dispatcher() {
 		case <-ticker.C:
 			q.mu.Lock()
 			// Move items from heap to channel
+		move:
 			for len(q.items) > 0 {
 				poppedItem := heap.Pop(&q.items)
 				item, ok := poppedItem.(*QueueItem)
@@ -204,9 +257,9 @@ func (q *NotificationQueue) dispatcher() {
 				case q.ch <- item:
 					// Successfully sent
 				default:
-					// Channel is full, push item back
+					// Channel is full, push item back and retry on the next tick
 					heap.Push(&q.items, item)
-					break
+					break move
 				}
 			}
 			q.mu.Unlock()
@@ -229,6 +282,21 @@ func (q *NotificationQueue) worker(_ int) {
 	}
 }
 
+// orderedWorker processes grouped notifications in the order they were queued
+func (q *NotificationQueue) orderedWorker() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+
+		case item := <-q.ordered:
+			q.processItem(item)
+		}
+	}
+}
+
 // processItem handles a single notification
 func (q *NotificationQueue) processItem(item *QueueItem) {
 	// Apply rate limiting
@@ -237,24 +305,70 @@ func (q *NotificationQueue) processItem(item *QueueItem) {
 	ctx, cancel := context.WithTimeout(q.ctx, 30*time.Second)
 	defer cancel()
 
-	var err error
+	if err := q.deliver(ctx, item); err != nil {
+		q.handleError(item, err)
+	} else {
+		q.mu.Lock()
+		q.processed++
+		q.finish(item)
+		q.mu.Unlock()
+	}
+}
 
-	// Process based on notification type
-	switch n := item.Notification.(type) {
+// groupOf returns the group of a queued notification, if any
+func groupOf(notification interface{}) string {
+	switch n := notification.(type) {
 	case Notification:
-		err = q.manager.Notify(ctx, n)
+		return n.Group
 	case OutputNotification:
-		err = q.manager.NotifyWithOutput(ctx, n)
+		return n.Group
+	case *TimeoutNotification:
+		return n.Group
 	default:
-		err = fmt.Errorf("unknown notification type: %T", n)
+		return ""
 	}
+}
 
-	if err != nil {
-		q.handleError(item, err)
-	} else {
-		q.mu.Lock()
-		q.processed++
-		q.mu.Unlock()
+// superseded reports whether a later notification of the item's group was
+// queued, in which case retrying the item would replace the newer one; q.mu
+// must be held
+func (q *NotificationQueue) superseded(item *QueueItem) bool {
+	return item.group != "" && q.sequences[item.group] != item.sequence
+}
+
+// finish forgets the item's group once its latest notification is done; q.mu
+// must be held
+func (q *NotificationQueue) finish(item *QueueItem) {
+	if item.group != "" && q.sequences[item.group] == item.sequence {
+		delete(q.sequences, item.group)
+	}
+}
+
+// SetManager replaces the manager that delivers notifications, e.g. after a
+// configuration reload. Queued notifications are delivered by the new manager.
+func (q *NotificationQueue) SetManager(manager *Manager) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.manager = manager
+}
+
+// deliver sends an item through the manager based on its notification type
+func (q *NotificationQueue) deliver(ctx context.Context, item *QueueItem) error {
+	q.mu.Lock()
+	manager := q.manager
+	q.mu.Unlock()
+
+	switch n := item.Notification.(type) {
+	case Notification:
+		return manager.Notify(ctx, n)
+	case OutputNotification:
+		return manager.NotifyWithOutput(ctx, n)
+	case *TimeoutNotification:
+		return manager.NotifyTimeout(ctx, n)
+	case *UpdateNotification:
+		return manager.NotifyUpdate(ctx, n)
+	default:
+		return fmt.Errorf("unknown notification type: %T", n)
 	}
 }
 
@@ -262,84 +376,135 @@ func (q *NotificationQueue) processItem(item *QueueItem) {
 func (q *NotificationQueue) handleError(item *QueueItem, _ error) {
 	item.RetryCount++
 
-	if item.RetryCount <= item.MaxRetries {
-		// Calculate backoff
-		backoff := q.retryBackoff * time.Duration(item.RetryCount)
+	q.mu.Lock()
+	defer q.mu.Unlock()
 
-		// Schedule retry
-		time.AfterFunc(backoff, func() {
-			if err := q.EnqueueWithRetries(item.Notification, item.Priority, item.MaxRetries-item.RetryCount); err != nil {
-				q.mu.Lock()
-				q.failed++
-				q.mu.Unlock()
-			}
-		})
-	} else {
-		q.mu.Lock()
+	if item.RetryCount > item.MaxRetries || q.superseded(item) {
 		q.failed++
-		q.mu.Unlock()
+		q.finish(item)
+		return
 	}
+
+	// Calculate backoff
+	backoff := q.retryBackoff * time.Duration(item.RetryCount)
+
+	// Schedule retry
+	time.AfterFunc(backoff, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		// A notification of the group queued during the backoff takes precedence
+		if q.superseded(item) {
+			q.failed++
+			return
+		}
+		item.Timestamp = time.Now()
+		if err := q.push(item); err != nil {
+			q.failed++
+			q.finish(item)
+		}
+	})
 }
 
-// applyRateLimit ensures minimum time between notifications
+// applyRateLimit ensures minimum time between notifications.
+// The delivery slot is reserved under the lock, but the wait happens outside
+// it so that Enqueue never blocks behind the rate limit.
 func (q *NotificationQueue) applyRateLimit() {
 	q.mu.Lock()
-	defer q.mu.Unlock()
-
+	now := time.Now()
+	var wait time.Duration
 	if !q.lastNotifyTime.IsZero() {
-		elapsed := time.Since(q.lastNotifyTime)
-		if elapsed < q.rateLimit {
-			time.Sleep(q.rateLimit - elapsed)
+		if next := q.lastNotifyTime.Add(q.rateLimit); next.After(now) {
+			wait = next.Sub(now)
+			now = next
 		}
 	}
+	q.lastNotifyTime = now
+	q.mu.Unlock()
 
-	q.lastNotifyTime = time.Now()
+	time.Sleep(wait)
 }
 
 // drainQueue processes all remaining items during shutdown
 func (q *NotificationQueue) drainQueue() {
+	// Collect pending items in priority order, then deliver without holding the lock
 	q.mu.Lock()
-	defer q.mu.Unlock()
+	pending := make([]*QueueItem, 0, len(q.items)+len(q.ch))
+	for len(q.items) > 0 {
+		if item, ok := heap.Pop(&q.items).(*QueueItem); ok {
+			pending = append(pending, item)
+		}
+	}
+	for drained := false; !drained; {
+		select {
+		case item := <-q.ch:
+			pending = append(pending, item)
+		default:
+			drained = true
+		}
+	}
+	for drained := false; !drained; {
+		select {
+		case item := <-q.ordered:
+			pending = append(pending, item)
+		default:
+			drained = true
+		}
+	}
+	q.mu.Unlock()
 
 	// Create a context with timeout for draining
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Process all items in heap
-	for len(q.items) > 0 {
-		poppedItem := heap.Pop(&q.items)
-		item, ok := poppedItem.(*QueueItem)
-		if !ok {
-			continue
-		}
-
-		switch n := item.Notification.(type) {
-		case Notification:
-			_ = q.manager.Notify(ctx, n)
-		case OutputNotification:
-			_ = q.manager.NotifyWithOutput(ctx, n)
-		}
+	for _, item := range pending {
+		err := q.deliver(ctx, item)
 
-		q.processed++
-	}
-
-	// Process all items in channel
-	for {
-		select {
-		case item := <-q.ch:
-			switch n := item.Notification.(type) {
-			case Notification:
-				_ = q.manager.Notify(ctx, n)
-			case OutputNotification:
-				_ = q.manager.NotifyWithOutput(ctx, n)
-			}
+		q.mu.Lock()
+		if err != nil {
+			q.failed++
+		} else {
 			q.processed++
-		default:
-			return
 		}
+		q.mu.Unlock()
 	}
 }
 
+// Notify queues a notification with the priority of its level
+func (q *NotificationQueue) Notify(_ context.Context, notification Notification) error {
+	return q.Enqueue(notification, PriorityForLevel(notification.Level))
+}
+
+// Info queues an info notification
+func (q *NotificationQueue) Info(ctx context.Context, title, message string) error {
+	return q.Notify(ctx, Notification{Title: title, Message: message, Level: LevelInfo})
+}
+
+// Warning queues a warning notification
+func (q *NotificationQueue) Warning(ctx context.Context, title, message string) error {
+	return q.Notify(ctx, Notification{Title: title, Message: message, Level: LevelWarning})
+}
+
+// Error queues an error notification
+func (q *NotificationQueue) Error(ctx context.Context, title, message string) error {
+	return q.Notify(ctx, Notification{Title: title, Message: message, Level: LevelError})
+}
+
+// Success queues a success notification
+func (q *NotificationQueue) Success(ctx context.Context, title, message string) error {
+	return q.Notify(ctx, Notification{Title: title, Message: message, Level: LevelSuccess})
+}
+
+// NotifyTimeout queues a timeout notification
+func (q *NotificationQueue) NotifyTimeout(_ context.Context, notification *TimeoutNotification) error {
+	return q.Enqueue(notification, PriorityHigh)
+}
+
+// NotifyUpdate queues an update notification
+func (q *NotificationQueue) NotifyUpdate(_ context.Context, notification *UpdateNotification) error {
+	return q.Enqueue(notification, PriorityLow)
+}
+
 // GetMetrics returns queue metrics
 func (q *NotificationQueue) GetMetrics() (processed, failed, dropped uint64) {
 	q.mu.Lock()
@@ -351,7 +516,7 @@ func (q *NotificationQueue) GetMetrics() (processed, failed, dropped uint64) {
 func (q *NotificationQueue) GetQueueSize() int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	return len(q.items) + len(q.ch)
+	return len(q.items) + len(q.ch) + len(q.ordered)
 }
 
 // Priority queue implementation
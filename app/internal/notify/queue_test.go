@@ -4,6 +4,8 @@ import (
 	"container/heap"
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -590,3 +592,120 @@ func TestPriorityQueue_DirectMethods(t *testing.T) {
 		t.Errorf("Pop: expected %s, got %s", title0Before, poppedTitle)
 	}
 }
+
+func TestNotificationQueue_Sender(t *testing.T) {
+	mock := NewMockNotifier(true)
+	manager := &Manager{notifiers: []Notifier{mock}, options: NotificationOptions{Timeouts: true}}
+
+	queue := NewNotificationQueue(manager, QueueOptions{RateLimit: time.Millisecond})
+	queue.Start()
+
+	var sender Sender = queue
+	ctx := context.Background()
+	if err := sender.Info(ctx, "Spell Cast", "e → editor"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if err := sender.NotifyTimeout(ctx, &TimeoutNotification{ActionName: "build", TimeoutDuration: 5}); err != nil {
+		t.Fatalf("NotifyTimeout() error = %v", err)
+	}
+	if err := queue.NotifyUpdate(ctx, &UpdateNotification{CurrentVersion: "1.0.0", NewVersion: "1.1.0"}); err != nil {
+		t.Fatalf("NotifyUpdate() error = %v", err)
+	}
+
+	// Stop drains anything the workers have not delivered yet
+	if err := queue.Stop(5 * time.Second); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	processed, failed, _ := queue.GetMetrics()
+	if processed != 3 || failed != 0 {
+		t.Errorf("GetMetrics() processed=%d failed=%d, want 3 and 0", processed, failed)
+	}
+	if got := len(mock.GetNotifications()); got != 3 {
+		t.Errorf("delivered %d notifications, want 3", got)
+	}
+
+	if err := sender.Error(ctx, "late", "after stop"); err == nil {
+		t.Error("Error() expected failure after Stop")
+	}
+}
+
+func TestPriorityForLevel(t *testing.T) {
+	if PriorityForLevel(LevelError) <= PriorityForLevel(LevelSuccess) ||
+		PriorityForLevel(LevelSuccess) <= PriorityForLevel(LevelInfo) {
+		t.Error("errors should outrank success, which should outrank info")
+	}
+}
+
+func TestQueueStatus_RoundTrip(t *testing.T) {
+	queue := NewNotificationQueue(&Manager{}, DefaultQueueOptions())
+	_ = queue.Enqueue(Notification{Title: "pending"}, PriorityNormal)
+
+	path := filepath.Join(t.TempDir(), QueueStatusFile)
+	if err := WriteQueueStatus(path, queue.Status()); err != nil {
+		t.Fatalf("WriteQueueStatus() error = %v", err)
+	}
+
+	status, err := ReadQueueStatus(path)
+	if err != nil {
+		t.Fatalf("ReadQueueStatus() error = %v", err)
+	}
+	if !status.Running || status.Pending != 1 || status.PID != os.Getpid() {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestNotificationQueue_DeliversGroupsInOrder(t *testing.T) {
+	recorder := &replaceRecorder{}
+	manager := &Manager{notifiers: []Notifier{recorder}}
+	manager.SetCoalescer(NewCoalescer(0))
+
+	opts := DefaultQueueOptions()
+	opts.RateLimit = time.Millisecond
+	queue := NewNotificationQueue(manager, opts)
+	queue.Start()
+	defer func() {
+		if err := queue.Stop(5 * time.Second); err != nil {
+			t.Errorf("Failed to stop queue: %v", err)
+		}
+	}()
+
+	// The error has the higher priority but was queued last, so it must stay shown
+	const groups = 20
+	ctx := context.Background()
+	for i := 0; i < groups; i++ {
+		group := NewGroup("build")
+		if err := queue.Notify(ctx, Notification{Title: "Spell Cast", Level: LevelInfo, Group: group}); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+		if err := queue.Notify(ctx, Notification{Title: "Spell Failed", Level: LevelError, Group: group}); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for processed, _, _ := queue.GetMetrics(); processed < 2*groups && time.Now().Before(deadline); processed, _, _ = queue.GetMetrics() {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Replay the calls to find what each notification shows in the end
+	titles, replaceIDs := recorder.calls()
+	shown := make(map[uint32]string)
+	var nextID uint32
+	for i, title := range titles {
+		id := replaceIDs[i]
+		if id == 0 {
+			nextID++
+			id = nextID
+		}
+		shown[id] = title
+	}
+	if len(shown) != groups {
+		t.Fatalf("shown notifications = %d, want %d", len(shown), groups)
+	}
+	for id, title := range shown {
+		if title != "Spell Failed" {
+			t.Errorf("notification %d shows %q, want Spell Failed", id, title)
+		}
+	}
+}
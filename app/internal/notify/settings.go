@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// OptionsFromConfig builds notification options from the notification section
+// of the configuration and the per-spell overrides in the grimoire
+func OptionsFromConfig(cfg *config.Config) NotificationOptions {
+	options := NotificationOptions{
+		MaxOutputLength: cfg.Notification.MaxOutputLength,
+		FormatAsCode:    true,
+		Sound:           cfg.Notification.SoundEnabled(),
+		Levels:          levelMap(cfg.Notification.Levels),
+		SpellLevels:     make(map[string]map[Level]bool),
+		Timeouts:        cfg.Notification.TimeoutEnabled(),
+		TimeoutWarnings: cfg.Notification.WarningEnabled(),
+	}
+	if options.MaxOutputLength <= 0 {
+		options.MaxOutputLength = 1024
+	}
+
+	for name, action := range cfg.Actions {
+		if !action.Notification.IsZero() {
+			options.SpellLevels[name] = levelMap(action.Notification)
+		}
+	}
+	return options
+}
+
+// NewManagerFromConfig creates a notification manager configured from cfg.
+// Webhook URLs and headers are expanded with the env files of scope.
+func NewManagerFromConfig(cfg *config.Config, scope *dotenv.Scope) *Manager {
+	manager := NewManager()
+	var vars *dotenv.Vars
+	if len(cfg.Notification.Webhooks) > 0 {
+		var err error
+		if vars, err = scope.Resolve(nil); err != nil {
+			logger.Warn("Webhooks use the process environment only: %v", err)
+		}
+	}
+	for _, webhook := range cfg.Notification.Webhooks {
+		notifier, err := NewWebhookNotifier(webhook, vars)
+		if err != nil {
+			logger.Warn("Skipping webhook %s: %v", webhook.Name, err)
+			continue
+		}
+		manager.AddNotifier(notifier)
+	}
+	if cfg.Notification.File.Path != "" {
+		notifier, err := NewFileNotifier(cfg.Notification.File)
+		if err != nil {
+			logger.Warn("Skipping notification file: %v", err)
+		} else {
+			manager.AddNotifier(notifier)
+		}
+	}
+	manager.SetOptions(OptionsFromConfig(cfg))
+	return manager
+}
+
+// levelMap converts level toggles into a map containing only the toggles that are set
+func levelMap(levels config.NotificationLevels) map[Level]bool {
+	result := make(map[Level]bool)
+	toggles := map[Level]*bool{
+		LevelInfo:    levels.Info,
+		LevelSuccess: levels.Success,
+		LevelWarning: levels.Warning,
+		LevelError:   levels.Error,
+	}
+	for level, enabled := range toggles {
+		if enabled != nil {
+			result[level] = *enabled
+		}
+	}
+	return result
+}
+
+// ForSpell returns a sender that tags every notification with the spell that
+// raised it, so that the spell's notification overrides apply. Notifications
+// sent with a context from WithGroup are also tagged with that group.
+func ForSpell(sender Sender, spell string) Sender {
+	return &spellSender{sender: sender, spell: spell}
+}
+
+// spellSender forwards notifications tagged with a spell name
+type spellSender struct {
+	sender Sender
+	spell  string
+}
+
+// Notify forwards a notification tagged with the spell
+func (s *spellSender) Notify(ctx context.Context, notification Notification) error {
+	notification.Spell = s.spell
+	if notification.Group == "" {
+		notification.Group = GroupFromContext(ctx)
+	}
+	return s.sender.Notify(ctx, notification)
+}
+
+// Info forwards an info notification
+func (s *spellSender) Info(ctx context.Context, title, message string) error {
+	return s.Notify(ctx, Notification{Title: title, Message: message, Level: LevelInfo})
+}
+
+// Warning forwards a warning notification
+func (s *spellSender) Warning(ctx context.Context, title, message string) error {
+	return s.Notify(ctx, Notification{Title: title, Message: message, Level: LevelWarning})
+}
+
+// Error forwards an error notification
+func (s *spellSender) Error(ctx context.Context, title, message string) error {
+	return s.Notify(ctx, Notification{Title: title, Message: message, Level: LevelError})
+}
+
+// Success forwards a success notification
+func (s *spellSender) Success(ctx context.Context, title, message string) error {
+	return s.Notify(ctx, Notification{Title: title, Message: message, Level: LevelSuccess})
+}
+
+// NotifyTimeout forwards a timeout notification tagged with the spell
+func (s *spellSender) NotifyTimeout(ctx context.Context, notification *TimeoutNotification) error {
+	notification.Spell = s.spell
+	if notification.Group == "" {
+		notification.Group = GroupFromContext(ctx)
+	}
+	return s.sender.NotifyTimeout(ctx, notification)
+}
@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestOptionsFromConfig(t *testing.T) {
+	cfg := &config.Config{
+		Notification: config.NotificationConfig{
+			EnableWarning: boolPtr(false),
+			Sound:         boolPtr(false),
+			Levels:        config.NotificationLevels{Info: boolPtr(false)},
+		},
+		Actions: map[string]config.ActionConfig{
+			"deploy": {Type: "script", Command: "deploy.sh", Notification: config.NotificationLevels{Info: boolPtr(true), Error: boolPtr(false)}},
+			"editor": {Type: "app", Command: "code"},
+		},
+	}
+
+	options := OptionsFromConfig(cfg)
+	if options.MaxOutputLength != 1024 || options.Sound || !options.Timeouts || options.TimeoutWarnings {
+		t.Errorf("unexpected options: %+v", options)
+	}
+	if _, ok := options.SpellLevels["editor"]; ok {
+		t.Error("actions without overrides should not be listed")
+	}
+
+	tests := []struct {
+		level Level
+		spell string
+		want  bool
+	}{
+		{LevelInfo, "", false},
+		{LevelInfo, "editor", false},
+		{LevelError, "editor", true},
+		{LevelInfo, "deploy", true},
+		{LevelError, "deploy", false},
+		{LevelSuccess, "deploy", true},
+	}
+	for _, tt := range tests {
+		if got := options.Allows(tt.level, tt.spell); got != tt.want {
+			t.Errorf("Allows(%s, %q) = %v, want %v", tt.level, tt.spell, got, tt.want)
+		}
+	}
+}
+
+func TestManager_LevelFiltering(t *testing.T) {
+	mock := NewMockNotifier(true)
+	manager := &Manager{notifiers: []Notifier{mock}}
+	manager.SetOptions(NotificationOptions{
+		Levels:      map[Level]bool{LevelInfo: false},
+		SpellLevels: map[string]map[Level]bool{"deploy": {LevelInfo: true}},
+	})
+
+	ctx := context.Background()
+	_ = manager.Info(ctx, "Spell Cast", "suppressed")
+	_ = manager.Error(ctx, "Spell Failed", "shown")
+	_ = ForSpell(manager, "deploy").Info(ctx, "Spell Cast", "shown for deploy")
+	_ = manager.NotifyTimeout(ctx, &TimeoutNotification{ActionName: "build", TimeoutDuration: 5})
+	_ = manager.NotifyTimeout(ctx, &TimeoutNotification{ActionName: "build", TimeoutDuration: 5, Warning: true})
+
+	got := mock.GetNotifications()
+	if len(got) != 2 {
+		t.Fatalf("delivered %d notifications, want 2: %+v", len(got), got)
+	}
+	if got[1].Spell != "deploy" {
+		t.Errorf("Spell = %q, want deploy", got[1].Spell)
+	}
+}
+
+func TestManager_TimeoutWarnings(t *testing.T) {
+	mock := NewMockNotifier(true)
+	manager := &Manager{notifiers: []Notifier{mock}}
+	manager.SetOptions(NotificationOptions{Timeouts: true, TimeoutWarnings: true})
+
+	err := ForSpell(manager, "build").NotifyTimeout(context.Background(), &TimeoutNotification{
+		ActionName:      "build",
+		TimeoutDuration: 60,
+		ElapsedTime:     50,
+		Warning:         true,
+	})
+	if err != nil {
+		t.Fatalf("NotifyTimeout() error = %v", err)
+	}
+
+	got := mock.GetNotifications()
+	if len(got) != 1 || got[0].Level != LevelWarning || got[0].Spell != "build" {
+		t.Fatalf("unexpected notifications: %+v", got)
+	}
+	if got[0].Message != "Script will be stopped in 10 seconds (timeout: 60 seconds)" {
+		t.Errorf("Message = %q", got[0].Message)
+	}
+}
+
+func TestNotificationQueue_SetManager(t *testing.T) {
+	first := NewMockNotifier(true)
+	second := NewMockNotifier(true)
+
+	queue := NewNotificationQueue(&Manager{notifiers: []Notifier{first}}, DefaultQueueOptions())
+	_ = queue.Enqueue(Notification{Title: "queued before reload"}, PriorityNormal)
+	queue.SetManager(&Manager{notifiers: []Notifier{second}})
+
+	queue.drainQueue()
+	if len(first.GetNotifications()) != 0 || len(second.GetNotifications()) != 1 {
+		t.Errorf("queued notification should be delivered by the new manager")
+	}
+}
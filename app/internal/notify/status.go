@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// QueueStatusFile is the file name the daemon writes queue metrics to
+const QueueStatusFile = "notification-status.json"
+
+// QueueStatus is a snapshot of notification queue metrics.
+// The daemon writes it periodically so that a separate process can report on it.
+type QueueStatus struct {
+	PID       int       `json:"pid"`
+	Running   bool      `json:"running"` // false once the queue has been drained on shutdown
+	Processed uint64    `json:"processed"`
+	Failed    uint64    `json:"failed"`
+	Dropped   uint64    `json:"dropped"`
+	Pending   int       `json:"pending"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Status returns a snapshot of the queue metrics
+func (q *NotificationQueue) Status() QueueStatus {
+	processed, failed, dropped := q.GetMetrics()
+	return QueueStatus{
+		PID:       os.Getpid(),
+		Running:   q.ctx.Err() == nil,
+		Processed: processed,
+		Failed:    failed,
+		Dropped:   dropped,
+		Pending:   q.GetQueueSize(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// WriteQueueStatus saves a status snapshot as JSON, replacing the file atomically
+func WriteQueueStatus(path string, status QueueStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode queue status: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write queue status: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write queue status: %w", err)
+	}
+	return nil
+}
+
+// ReadQueueStatus loads a status snapshot written by WriteQueueStatus
+func ReadQueueStatus(path string) (*QueueStatus, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var status QueueStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse queue status: %w", err)
+	}
+	return &status, nil
+}
+
+// ReportStatus writes the queue status to dir/QueueStatusFile every interval
+// until ctx is cancelled. Write errors are passed to onError, which may be nil.
+func (q *NotificationQueue) ReportStatus(ctx context.Context, dir string, interval time.Duration, onError func(error)) {
+	path := filepath.Join(dir, QueueStatusFile)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := WriteQueueStatus(path, q.Status()); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -8,9 +8,15 @@ import (
 	"github.com/SphereStacking/silentcast/internal/updater"
 )
 
+// UpdateSender delivers update notifications; it is implemented by Manager and NotificationQueue
+type UpdateSender interface {
+	Notify(ctx context.Context, notification Notification) error
+	NotifyUpdate(ctx context.Context, notification *UpdateNotification) error
+}
+
 // UpdateNotificationManager handles update-related notifications
 type UpdateNotificationManager struct {
-	notifier *Manager
+	notifier UpdateSender
 	config   UpdateNotificationConfig
 }
 
@@ -43,7 +49,7 @@ func DefaultUpdateNotificationConfig() UpdateNotificationConfig {
 }
 
 // NewUpdateNotificationManager creates a new update notification manager
-func NewUpdateNotificationManager(notifier *Manager) *UpdateNotificationManager {
+func NewUpdateNotificationManager(notifier UpdateSender) *UpdateNotificationManager {
 	return &UpdateNotificationManager{
 		notifier: notifier,
 		config:   DefaultUpdateNotificationConfig(),
@@ -0,0 +1,348 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/internal/errors"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// defaultWebhookTimeout bounds a webhook request when no timeout is configured
+const defaultWebhookTimeout = 10 * time.Second
+
+// defaultWebhookMessage is the message template used when none is configured
+const defaultWebhookMessage = `{{.Message}}{{if .Output}}
+
+Output:
+{{.Output}}{{end}}`
+
+// WebhookData is the data available to webhook title and message templates
+type WebhookData struct {
+	Title          string
+	Message        string
+	Level          string
+	Spell          string
+	Output         string
+	ExitCode       int // -1 when the notification has no exit code
+	TruncatedBytes int
+	Host           string
+	Time           time.Time
+}
+
+// WebhookNotifier posts notifications to an HTTP endpoint: a generic JSON
+// webhook, a Slack incoming webhook, or an ntfy topic
+type WebhookNotifier struct {
+	name            string
+	kind            string
+	url             string
+	headers         map[string]string
+	levels          map[Level]bool // nil sends every level
+	title           *template.Template
+	message         *template.Template
+	client          *http.Client
+	maxOutputLength int
+	now             func() time.Time
+}
+
+// NewWebhookNotifier creates a notifier from a webhooks entry in the
+// configuration. References in the URL and headers are expanded with vars,
+// so they may come from env files or the secrets file; nil uses the process
+// environment only.
+func NewWebhookNotifier(cfg config.WebhookConfig, vars *dotenv.Vars) (*WebhookNotifier, error) {
+	kind := cfg.Type
+	if kind == "" {
+		kind = config.WebhookTypeGeneric
+	}
+	switch kind {
+	case config.WebhookTypeGeneric, config.WebhookTypeSlack, config.WebhookTypeNtfy:
+	default:
+		return nil, errors.New(errors.ErrorTypeConfig, "invalid webhook type").
+			WithContext("type", cfg.Type)
+	}
+
+	// URLs and headers often hold tokens, so they may come from the environment
+	if vars == nil {
+		// Without env files a scope resolves nothing but the process environment
+		vars, _ = dotenv.NewScope("").Resolve(nil)
+	}
+	rawURL, err := vars.Expand(cfg.URL)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrorTypeConfig, "failed to expand webhook URL", err).
+			WithContext("url", cfg.URL)
+	}
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || endpoint.Host == "" {
+		return nil, errors.New(errors.ErrorTypeConfig, "invalid webhook URL").
+			WithContext("url", cfg.URL)
+	}
+	headers := make(map[string]string, len(cfg.Headers))
+	for key, value := range cfg.Headers {
+		if headers[key], err = vars.Expand(value); err != nil {
+			return nil, errors.Wrap(errors.ErrorTypeConfig, "failed to expand webhook header", err).
+				WithContext("header", key)
+		}
+	}
+
+	title, err := template.New("title").Parse(orDefault(cfg.Title, "{{.Title}}"))
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrorTypeConfig, "invalid webhook title template", err)
+	}
+	message, err := template.New("message").Parse(orDefault(cfg.Message, defaultWebhookMessage))
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrorTypeConfig, "invalid webhook message template", err)
+	}
+
+	var levels map[Level]bool
+	if len(cfg.Levels) > 0 {
+		levels = make(map[Level]bool, len(cfg.Levels))
+		for _, name := range cfg.Levels {
+			if level, ok := parseLevel(name); ok {
+				levels[level] = true
+			}
+		}
+	}
+
+	timeout := defaultWebhookTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	return &WebhookNotifier{
+		name:            orDefault(cfg.Name, endpoint.Host),
+		kind:            kind,
+		url:             rawURL,
+		headers:         headers,
+		levels:          levels,
+		title:           title,
+		message:         message,
+		client:          &http.Client{Timeout: timeout},
+		maxOutputLength: 1024,
+		now:             time.Now,
+	}, nil
+}
+
+// Name returns the name shown in logs
+func (w *WebhookNotifier) Name() string {
+	return w.name
+}
+
+// Notify posts a notification
+func (w *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	return w.ShowWithOutput(ctx, OutputNotification{Notification: notification, ExitCode: -1})
+}
+
+// ShowWithOutput posts a notification with command output
+func (w *WebhookNotifier) ShowWithOutput(ctx context.Context, notification OutputNotification) error {
+	if w.levels != nil && !w.levels[notification.Level] {
+		return nil
+	}
+
+	output := notification.Output
+	truncated := notification.TruncatedBytes
+	if len(output) > w.maxOutputLength {
+		cut := truncateOutput(output, w.maxOutputLength)
+		truncated += len(output) - len(cut)
+		output = cut
+	}
+
+	host, _ := os.Hostname()
+	data := WebhookData{
+		Title:          notification.Title,
+		Message:        notification.Message,
+		Level:          strings.ToLower(notification.Level.String()),
+		Spell:          notification.Spell,
+		Output:         output,
+		ExitCode:       notification.ExitCode,
+		TruncatedBytes: truncated,
+		Host:           host,
+		Time:           w.now(),
+	}
+
+	err := w.post(ctx, data)
+	if err != nil {
+		logger.Warn("Webhook %s failed: %v", w.name, err)
+	}
+	return err
+}
+
+// SetMaxOutputLength sets the maximum output length sent
+func (w *WebhookNotifier) SetMaxOutputLength(maxLength int) int {
+	if maxLength > 0 {
+		w.maxOutputLength = maxLength
+	}
+	return w.maxOutputLength
+}
+
+// SupportsRichContent reports whether the endpoint renders formatting
+func (w *WebhookNotifier) SupportsRichContent() bool {
+	return w.kind == config.WebhookTypeSlack
+}
+
+// IsAvailable always returns true; delivery errors are reported per notification
+func (w *WebhookNotifier) IsAvailable() bool {
+	return true
+}
+
+// post renders the templates and sends the request
+func (w *WebhookNotifier) post(ctx context.Context, data WebhookData) error {
+	title, err := renderWebhookTemplate(w.title, data)
+	if err != nil {
+		return err
+	}
+	message, err := renderWebhookTemplate(w.message, data)
+	if err != nil {
+		return err
+	}
+
+	endpoint := w.url
+	var payload interface{}
+	switch w.kind {
+	case config.WebhookTypeSlack:
+		text := message
+		if title != "" {
+			text = "*" + title + "*\n" + message
+		}
+		payload = map[string]string{"text": strings.TrimRight(text, "\n")}
+	case config.WebhookTypeNtfy:
+		// Publish as JSON to the server root so titles may contain any characters
+		server, topic := splitNtfyURL(w.url)
+		endpoint = server
+		payload = map[string]interface{}{
+			"topic":    topic,
+			"title":    title,
+			"message":  message,
+			"priority": ntfyPriority(data.Level),
+			"tags":     []string{ntfyTag(data.Level)},
+		}
+	default:
+		fields := map[string]interface{}{
+			"title":   title,
+			"message": message,
+			"level":   data.Level,
+			"host":    data.Host,
+			"time":    data.Time,
+		}
+		if data.Spell != "" {
+			fields["spell"] = data.Spell
+		}
+		if data.Output != "" {
+			fields["output"] = data.Output
+			fields["truncated_bytes"] = data.TruncatedBytes
+		}
+		if data.ExitCode >= 0 {
+			fields["exit_code"] = data.ExitCode
+		}
+		payload = fields
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeSystem, "failed to encode webhook payload", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(errors.ErrorTypeNetwork, "failed to create webhook request", err).
+			WithContext("webhook", w.name)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SilentCast")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		// The URL may contain a token, so only the cause is reported
+		var urlErr *url.Error
+		if stderrors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return errors.Wrap(errors.ErrorTypeNetwork, "webhook request failed", err).
+			WithContext("webhook", w.name)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New(errors.ErrorTypeNetwork, fmt.Sprintf("webhook returned %s", resp.Status)).
+			WithContext("webhook", w.name).
+			WithContext("status_code", resp.StatusCode)
+	}
+	return nil
+}
+
+// renderWebhookTemplate executes a template with the notification data
+func renderWebhookTemplate(tmpl *template.Template, data WebhookData) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", errors.Wrap(errors.ErrorTypeConfig, "failed to render webhook template", err).
+			WithContext("template", tmpl.Name())
+	}
+	return sb.String(), nil
+}
+
+// splitNtfyURL splits a topic URL such as https://ntfy.sh/builds into the
+// server URL and the topic
+func splitNtfyURL(topicURL string) (server, topic string) {
+	u, err := url.Parse(topicURL)
+	if err != nil {
+		return topicURL, ""
+	}
+	path := strings.Trim(u.Path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		topic = path[i+1:]
+		u.Path = "/" + path[:i+1]
+	} else {
+		topic = path
+		u.Path = "/"
+	}
+	return u.String(), topic
+}
+
+// ntfyPriority maps a level name to an ntfy priority (1-5)
+func ntfyPriority(level string) int {
+	switch level {
+	case "error":
+		return 4
+	case "info":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// ntfyTag maps a level name to an ntfy tag, which ntfy shows as an emoji
+func ntfyTag(level string) string {
+	switch level {
+	case "error":
+		return "rotating_light"
+	case "warning":
+		return "warning"
+	case "success":
+		return "white_check_mark"
+	default:
+		return "information_source"
+	}
+}
+
+// orDefault returns value, or fallback if value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
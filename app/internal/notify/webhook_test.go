@@ -0,0 +1,297 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+)
+
+// webhookRecorder is an HTTP server that records posted JSON bodies
+type webhookRecorder struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	paths    []string
+	headers  []http.Header
+	payloads []map[string]interface{}
+	status   int
+}
+
+func newWebhookRecorder(t *testing.T) *webhookRecorder {
+	t.Helper()
+	r := &webhookRecorder{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid JSON body: %v", err)
+		}
+		r.mu.Lock()
+		r.paths = append(r.paths, req.URL.Path)
+		r.headers = append(r.headers, req.Header.Clone())
+		r.payloads = append(r.payloads, payload)
+		status := r.status
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.payloads)
+}
+
+func (r *webhookRecorder) last(t *testing.T) (string, http.Header, map[string]interface{}) {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.payloads) == 0 {
+		t.Fatal("Expected a webhook request")
+	}
+	i := len(r.payloads) - 1
+	return r.paths[i], r.headers[i], r.payloads[i]
+}
+
+func TestWebhookNotifier_Generic(t *testing.T) {
+	server := newWebhookRecorder(t)
+	t.Setenv("SILENTCAST_TEST_TOKEN", "secret")
+	notifier, err := NewWebhookNotifier(config.WebhookConfig{
+		URL:     server.URL + "/hook",
+		Headers: map[string]string{"Authorization": "Bearer ${SILENTCAST_TEST_TOKEN}"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	err = notifier.ShowWithOutput(context.Background(), OutputNotification{
+		Notification: Notification{Title: "Spell Failed", Message: "build exited", Level: LevelError, Spell: "build"},
+		Output:       "undefined: foo",
+		ExitCode:     2,
+	})
+	if err != nil {
+		t.Fatalf("ShowWithOutput() error = %v", err)
+	}
+
+	path, header, payload := server.last(t)
+	if path != "/hook" {
+		t.Errorf("path = %q, want /hook", path)
+	}
+	if header.Get("Authorization") != "Bearer secret" || header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected headers: %v", header)
+	}
+	if payload["title"] != "Spell Failed" || payload["level"] != "error" || payload["spell"] != "build" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+	if payload["output"] != "undefined: foo" || payload["exit_code"] != float64(2) {
+		t.Errorf("output = %v, exit_code = %v", payload["output"], payload["exit_code"])
+	}
+	if message, _ := payload["message"].(string); !strings.Contains(message, "build exited") || !strings.Contains(message, "undefined: foo") {
+		t.Errorf("default message template should include message and output, got %q", message)
+	}
+
+	// Plain notifications carry no exit code
+	if err := notifier.Notify(context.Background(), Notification{Title: "Spell Cast", Level: LevelInfo}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if _, _, payload := server.last(t); payload["exit_code"] != nil {
+		t.Errorf("exit_code = %v, want omitted", payload["exit_code"])
+	}
+}
+
+func TestWebhookNotifier_Slack(t *testing.T) {
+	server := newWebhookRecorder(t)
+	notifier, err := NewWebhookNotifier(config.WebhookConfig{
+		Type:    config.WebhookTypeSlack,
+		URL:     server.URL + "/services/T/B/X",
+		Title:   "{{.Spell}} on {{.Host}}",
+		Message: "{{.Message}} ({{.Level}})",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	err = notifier.Notify(context.Background(), Notification{Title: "Spell Succeeded", Message: "done", Level: LevelSuccess, Spell: "build"})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	_, _, payload := server.last(t)
+	text, _ := payload["text"].(string)
+	if !strings.HasPrefix(text, "*build on ") || !strings.HasSuffix(text, "*\ndone (success)") {
+		t.Errorf("text = %q", text)
+	}
+	if len(payload) != 1 {
+		t.Errorf("Slack payload should only contain text, got %v", payload)
+	}
+}
+
+func TestWebhookNotifier_Ntfy(t *testing.T) {
+	server := newWebhookRecorder(t)
+	notifier, err := NewWebhookNotifier(config.WebhookConfig{
+		Type: config.WebhookTypeNtfy,
+		URL:  server.URL + "/builds",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	if err := notifier.Notify(context.Background(), Notification{Title: "🚀 Spell Failed", Message: "exit 1", Level: LevelError}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	path, _, payload := server.last(t)
+	if path != "/" {
+		t.Errorf("path = %q, want the server root", path)
+	}
+	if payload["topic"] != "builds" || payload["title"] != "🚀 Spell Failed" || payload["message"] != "exit 1" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+	if payload["priority"] != float64(4) {
+		t.Errorf("priority = %v, want 4", payload["priority"])
+	}
+}
+
+func TestWebhookNotifier_Levels(t *testing.T) {
+	server := newWebhookRecorder(t)
+	notifier, err := NewWebhookNotifier(config.WebhookConfig{
+		URL:    server.URL,
+		Levels: []string{"error", "Success"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for _, level := range []Level{LevelInfo, LevelWarning, LevelError, LevelSuccess} {
+		if err := notifier.Notify(ctx, Notification{Title: "n", Level: level}); err != nil {
+			t.Fatalf("Notify(%v) error = %v", level, err)
+		}
+	}
+	if got := server.count(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestWebhookNotifier_Errors(t *testing.T) {
+	server := newWebhookRecorder(t)
+	server.status = http.StatusForbidden
+
+	notifier, err := NewWebhookNotifier(config.WebhookConfig{URL: server.URL}, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+	if err := notifier.Notify(context.Background(), Notification{Title: "n"}); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Notify() error = %v, want 403 error", err)
+	}
+
+	if _, err := NewWebhookNotifier(config.WebhookConfig{Type: "discord", URL: server.URL}, nil); err == nil {
+		t.Error("Expected error for unknown type")
+	}
+	if _, err := NewWebhookNotifier(config.WebhookConfig{URL: server.URL, Title: "{{.Title"}, nil); err == nil {
+		t.Error("Expected error for invalid template")
+	}
+	notifier, err = NewWebhookNotifier(config.WebhookConfig{URL: server.URL, Title: "{{.Missing}}"}, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+	if err := notifier.Notify(context.Background(), Notification{Title: "n"}); err == nil {
+		t.Error("Expected error for unknown template field")
+	}
+}
+
+func TestSplitNtfyURL(t *testing.T) {
+	tests := []struct {
+		url, server, topic string
+	}{
+		{"https://ntfy.sh/builds", "https://ntfy.sh/", "builds"},
+		{"https://ntfy.sh/builds/", "https://ntfy.sh/", "builds"},
+		{"https://example.com/ntfy/alerts", "https://example.com/ntfy/", "alerts"},
+	}
+	for _, tt := range tests {
+		server, topic := splitNtfyURL(tt.url)
+		if server != tt.server || topic != tt.topic {
+			t.Errorf("splitNtfyURL(%q) = %q, %q, want %q, %q", tt.url, server, topic, tt.server, tt.topic)
+		}
+	}
+}
+
+func TestNewManagerFromConfig_Webhooks(t *testing.T) {
+	server := newWebhookRecorder(t)
+	cfg := &config.Config{}
+	cfg.Notification.Webhooks = []config.WebhookConfig{{URL: server.URL}}
+
+	manager := NewManagerFromConfig(cfg, nil)
+	if err := manager.Success(context.Background(), "Spell Succeeded", "build"); err != nil {
+		t.Fatalf("Success() error = %v", err)
+	}
+	if server.count() != 1 {
+		t.Errorf("requests = %d, want 1", server.count())
+	}
+}
+
+func TestNewManagerFromConfig_WebhookEnvFiles(t *testing.T) {
+	server := newWebhookRecorder(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("HOOK_PATH=alerts\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secrets.env"), []byte("HOOK_TOKEN=s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	scope := dotenv.NewScope(dir)
+	scope.Configure([]string{".env"}, "secrets.env")
+
+	cfg := &config.Config{}
+	cfg.Notification.Webhooks = []config.WebhookConfig{{
+		URL: server.URL + "/${HOOK_PATH}",
+		Headers: map[string]string{
+			"Authorization": "Bearer ${secret:HOOK_TOKEN}",
+			"X-Channel":     "${SILENTCAST_TEST_UNSET:-builds}",
+		},
+	}}
+
+	manager := NewManagerFromConfig(cfg, scope)
+	if err := manager.Success(context.Background(), "Spell Succeeded", "build"); err != nil {
+		t.Fatalf("Success() error = %v", err)
+	}
+	path, header, _ := server.last(t)
+	if path != "/alerts" {
+		t.Errorf("path = %q, want /alerts", path)
+	}
+	if header.Get("Authorization") != "Bearer s3cret" || header.Get("X-Channel") != "builds" {
+		t.Errorf("unexpected headers: %v", header)
+	}
+}
+
+func TestWebhookNotifier_CutsOutputOnRuneBoundary(t *testing.T) {
+	server := newWebhookRecorder(t)
+	notifier, err := NewWebhookNotifier(config.WebhookConfig{URL: server.URL}, nil)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+	notifier.SetMaxOutputLength(4)
+
+	err = notifier.ShowWithOutput(context.Background(), OutputNotification{
+		Notification: Notification{Title: "Spell Failed", Level: LevelError},
+		Output:       "ab→cd",
+		ExitCode:     1,
+	})
+	if err != nil {
+		t.Fatalf("ShowWithOutput() error = %v", err)
+	}
+	_, _, payload := server.last(t)
+	if payload["output"] != "ab" || payload["truncated_bytes"] != float64(5) {
+		t.Errorf("output = %q, truncated_bytes = %v, want \"ab\" and 5", payload["output"], payload["truncated_bytes"])
+	}
+}
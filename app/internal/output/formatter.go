@@ -60,17 +60,37 @@ func (f *Formatter) Format(input string) string {
 	return output
 }
 
+// ansiRegex matches ANSI escape sequences:
+// ESC [ ... m (SGR - Select Graphic Rendition) and the other CSI sequences
+// such as ESC [ ... K (EL - Erase Line) or ESC [ ? 25 l (hide cursor),
+// ESC ] ... BEL (OSC - e.g. window titles) and two-character escapes
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
 // stripANSI removes ANSI escape sequences from the string
 func (f *Formatter) stripANSI(s string) string {
-	// Match ANSI escape sequences
-	// ESC [ ... m (SGR - Select Graphic Rendition)
-	// ESC [ ... J (ED - Erase Display)
-	// ESC [ ... K (EL - Erase Line)
-	// ESC [ ... H (CUP - Cursor Position)
-	ansiRegex := regexp.MustCompile(`\x1b\[[0-9;]*[mJKH]`)
+	return StripANSI(s)
+}
+
+// StripANSI removes ANSI escape sequences from the string
+func StripANSI(s string) string {
 	return ansiRegex.ReplaceAllString(s, "")
 }
 
+// RenderTerminalText renders the raw output of a pseudo-terminal as plain
+// text: escape sequences are removed, CRLF line endings become LF, and text
+// overwritten after a carriage return, e.g. by a progress bar, is dropped
+func RenderTerminalText(s string) string {
+	lines := strings.Split(StripANSI(strings.ReplaceAll(s, "\r\n", "\n")), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if j := strings.LastIndexByte(line, '\r'); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
 // normalizeWhitespace normalizes whitespace in the string
 func (f *Formatter) normalizeWhitespace(s string) string {
 	// Replace tabs with spaces
@@ -403,3 +403,24 @@ func TestFormatter_FormatError(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderTerminalText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"line endings", "one\r\ntwo\r\n", "one\ntwo\n"},
+		{"colors and cursor", "\x1b[?25l\x1b[1;32mok\x1b[0m\x1b[?25h\r\n", "ok\n"},
+		{"progress bar", "10%\r50%\r100%\r\ndone", "100%\ndone"},
+		{"window title", "\x1b]0;build\x07building\r\n", "building\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderTerminalText(tt.input); got != tt.want {
+				t.Errorf("RenderTerminalText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
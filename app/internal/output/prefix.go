@@ -0,0 +1,47 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter writes output with a prefix at the start of every line, so
+// that the output of concurrent scripts on one console can be told apart
+type PrefixWriter struct {
+	mu          sync.Mutex
+	writer      io.Writer
+	prefix      []byte
+	atLineStart bool
+}
+
+// NewPrefixWriter creates a writer that prefixes every line written to w
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{writer: w, prefix: []byte(prefix), atLineStart: true}
+}
+
+// Write implements io.Writer
+func (w *PrefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var buf bytes.Buffer
+	for rest := p; len(rest) > 0; {
+		if w.atLineStart {
+			buf.Write(w.prefix)
+			w.atLineStart = false
+		}
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line = rest[:i+1]
+			w.atLineStart = true
+		}
+		buf.Write(line)
+		rest = rest[len(line):]
+	}
+
+	if _, err := w.writer.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
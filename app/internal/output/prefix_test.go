@@ -0,0 +1,22 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewPrefixWriter(&out, "[build] ")
+
+	for _, chunk := range []string{"compil", "ing\nlinking\n", "done"} {
+		if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+
+	want := "[build] compiling\n[build] linking\n[build] done"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,232 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// RunLogDir is the directory of script output logs in the config directory
+const RunLogDir = "logs"
+
+// runLogTimeFormat names log files so that they sort by start time
+const runLogTimeFormat = "20060102-150405.000000000"
+
+// RunLogs keeps the output of script runs as one file per run, in a directory
+// per spell, and removes the oldest runs of a spell beyond the configured limit
+type RunLogs struct {
+	dir string
+	now func() time.Time
+
+	mu  sync.RWMutex
+	cfg config.ExecutionLogsConfig
+}
+
+// NewRunLogs creates run logs stored in dir, with logging disabled until
+// Configure enables it
+func NewRunLogs(dir string) *RunLogs {
+	return &RunLogs{dir: dir, now: time.Now}
+}
+
+// Configure applies the execution_logs settings, e.g. after a config reload
+func (l *RunLogs) Configure(cfg config.ExecutionLogsConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = cfg
+}
+
+// Enabled reports whether runs of an action are logged
+func (l *RunLogs) Enabled(action *config.ActionConfig) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return action.LogEnabled(l.cfg)
+}
+
+// Create starts the log of a new run of spell
+func (l *RunLogs) Create(spell, command string) (*RunLog, error) {
+	l.mu.RLock()
+	cfg := l.cfg
+	l.mu.RUnlock()
+
+	dir := filepath.Join(l.dir, runLogName(spell))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	started := l.now()
+	var file *os.File
+	var err error
+	for stamp := started; ; stamp = stamp.Add(time.Nanosecond) {
+		name := stamp.Format(runLogTimeFormat) + ".log"
+		file, err = os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if !os.IsExist(err) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log: %w", err)
+	}
+
+	log := &RunLog{file: file, started: started, remaining: -1}
+	if cfg.MaxSize > 0 {
+		log.remaining = int64(cfg.MaxSize) * 1024
+	}
+	fmt.Fprintf(file, "# spell: %s\n# command: %s\n# started: %s\n\n", spell, firstCommandLine(command), started.Format(time.RFC3339))
+
+	if cfg.MaxRuns > 0 {
+		l.prune(dir, cfg.MaxRuns)
+	}
+	return log, nil
+}
+
+// prune removes the oldest logs in dir beyond maxRuns
+func (l *RunLogs) prune(dir string, maxRuns int) {
+	paths, err := logFiles(dir)
+	if err != nil || len(paths) <= maxRuns {
+		return
+	}
+	for _, path := range paths[:len(paths)-maxRuns] {
+		_ = os.Remove(path)
+	}
+}
+
+// RunLog is the log of a single script run. Writes beyond the size limit are
+// dropped and counted.
+type RunLog struct {
+	mu        sync.Mutex
+	file      *os.File
+	started   time.Time
+	remaining int64 // Bytes still written, -1 for no limit
+	truncated int64
+	closed    bool
+}
+
+// Path returns the path of the log file
+func (r *RunLog) Path() string {
+	return r.file.Name()
+}
+
+// File returns the log file, for scripts that outlive SilentCast and write to
+// it directly. Such writes are not limited by max_size.
+func (r *RunLog) File() *os.File {
+	return r.file
+}
+
+// Write implements io.Writer
+func (r *RunLog) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	data := p
+	if r.remaining >= 0 && int64(len(data)) > r.remaining {
+		r.truncated += int64(len(data)) - r.remaining
+		data = data[:r.remaining]
+	}
+	if len(data) > 0 {
+		if _, err := r.file.Write(data); err != nil {
+			return 0, err
+		}
+		if r.remaining >= 0 {
+			r.remaining -= int64(len(data))
+		}
+	}
+	return len(p), nil
+}
+
+// Finish records how the run ended and closes the log. exitCode is -1 when
+// the script did not exit by itself, e.g. after a timeout.
+func (r *RunLog) Finish(exitCode int, runErr error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	elapsed := time.Since(r.started).Round(time.Millisecond)
+	var footer strings.Builder
+	footer.WriteString("\n")
+	if r.truncated > 0 {
+		fmt.Fprintf(&footer, "# truncated: %d bytes\n", r.truncated)
+	}
+	switch {
+	case exitCode >= 0:
+		fmt.Fprintf(&footer, "# finished: exit %d after %s\n", exitCode, elapsed)
+	case runErr != nil:
+		fmt.Fprintf(&footer, "# finished: %v after %s\n", runErr, elapsed)
+	default:
+		fmt.Fprintf(&footer, "# finished: after %s\n", elapsed)
+	}
+	_, err := r.file.WriteString(footer.String())
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// RunLogPaths returns the logs of a spell in dir, oldest first
+func RunLogPaths(dir, spell string) ([]string, error) {
+	return logFiles(filepath.Join(dir, runLogName(spell)))
+}
+
+// LatestRunLog returns the path of the most recent log of a spell in dir
+func LatestRunLog(dir, spell string) (string, error) {
+	paths, err := RunLogPaths(dir, spell)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no output logs for spell %q", spell)
+	}
+	return paths[len(paths)-1], nil
+}
+
+// logFiles returns the log files in dir sorted by start time
+func logFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".log") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// runLogName returns the directory name of a spell's logs
+func runLogName(spell string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, spell)
+	if name == "" || name == "." || name == ".." {
+		return "_" + name
+	}
+	return name
+}
+
+// firstCommandLine returns the first line of a command, marking that more follows
+func firstCommandLine(command string) string {
+	command = strings.TrimSpace(command)
+	if i := strings.IndexByte(command, '\n'); i >= 0 {
+		return command[:i] + " …"
+	}
+	return command
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestRunLogs_CreateAndFinish(t *testing.T) {
+	dir := t.TempDir()
+	logs := NewRunLogs(dir)
+	logs.Configure(config.ExecutionLogsConfig{Enabled: true, MaxRuns: 5, MaxSize: 1})
+
+	log, err := logs.Create("git/status", "git status\ngit log")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if filepath.Base(filepath.Dir(log.Path())) != "git_status" {
+		t.Errorf("log path = %s, want it under git_status", log.Path())
+	}
+	if _, err := log.Write([]byte(strings.Repeat("x", 1000))); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// Past max_size, output is counted but not written
+	if n, err := log.Write([]byte(strings.Repeat("y", 100))); err != nil || n != 100 {
+		t.Fatalf("Write() = %d, %v, want 100, nil", n, err)
+	}
+	if err := log.Finish(2, errors.New("exit status 2")); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	data, err := os.ReadFile(log.Path())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{"# spell: git/status", "# command: git status …", "# truncated: 76 bytes", "# finished: exit 2 after"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("log %q does not contain %q", content, want)
+		}
+	}
+	if !strings.Contains(content, "\n\n"+strings.Repeat("x", 1000)+strings.Repeat("y", 24)+"\n#") {
+		t.Errorf("log %q does not hold exactly the first 1024 bytes of output", content)
+	}
+	if _, err := log.Write([]byte("late")); err == nil {
+		t.Error("Write() after Finish() should fail")
+	}
+}
+
+func TestRunLogs_Retention(t *testing.T) {
+	dir := t.TempDir()
+	logs := NewRunLogs(dir)
+	logs.Configure(config.ExecutionLogsConfig{Enabled: true, MaxRuns: 3})
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	logs.now = func() time.Time { return now }
+
+	var paths []string
+	for i := 0; i < 5; i++ {
+		// Two runs start at the same instant to check that names stay unique
+		if i != 2 {
+			now = now.Add(time.Second)
+		}
+		log, err := logs.Create("backup", "./backup.sh")
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := log.Finish(0, nil); err != nil {
+			t.Fatalf("Finish() error = %v", err)
+		}
+		paths = append(paths, log.Path())
+	}
+
+	kept, err := RunLogPaths(dir, "backup")
+	if err != nil {
+		t.Fatalf("RunLogPaths() error = %v", err)
+	}
+	if strings.Join(kept, "\n") != strings.Join(paths[2:], "\n") {
+		t.Errorf("kept %v, want the newest three %v", kept, paths[2:])
+	}
+
+	latest, err := LatestRunLog(dir, "backup")
+	if err != nil || latest != paths[4] {
+		t.Errorf("LatestRunLog() = %s, %v, want %s", latest, err, paths[4])
+	}
+	if _, err := LatestRunLog(dir, "unknown"); err == nil {
+		t.Error("LatestRunLog() of a spell without logs should fail")
+	}
+}
+
+func TestRunLogs_Enabled(t *testing.T) {
+	logs := NewRunLogs(t.TempDir())
+	on, off := true, false
+
+	tests := []struct {
+		name    string
+		enabled bool
+		log     *bool
+		want    bool
+	}{
+		{name: "disabled by default", want: false},
+		{name: "enabled globally", enabled: true, want: true},
+		{name: "spell opts out", enabled: true, log: &off, want: false},
+		{name: "spell opts in", log: &on, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Configure(config.ExecutionLogsConfig{Enabled: tt.enabled})
+			if got := logs.Enabled(&config.ActionConfig{Log: tt.log}); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
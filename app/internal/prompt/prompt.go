@@ -0,0 +1,138 @@
+// Package prompt asks the user for a line of text in a dialog, through a
+// dialog tool found on the system.
+package prompt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrCancelled is returned when the user cancels the dialog
+var ErrCancelled = errors.New("prompt cancelled")
+
+// Dialog is a command-line tool that shows an input dialog
+type Dialog struct {
+	Name    string
+	Command string
+	args    func(title, message string) []string
+}
+
+// Args returns the arguments that ask for input with title and message
+func (d Dialog) Args(title, message string) []string {
+	return d.args(title, message)
+}
+
+// lookPath finds a command in PATH; replaced in tests
+var lookPath = exec.LookPath
+
+// platformDialogs returns the dialog tools known on the current platform,
+// most preferred first
+func platformDialogs() []Dialog {
+	switch runtime.GOOS {
+	case "darwin":
+		return []Dialog{
+			{
+				Name: "osascript", Command: "osascript",
+				args: func(title, message string) []string {
+					// The texts are passed as arguments, so they need no quoting
+					return []string{
+						"-e", "on run argv",
+						"-e", `text returned of (display dialog (item 1 of argv) default answer "" with title (item 2 of argv))`,
+						"-e", "end run",
+						message, title,
+					}
+				},
+			},
+		}
+	case "windows":
+		return []Dialog{
+			{
+				Name: "powershell", Command: "powershell.exe",
+				args: func(title, message string) []string {
+					return []string{"-NoProfile", "-NonInteractive", "-Command",
+						"[Console]::OutputEncoding = [Text.Encoding]::UTF8; Add-Type -AssemblyName Microsoft.VisualBasic; " +
+							"[Microsoft.VisualBasic.Interaction]::InputBox(" + psQuote(message) + ", " + psQuote(title) + ")"}
+				},
+			},
+		}
+	default:
+		return []Dialog{
+			{
+				Name: "zenity", Command: "zenity",
+				args: func(title, message string) []string {
+					return []string{"--entry", "--title", title, "--text", message}
+				},
+			},
+			{
+				Name: "kdialog", Command: "kdialog",
+				args: func(title, message string) []string {
+					return []string{"--title", title, "--inputbox", message}
+				},
+			},
+		}
+	}
+}
+
+// DetectDialog returns the most preferred dialog tool that is available
+func DetectDialog() (Dialog, error) {
+	for _, dialog := range platformDialogs() {
+		if _, err := lookPath(dialog.Command); err == nil {
+			return dialog, nil
+		}
+	}
+	return Dialog{}, fmt.Errorf("no dialog tool found (%s)", dialogHint())
+}
+
+// Ask shows a dialog and returns the text the user entered. It returns
+// ErrCancelled if the user closes the dialog, and on Windows, where the
+// dialog cannot tell, also if nothing was entered.
+func Ask(ctx context.Context, title, message string) (string, error) {
+	dialog, err := DetectDialog()
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, dialog.Command, dialog.Args(title, message)...) //nolint:gosec // Command is from the known dialog tools
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// The dialog tools exit with status 1 when cancelled
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", ErrCancelled
+		}
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("failed to show dialog with %s: %w: %s", dialog.Name, err, detail)
+		}
+		return "", fmt.Errorf("failed to show dialog with %s: %w", dialog.Name, err)
+	}
+
+	answer := strings.TrimRight(stdout.String(), "\r\n")
+	if answer == "" && runtime.GOOS == "windows" {
+		return "", ErrCancelled
+	}
+	return answer, nil
+}
+
+// dialogHint suggests how to install a dialog tool on the current platform
+func dialogHint() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "osascript ships with macOS"
+	case "windows":
+		return "PowerShell is required"
+	default:
+		return "install zenity or kdialog"
+	}
+}
+
+// psQuote quotes a string for PowerShell
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
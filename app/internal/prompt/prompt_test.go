@@ -0,0 +1,66 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeZenity puts a zenity that runs script first in PATH
+func fakeZenity(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "zenity"), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestAsk(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses a fake zenity")
+	}
+
+	tests := []struct {
+		name    string
+		script  string
+		want    string
+		wantErr error
+	}{
+		{name: "answer", script: `echo "$5: hello"`, want: "Text: hello"},
+		{name: "cancelled", script: "exit 1", wantErr: ErrCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeZenity(t, tt.script)
+			got, err := Ask(context.Background(), "Translate", "Text")
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("Ask() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	fakeZenity(t, "echo broken >&2; exit 5")
+	if _, err := Ask(context.Background(), "Translate", "Text"); err == nil || errors.Is(err, ErrCancelled) {
+		t.Errorf("Ask() error = %v, want dialog failure", err)
+	}
+}
+
+func TestDialogArgs_Darwin(t *testing.T) {
+	if runtime.GOOS != "darwin" {
+		t.Skip("macOS dialog")
+	}
+
+	dialog, err := DetectDialog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := dialog.Args("It's a title", `say "hi"`)
+	if args[len(args)-2] != `say "hi"` || args[len(args)-1] != "It's a title" {
+		t.Errorf("Args() = %v, want message and title passed unquoted", args)
+	}
+}
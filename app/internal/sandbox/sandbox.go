@@ -0,0 +1,39 @@
+// Package sandbox contains the damage a script can do: resource limits,
+// its own process group, a restricted environment, read-only paths and no
+// network. Limits are set by SilentCast itself, re-executed between fork and
+// exec of the script, so Init must run first thing in main.
+package sandbox
+
+import "strings"
+
+// initArg is the first argument of SilentCast re-executed to set up a script
+const initArg = "__sandbox-init"
+
+// DefaultEnv are the variables of the environment a sandboxed script gets,
+// in addition to those it passes with sandbox.env, its env and env files
+var DefaultEnv = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL",
+	"LANG", "LANGUAGE", "LC_ALL", "LC_CTYPE", "TERM", "TZ", "TMPDIR",
+}
+
+// Visible returns a function that reports whether a variable is named in
+// DefaultEnv or pass
+func Visible(pass []string) func(name string) bool {
+	allowed := make(map[string]bool, len(DefaultEnv)+len(pass))
+	for _, name := range append(append([]string(nil), DefaultEnv...), pass...) {
+		allowed[name] = true
+	}
+	return func(name string) bool { return allowed[name] }
+}
+
+// Environ returns the variables of environ named in DefaultEnv or pass
+func Environ(environ []string, pass []string) []string {
+	visible := Visible(pass)
+	var restricted []string
+	for _, entry := range environ {
+		if name, _, _ := strings.Cut(entry, "="); visible(name) {
+			restricted = append(restricted, entry)
+		}
+	}
+	return restricted
+}
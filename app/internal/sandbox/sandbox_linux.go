@@ -0,0 +1,230 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+)
+
+// I/O priority constants of ioprio_set(2)
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+)
+
+// spec is what the re-executed SilentCast sets up before it runs the script
+type spec struct {
+	Path     string   `json:"path"`
+	Rlimits  []rlimit `json:"rlimits,omitempty"`
+	Nice     int      `json:"nice,omitempty"`
+	IOClass  int      `json:"io_class,omitempty"`
+	IOLevel  int      `json:"io_level,omitempty"`
+	ReadOnly []string `json:"read_only,omitempty"`
+	Isolate  bool     `json:"isolate,omitempty"`
+}
+
+type rlimit struct {
+	Resource int    `json:"resource"`
+	Value    uint64 `json:"value"`
+}
+
+// Apply makes cmd run with the limits and sandbox. It must be called after
+// the command's path, arguments, directory and other process attributes
+// are set, right before it is started.
+func Apply(cmd *exec.Cmd, limits config.LimitsConfig, box config.SandboxConfig) error {
+	s, err := newSpec(cmd, limits, box)
+	if err != nil {
+		return err
+	}
+
+	attrs := cmd.SysProcAttr
+	if attrs == nil {
+		attrs = &syscall.SysProcAttr{}
+		cmd.SysProcAttr = attrs
+	}
+
+	// Its own process group (or session), so that a timeout kills the
+	// script's children too
+	if !attrs.Setsid {
+		attrs.Setpgid = true
+	}
+	if cmd.Cancel != nil {
+		cmd.Cancel = func() error {
+			return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		}
+	}
+
+	// Without network or with read-only paths, the script runs in a user
+	// namespace mapped to the current user, with its own network and mounts
+	if box.Enabled && (!box.Network || len(s.ReadOnly) > 0) {
+		attrs.Cloneflags |= syscall.CLONE_NEWUSER
+		if !box.Network {
+			attrs.Cloneflags |= syscall.CLONE_NEWNET
+		}
+		if len(s.ReadOnly) > 0 {
+			attrs.Cloneflags |= syscall.CLONE_NEWNS
+			attrs.AmbientCaps = append(attrs.AmbientCaps, unix.CAP_SYS_ADMIN)
+		}
+		uid, gid := os.Getuid(), os.Getgid()
+		attrs.UidMappings = []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}}
+		attrs.GidMappings = []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}}
+	}
+
+	if len(s.Rlimits) == 0 && s.Nice == 0 && s.IOClass == 0 && len(s.ReadOnly) == 0 {
+		return nil
+	}
+
+	// Limits and mounts are set up by SilentCast itself before it execs the script
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	cmd.Args = append([]string{"silentcast", initArg, string(encoded)}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+	return nil
+}
+
+// newSpec converts the limits and sandbox of a command
+func newSpec(cmd *exec.Cmd, limits config.LimitsConfig, box config.SandboxConfig) (*spec, error) {
+	s := &spec{Path: cmd.Path, Nice: limits.Nice, Isolate: box.Enabled}
+
+	for _, size := range []struct {
+		resource int
+		value    string
+	}{{unix.RLIMIT_AS, limits.Memory}, {unix.RLIMIT_FSIZE, limits.FileSize}} {
+		if size.value == "" {
+			continue
+		}
+		value, err := config.ParseSize(size.value)
+		if err != nil {
+			return nil, err
+		}
+		s.Rlimits = append(s.Rlimits, rlimit{Resource: size.resource, Value: value})
+	}
+	if limits.CPUTime > 0 {
+		s.Rlimits = append(s.Rlimits, rlimit{Resource: unix.RLIMIT_CPU, Value: uint64(limits.CPUTime)})
+	}
+	if limits.Processes > 0 {
+		s.Rlimits = append(s.Rlimits, rlimit{Resource: unix.RLIMIT_NPROC, Value: uint64(limits.Processes)})
+	}
+
+	if limits.IONice != "" {
+		class, level, err := config.ParseIONice(limits.IONice)
+		if err != nil {
+			return nil, err
+		}
+		s.IOClass, s.IOLevel = class, level
+	}
+
+	if box.Enabled {
+		for _, path := range box.ReadOnly {
+			path = dotenv.ExpandPath(path, cmd.Dir)
+			if _, err := os.Stat(path); err != nil {
+				return nil, fmt.Errorf("read-only path: %w", err)
+			}
+			s.ReadOnly = append(s.ReadOnly, path)
+		}
+	}
+	return s, nil
+}
+
+// Init sets up and runs a script when SilentCast was re-executed by Apply,
+// and returns otherwise
+func Init() {
+	if len(os.Args) < 3 || os.Args[1] != initArg {
+		return
+	}
+	if err := runInit(os.Args[2], os.Args[3:]); err != nil {
+		fmt.Fprintf(os.Stderr, "silentcast: sandbox: %v\n", err)
+		os.Exit(126)
+	}
+}
+
+// runInit applies a spec to the current process and execs the script.
+// Priorities and capabilities are per thread, so they are set on the
+// thread that execs.
+func runInit(encoded string, args []string) error {
+	runtime.LockOSThread()
+
+	var s spec
+	if err := json.Unmarshal([]byte(encoded), &s); err != nil {
+		return fmt.Errorf("invalid spec: %w", err)
+	}
+
+	if len(s.ReadOnly) > 0 {
+		if err := mountReadOnly(s.ReadOnly); err != nil {
+			return err
+		}
+	}
+	if s.Isolate {
+		dropPrivileges()
+	}
+
+	if s.Nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, 0, s.Nice); err != nil {
+			return fmt.Errorf("failed to set nice: %w", err)
+		}
+	}
+	if s.IOClass != 0 {
+		prio := uintptr(s.IOClass<<ioprioClassShift | s.IOLevel)
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, 0, prio); errno != 0 {
+			return fmt.Errorf("failed to set ionice: %w", errno)
+		}
+	}
+
+	// Limits come last, since they also apply to SilentCast until it execs
+	for _, limit := range s.Rlimits {
+		if err := unix.Setrlimit(limit.Resource, &unix.Rlimit{Cur: limit.Value, Max: limit.Value}); err != nil {
+			return fmt.Errorf("failed to set limit %d: %w", limit.Resource, err)
+		}
+	}
+	return unix.Exec(s.Path, args, os.Environ())
+}
+
+// mountReadOnly bind mounts paths onto themselves read-only, in the mount
+// namespace of the script
+func mountReadOnly(paths []string) error {
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %w", err)
+	}
+	for _, path := range paths {
+		if err := unix.Mount(path, path, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+			return fmt.Errorf("failed to bind %s: %w", path, err)
+		}
+		// A remount must keep the flags the original mount is locked with
+		var st unix.Statfs_t
+		if err := unix.Statfs(path, &st); err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		locked := uintptr(st.Flags) & (unix.MS_NOSUID | unix.MS_NODEV | unix.MS_NOEXEC | unix.MS_NOATIME | unix.MS_NODIRATIME | unix.MS_RELATIME)
+		if err := unix.Mount("", path, "", unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY|locked, ""); err != nil {
+			return fmt.Errorf("failed to make %s read-only: %w", path, err)
+		}
+	}
+
+	// The working directory may be below a path that was just mounted
+	if wd, err := os.Getwd(); err == nil {
+		_ = os.Chdir(wd)
+	}
+	return nil
+}
+
+// dropPrivileges keeps the script from regaining the capabilities that set
+// up its namespaces, e.g. to remount a read-only path, or any others
+func dropPrivileges() {
+	_ = unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0)
+	for capability := 0; capability <= unix.CAP_LAST_CAP; capability++ {
+		_ = unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(capability), 0, 0, 0)
+	}
+	_ = unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0)
+}
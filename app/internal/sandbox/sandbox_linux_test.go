@@ -0,0 +1,101 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// TestMain lets the test binary set up commands like SilentCast does
+func TestMain(m *testing.M) {
+	Init()
+	os.Exit(m.Run())
+}
+
+// skipWithoutUserNamespaces skips tests that need unprivileged user namespaces
+func skipWithoutUserNamespaces(t *testing.T) {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := Apply(cmd, config.LimitsConfig{}, config.SandboxConfig{Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Skipf("user namespaces are not available: %v", err)
+	}
+}
+
+func TestApply_Limits(t *testing.T) {
+	cmd := exec.CommandContext(context.Background(), "sh", "-c",
+		`grep -E '^Max (cpu time|file size|processes|address space)' /proc/self/limits; awk '{print "nice", $19}' /proc/self/stat`)
+	limits := config.LimitsConfig{Memory: "1G", CPUTime: 30, Processes: 4096, FileSize: "10M", Nice: 10, IONice: config.IONiceIdle}
+	if err := Apply(cmd, limits, config.SandboxConfig{}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("command failed: %v: %s", err, out)
+	}
+	for _, want := range []string{"1073741824", "30", "4096", "10485760", "nice 10"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestApply_ProcessGroup(t *testing.T) {
+	cmd := exec.CommandContext(context.Background(), "sh", "-c", `echo $$ $(cut -d' ' -f5 /proc/$$/stat)`)
+	if err := Apply(cmd, config.LimitsConfig{CPUTime: 60}, config.SandboxConfig{}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if fields := strings.Fields(string(out)); len(fields) != 2 || fields[0] != fields[1] {
+		t.Errorf("pid and process group = %q, want the script to lead its group", out)
+	}
+}
+
+func TestApply_Sandbox(t *testing.T) {
+	skipWithoutUserNamespaces(t)
+
+	dir := t.TempDir()
+	protected := filepath.Join(dir, "protected")
+	if err := os.Mkdir(protected, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.CommandContext(context.Background(), "sh", "-c",
+		`touch protected/file 2>/dev/null && echo writable || echo read-only; touch other && echo other writable; grep -c : /proc/net/dev`)
+	cmd.Dir = dir
+	if err := Apply(cmd, config.LimitsConfig{}, config.SandboxConfig{Enabled: true, ReadOnly: []string{"protected"}}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("command failed: %v: %s", err, out)
+	}
+	// Only the loopback interface exists without network
+	want := "read-only\nother writable\n1\n"
+	if string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestApply_MissingReadOnlyPath(t *testing.T) {
+	cmd := exec.Command("true")
+	box := config.SandboxConfig{Enabled: true, ReadOnly: []string{filepath.Join(t.TempDir(), "missing")}}
+	if err := Apply(cmd, config.LimitsConfig{}, box); err == nil {
+		t.Error("Apply() with a missing read-only path succeeded, want error")
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// Apply makes cmd run with the limits and sandbox, which are only
+// supported on Linux
+func Apply(_ *exec.Cmd, _ config.LimitsConfig, _ config.SandboxConfig) error {
+	return fmt.Errorf("limits and sandbox are not supported on %s", runtime.GOOS)
+}
+
+// Init sets up a script re-executed by Apply, which only happens on Linux
+func Init() {}
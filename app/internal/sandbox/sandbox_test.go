@@ -0,0 +1,22 @@
+package sandbox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnviron(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"HOME=/home/mage",
+		"AWS_SECRET_ACCESS_KEY=hunter2",
+		"DISPLAY=:0",
+		"SSH_AUTH_SOCK=/run/agent",
+	}
+
+	got := Environ(environ, []string{"DISPLAY"})
+	want := []string{"PATH=/usr/bin", "HOME=/home/mage", "DISPLAY=:0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Environ() = %v, want %v", got, want)
+	}
+}
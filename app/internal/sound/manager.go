@@ -0,0 +1,210 @@
+package sound
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+	"github.com/SphereStacking/silentcast/internal/dotenv"
+	"github.com/SphereStacking/silentcast/pkg/logger"
+)
+
+// maxPlaying bounds the cues played at once; more are skipped
+const maxPlaying = 4
+
+// DefaultCacheDir returns the directory where bundled sounds are written
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, config.AppName, "sounds")
+}
+
+// Manager plays the sound configured for each event. Like DoNotDisturb it
+// outlives configuration reloads; Configure applies the new settings.
+type Manager struct {
+	mu       sync.Mutex
+	enabled  bool
+	player   Player
+	volume   int
+	cfg      config.SoundConfig
+	cacheDir string
+	playing  atomic.Int32
+}
+
+// NewManager creates a manager that writes bundled sounds to cacheDir.
+// Cues are off until Configure enables them.
+func NewManager(cacheDir string) *Manager {
+	return &Manager{cacheDir: cacheDir, volume: 80}
+}
+
+// Configure applies the notification settings. Cues play only when both
+// notification.sound and notification.sounds.enabled are on. An error means
+// no player was found, and cues stay off.
+func (m *Manager) Configure(cfg config.NotificationConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cfg = cfg.Sounds
+	m.volume = cfg.Sounds.Volume
+	if m.volume <= 0 || m.volume > 100 {
+		m.volume = 80
+	}
+	m.enabled = false
+	if !cfg.SoundEnabled() || !cfg.Sounds.SoundsEnabled() {
+		return nil
+	}
+
+	player, err := FindPlayer(cfg.Sounds.Player)
+	if err != nil {
+		return err
+	}
+	m.player = player
+	m.enabled = true
+	return nil
+}
+
+// Enabled reports whether cues are played
+func (m *Manager) Enabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.enabled
+}
+
+// Player returns the player in use
+func (m *Manager) Player() Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.player
+}
+
+// Play plays the cue for event in the background. Events without a sound,
+// and cues beyond the few already playing, are skipped.
+func (m *Manager) Play(event string) {
+	cmd, err := m.command(event)
+	if err != nil {
+		logger.Debug("Sound cue %s: %v", event, err)
+		return
+	}
+	if cmd == nil {
+		return
+	}
+	if m.playing.Add(1) > maxPlaying {
+		m.playing.Add(-1)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		m.playing.Add(-1)
+		logger.Debug("Sound cue %s: %v", event, err)
+		return
+	}
+	go func() {
+		defer m.playing.Add(-1)
+		if err := cmd.Wait(); err != nil {
+			logger.Debug("Sound cue %s: %v", event, err)
+		}
+	}()
+}
+
+// PlayAndWait plays the cue for event and waits for it to finish
+func (m *Manager) PlayAndWait(event string) error {
+	cmd, err := m.command(event)
+	if err != nil || cmd == nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", cmd.Path, err, msg)
+		}
+		return fmt.Errorf("%s: %w", cmd.Path, err)
+	}
+	return nil
+}
+
+// Sound returns the sound configured for event: a bundled name, a file, or "none"
+func (m *Manager) Sound(event string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg.Sound(event)
+}
+
+// command builds the player command for event, or nil if the event is silent
+func (m *Manager) command(event string) (*exec.Cmd, error) {
+	m.mu.Lock()
+	enabled, player, volume := m.enabled, m.player, m.volume
+	sound := m.cfg.Sound(event)
+	m.mu.Unlock()
+
+	if !enabled || sound == "" || sound == config.SoundNone {
+		return nil, nil
+	}
+	file, err := m.resolve(sound, player, volume)
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(player.Command, player.Args(file, volume)...), nil //nolint:gosec // Player and file come from the configuration
+}
+
+// resolve returns the file to play for a sound. Bundled sounds are written to
+// the cache directory on first use; for players without volume control they
+// are rendered at the configured volume.
+func (m *Manager) resolve(sound string, player Player, volume int) (string, error) {
+	if !config.IsBundledSound(sound) {
+		file := dotenv.ExpandPath(sound, "")
+		if _, err := os.Stat(file); err != nil {
+			return "", fmt.Errorf("sound file not found: %s", file)
+		}
+		return file, nil
+	}
+
+	name := sound + ".wav"
+	renderVolume := 100
+	if !player.Volume {
+		renderVolume = volume
+		name = fmt.Sprintf("%s-%d.wav", sound, volume)
+	}
+	file := filepath.Join(m.cacheDir, name)
+	if _, err := os.Stat(file); err == nil {
+		return file, nil
+	}
+
+	data, _ := synthesize(sound, renderVolume)
+	if err := os.MkdirAll(m.cacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create sound cache: %w", err)
+	}
+	// Write then rename so a concurrent cue never plays a partial file
+	tmp, err := os.CreateTemp(m.cacheDir, name+".*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write sound: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write sound: %v", firstError(writeErr, closeErr))
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write sound: %w", err)
+	}
+	return file, nil
+}
+
+// firstError returns the first non-nil error
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package sound
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// recordingPlayer returns a player that appends each file it plays to log
+func recordingPlayer(t *testing.T, log string, volume bool) Player {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh as the player")
+	}
+	return Player{
+		Name: "fake", Command: "sh", Volume: volume,
+		args: func(file string, _ int) []string {
+			return []string{"-c", `echo "$0" >> "$1"`, file, log}
+		},
+	}
+}
+
+func playedFiles(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func TestManager_DisabledByDefault(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if err := m.Configure(config.NotificationConfig{}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if m.Enabled() {
+		t.Error("sound cues enabled without notification.sounds.enabled")
+	}
+	if err := m.PlayAndWait(config.SoundEventPrefix); err != nil {
+		t.Errorf("PlayAndWait() while disabled = %v, want nil", err)
+	}
+}
+
+func TestManager_PlayAndWait(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "cache")
+	log := filepath.Join(dir, "played")
+	custom := filepath.Join(dir, "done.wav")
+	if err := os.WriteFile(custom, []byte("RIFF"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(cacheDir)
+	m.enabled = true
+	m.player = recordingPlayer(t, log, false)
+	m.volume = 50
+	m.cfg = config.SoundConfig{Success: custom, Failure: config.SoundNone, Timeout: filepath.Join(dir, "missing.wav")}
+
+	for i := 0; i < 2; i++ {
+		if err := m.PlayAndWait(config.SoundEventPrefix); err != nil {
+			t.Fatalf("PlayAndWait(prefix) error = %v", err)
+		}
+	}
+	if err := m.PlayAndWait(config.SoundEventSuccess); err != nil {
+		t.Fatalf("PlayAndWait(success) error = %v", err)
+	}
+	if err := m.PlayAndWait(config.SoundEventFailure); err != nil {
+		t.Fatalf("PlayAndWait(failure) error = %v", err)
+	}
+	if err := m.PlayAndWait(config.SoundEventTimeout); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("PlayAndWait(timeout) error = %v, want sound file not found", err)
+	}
+
+	// The bundled sound is rendered once at the volume of a player without volume control
+	rendered := filepath.Join(cacheDir, "pop-50.wav")
+	want := []string{rendered, rendered, custom}
+	if got := playedFiles(t, log); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("played %v, want %v", got, want)
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("cache holds %v (%v), want only pop-50.wav", entries, err)
+	}
+}
+
+func TestManager_VolumePlayerUsesFullScaleSound(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "played")
+
+	m := NewManager(dir)
+	m.enabled = true
+	m.player = recordingPlayer(t, log, true)
+	m.volume = 30
+
+	if err := m.PlayAndWait(config.SoundEventFailure); err != nil {
+		t.Fatalf("PlayAndWait(failure) error = %v", err)
+	}
+	if got := playedFiles(t, log); len(got) != 1 || got[0] != filepath.Join(dir, "buzz.wav") {
+		t.Errorf("played %v, want buzz.wav", got)
+	}
+}
@@ -0,0 +1,130 @@
+// Package sound plays short audio cues for the prefix key and spell results
+// through a command-line audio player found on the system.
+package sound
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Player is a command-line audio player
+type Player struct {
+	Name     string
+	Command  string
+	Priority int  // Higher is preferred when detecting
+	Volume   bool // Whether the player can set the volume
+	args     func(file string, volume int) []string
+}
+
+// Args returns the arguments that play file at volume (1-100)
+func (p Player) Args(file string, volume int) []string {
+	return p.args(file, volume)
+}
+
+// lookPath finds a command in PATH; replaced in tests
+var lookPath = exec.LookPath
+
+// platformPlayers returns the players known on the current platform
+func platformPlayers() []Player {
+	switch runtime.GOOS {
+	case "darwin":
+		return []Player{
+			{
+				Name: "afplay", Command: "afplay", Priority: 100, Volume: true,
+				args: func(file string, volume int) []string {
+					return []string{"-v", fraction(volume), file}
+				},
+			},
+		}
+	case "windows":
+		return []Player{
+			{
+				Name: "powershell", Command: "powershell.exe", Priority: 100,
+				args: func(file string, _ int) []string {
+					quoted := "'" + strings.ReplaceAll(file, "'", "''") + "'"
+					return []string{"-NoProfile", "-NonInteractive", "-Command",
+						"(New-Object Media.SoundPlayer " + quoted + ").PlaySync()"}
+				},
+			},
+		}
+	default:
+		return []Player{
+			{
+				Name: "pw-play", Command: "pw-play", Priority: 100, Volume: true,
+				args: func(file string, volume int) []string {
+					return []string{"--volume=" + fraction(volume), file}
+				},
+			},
+			{
+				Name: "paplay", Command: "paplay", Priority: 90, Volume: true,
+				args: func(file string, volume int) []string {
+					// 65536 is 100% for PulseAudio
+					return []string{"--volume=" + strconv.Itoa(volume*65536/100), file}
+				},
+			},
+			{
+				Name: "aplay", Command: "aplay", Priority: 50,
+				args: func(file string, _ int) []string {
+					return []string{"-q", file}
+				},
+			},
+		}
+	}
+}
+
+// DetectPlayers returns the available players, most preferred first
+func DetectPlayers() []Player {
+	var available []Player
+	for _, player := range platformPlayers() {
+		if _, err := lookPath(player.Command); err == nil {
+			available = append(available, player)
+		}
+	}
+	sort.SliceStable(available, func(i, j int) bool {
+		return available[i].Priority > available[j].Priority
+	})
+	return available
+}
+
+// FindPlayer returns the named player, or the most preferred available one
+// for "auto" or an empty name
+func FindPlayer(name string) (Player, error) {
+	if name == "" || name == "auto" {
+		available := DetectPlayers()
+		if len(available) == 0 {
+			return Player{}, fmt.Errorf("no audio player found (%s)", playerHint())
+		}
+		return available[0], nil
+	}
+
+	for _, player := range platformPlayers() {
+		if strings.EqualFold(player.Name, name) || strings.EqualFold(player.Command, name) {
+			if _, err := lookPath(player.Command); err != nil {
+				return Player{}, fmt.Errorf("audio player '%s' not found in PATH", player.Command)
+			}
+			return player, nil
+		}
+	}
+	return Player{}, fmt.Errorf("audio player '%s' is not supported on %s", name, runtime.GOOS)
+}
+
+// playerHint suggests how to install a player on the current platform
+func playerHint() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "afplay ships with macOS"
+	case "windows":
+		return "PowerShell is required"
+	default:
+		return "install pipewire, pulseaudio-utils or alsa-utils"
+	}
+}
+
+// fraction formats a 1-100 volume as 0.00-1.00
+func fraction(volume int) string {
+	return strconv.FormatFloat(float64(volume)/100, 'f', 2, 64)
+}
@@ -0,0 +1,56 @@
+package sound
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+// fakeLookPath makes only the given commands available
+func fakeLookPath(t *testing.T, commands ...string) {
+	t.Helper()
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	lookPath = func(file string) (string, error) {
+		for _, command := range commands {
+			if file == command {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestFindPlayer_Linux(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Linux players")
+	}
+
+	fakeLookPath(t, "aplay", "paplay")
+	player, err := FindPlayer("auto")
+	if err != nil {
+		t.Fatalf("FindPlayer(auto) error = %v", err)
+	}
+	if player.Name != "paplay" {
+		t.Errorf("FindPlayer(auto) = %s, want paplay, preferred over aplay", player.Name)
+	}
+	if got := player.Args("/tmp/a.wav", 50); got[0] != "--volume=32768" {
+		t.Errorf("paplay args = %v, want --volume=32768 first", got)
+	}
+
+	player, err = FindPlayer("aplay")
+	if err != nil || player.Volume {
+		t.Errorf("FindPlayer(aplay) = %+v, %v; want aplay without volume control", player, err)
+	}
+	if _, err := FindPlayer("pw-play"); err == nil {
+		t.Error("FindPlayer(pw-play) succeeded, want not found in PATH")
+	}
+	if _, err := FindPlayer("afplay"); err == nil {
+		t.Error("FindPlayer(afplay) succeeded, want unsupported on linux")
+	}
+
+	fakeLookPath(t)
+	if _, err := FindPlayer(""); err == nil {
+		t.Error("FindPlayer with no players succeeded, want error")
+	}
+}
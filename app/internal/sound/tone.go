@@ -0,0 +1,80 @@
+package sound
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+// sampleRate is the sample rate of the bundled sounds
+const sampleRate = 22050
+
+// note is a tone of a frequency in Hz for a duration in milliseconds; a zero
+// frequency is a rest
+type note struct {
+	freq float64
+	ms   int
+}
+
+// bundledNotes are the melodies of the bundled sounds. They are synthesized
+// instead of shipped as files so that the binary stays self-contained.
+var bundledNotes = map[string][]note{
+	config.SoundPop:   {{1200, 45}},
+	config.SoundChime: {{660, 110}, {990, 180}},
+	config.SoundBuzz:  {{330, 140}, {0, 40}, {220, 220}},
+	config.SoundAlarm: {{880, 110}, {0, 70}, {880, 110}, {0, 70}, {880, 110}},
+}
+
+// synthesize renders a bundled sound as a 16-bit mono WAV file. Volume (1-100)
+// scales the samples for players that cannot set the volume themselves.
+func synthesize(name string, volume int) ([]byte, bool) {
+	notes, ok := bundledNotes[name]
+	if !ok {
+		return nil, false
+	}
+
+	const fade = sampleRate * 5 / 1000 // 5ms ramps avoid clicks
+	amplitude := 0.6 * math.MaxInt16 * float64(volume) / 100
+
+	var samples []int16
+	for _, n := range notes {
+		count := sampleRate * n.ms / 1000
+		for i := 0; i < count; i++ {
+			if n.freq == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			envelope := 1.0
+			if i < fade {
+				envelope = float64(i) / fade
+			} else if count-i < fade {
+				envelope = float64(count-i) / fade
+			}
+			value := math.Sin(2*math.Pi*n.freq*float64(i)/sampleRate) * amplitude * envelope
+			samples = append(samples, int16(value))
+		}
+	}
+
+	dataSize := len(samples) * 2
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	for _, field := range []interface{}{
+		uint32(16),             // fmt chunk size
+		uint16(1),              // PCM
+		uint16(1),              // Mono
+		uint32(sampleRate),     // Sample rate
+		uint32(sampleRate * 2), // Byte rate
+		uint16(2),              // Block align
+		uint16(16),             // Bits per sample
+	} {
+		_ = binary.Write(&buf, binary.LittleEndian, field)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	_ = binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes(), true
+}
@@ -0,0 +1,48 @@
+package sound
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/SphereStacking/silentcast/internal/config"
+)
+
+func TestSynthesize(t *testing.T) {
+	for _, name := range config.BundledSounds {
+		data, ok := synthesize(name, 100)
+		if !ok {
+			t.Fatalf("synthesize(%s) found no sound", name)
+		}
+		if string(data[0:4]) != "RIFF" || string(data[8:16]) != "WAVEfmt " || string(data[36:40]) != "data" {
+			t.Fatalf("synthesize(%s) wrote an invalid WAV header", name)
+		}
+		if size := binary.LittleEndian.Uint32(data[4:8]); int(size) != len(data)-8 {
+			t.Errorf("synthesize(%s) RIFF size = %d, want %d", name, size, len(data)-8)
+		}
+		if size := binary.LittleEndian.Uint32(data[40:44]); int(size) != len(data)-44 || size == 0 {
+			t.Errorf("synthesize(%s) data size = %d, want %d", name, size, len(data)-44)
+		}
+	}
+
+	if _, ok := synthesize("trumpet", 100); ok {
+		t.Error("synthesize(trumpet) succeeded, want unknown sound")
+	}
+}
+
+func TestSynthesize_Volume(t *testing.T) {
+	peak := func(data []byte) int16 {
+		var max int16
+		for i := 44; i+1 < len(data); i += 2 {
+			if v := int16(binary.LittleEndian.Uint16(data[i:])); v > max {
+				max = v
+			}
+		}
+		return max
+	}
+
+	loud, _ := synthesize(config.SoundChime, 100)
+	quiet, _ := synthesize(config.SoundChime, 25)
+	if peak(quiet) == 0 || peak(quiet) >= peak(loud)/2 {
+		t.Errorf("peak at 25%% = %d, at 100%% = %d; want about a quarter", peak(quiet), peak(loud))
+	}
+}
@@ -63,7 +63,7 @@ func (b *WindowsCommandBuilder) BuildCommand(terminal Terminal, cmd *exec.Cmd, o
 		}
 
 		if options.Customization != nil && terminal.SupportedFeatures.WindowPosition {
-			if options.Customization.X >= 0 && options.Customization.Y >= 0 {
+			if options.Customization.HasPosition() {
 				args = append(args, "--pos", fmt.Sprintf("%d,%d", options.Customization.X, options.Customization.Y))
 			}
 		}
@@ -105,7 +105,7 @@ func (b *WindowsCommandBuilder) BuildCommand(terminal Terminal, cmd *exec.Cmd, o
 		}
 
 		if options.Customization != nil && terminal.SupportedFeatures.WindowPosition {
-			if options.Customization.X >= 0 && options.Customization.Y >= 0 {
+			if options.Customization.HasPosition() {
 				startArgs = append(startArgs, "/POS", fmt.Sprintf("%d,%d", options.Customization.X, options.Customization.Y))
 			}
 		}
@@ -181,7 +181,7 @@ func (b *MacOSCommandBuilder) BuildCommand(terminal Terminal, cmd *exec.Cmd, opt
 			set bounds of window 1 to {0, 0, %d, %d}`, options.Customization.Width*8, options.Customization.Height*16)
 			}
 
-			if terminal.SupportedFeatures.WindowPosition && options.Customization.X >= 0 && options.Customization.Y >= 0 {
+			if terminal.SupportedFeatures.WindowPosition && options.Customization.HasPosition() {
 				appleScript += fmt.Sprintf(`
 			set position of window 1 to {%d, %d}`, options.Customization.X, options.Customization.Y)
 			}
@@ -229,7 +229,7 @@ func (b *MacOSCommandBuilder) BuildCommand(terminal Terminal, cmd *exec.Cmd, opt
 				args = append(args, "--dimensions", fmt.Sprintf("%dx%d", options.Customization.Width, options.Customization.Height))
 			}
 
-			if terminal.SupportedFeatures.WindowPosition && options.Customization.X >= 0 && options.Customization.Y >= 0 {
+			if terminal.SupportedFeatures.WindowPosition && options.Customization.HasPosition() {
 				args = append(args, "--position", fmt.Sprintf("%d,%d", options.Customization.X, options.Customization.Y))
 			}
 
@@ -379,7 +379,7 @@ func (b *LinuxCommandBuilder) BuildCommand(terminal Terminal, cmd *exec.Cmd, opt
 				args = append(args, "--dimensions", fmt.Sprintf("%dx%d", options.Customization.Width, options.Customization.Height))
 			}
 
-			if terminal.SupportedFeatures.WindowPosition && options.Customization.X >= 0 && options.Customization.Y >= 0 {
+			if terminal.SupportedFeatures.WindowPosition && options.Customization.HasPosition() {
 				args = append(args, "--position", fmt.Sprintf("%d,%d", options.Customization.X, options.Customization.Y))
 			}
 
@@ -0,0 +1,36 @@
+package terminal
+
+// HasPosition reports whether a window position is set. Negative values and
+// the zero value leave the position to the window manager.
+func (c *Customization) HasPosition() bool {
+	return c.X >= 0 && c.Y >= 0 && (c.X > 0 || c.Y > 0)
+}
+
+// Unsupported returns the customization options that are set but that a
+// terminal with the given features cannot apply. Builders skip them.
+func (c *Customization) Unsupported(features TerminalFeatures) []string {
+	if c == nil {
+		return nil
+	}
+
+	var unsupported []string
+	if (c.Width > 0 || c.Height > 0) && !features.WindowSize {
+		unsupported = append(unsupported, "width/height")
+	}
+	if c.HasPosition() && !features.WindowPosition {
+		unsupported = append(unsupported, "x/y")
+	}
+	if c.FontSize > 0 && !features.FontSize {
+		unsupported = append(unsupported, "font_size")
+	}
+	if c.Theme != "" && !features.ColorScheme {
+		unsupported = append(unsupported, "theme")
+	}
+	if (c.Fullscreen || c.Maximized) && !features.WindowState {
+		unsupported = append(unsupported, "fullscreen/maximized")
+	}
+	if c.AlwaysOnTop && !features.AlwaysOnTop {
+		unsupported = append(unsupported, "always_on_top")
+	}
+	return unsupported
+}
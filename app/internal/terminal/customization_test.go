@@ -2,6 +2,7 @@ package terminal
 
 import (
 	"os/exec"
+	"strings"
 	"testing"
 )
 
@@ -350,3 +351,20 @@ func ExampleCustomization() {
 	// Use with terminal execution
 	_ = options
 }
+
+func TestCustomization_Unsupported(t *testing.T) {
+	c := &Customization{Width: 100, Height: 30, FontSize: 14, Theme: "nord", AlwaysOnTop: true}
+	got := c.Unsupported(TerminalFeatures{WindowSize: true, FontSize: true})
+	want := []string{"theme", "always_on_top"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Unsupported() = %v, want %v", got, want)
+	}
+
+	var unset *Customization
+	if got := unset.Unsupported(TerminalFeatures{}); got != nil {
+		t.Errorf("nil customization Unsupported() = %v, want nil", got)
+	}
+	if (&Customization{Width: 80}).HasPosition() {
+		t.Error("HasPosition() with no x/y = true, want the window manager's choice")
+	}
+}
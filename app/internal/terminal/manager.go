@@ -55,6 +55,8 @@ func (m *baseManager) ExecuteInTerminal(ctx context.Context, cmd *exec.Cmd, opti
 	} else if cmd.Dir != "" {
 		termCmd.Dir = cmd.Dir
 	}
+	// Pass the command's environment on; nil inherits ours
+	termCmd.Env = cmd.Env
 
 	// Start the terminal
 	if err := termCmd.Start(); err != nil {
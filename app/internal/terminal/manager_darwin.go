@@ -48,6 +48,7 @@ func (m *DarwinManager) ExecuteInTerminal(ctx context.Context, cmd *exec.Cmd, op
 		} else if cmd.Dir != "" {
 			osascriptCmd.Dir = cmd.Dir
 		}
+		osascriptCmd.Env = cmd.Env
 
 		if err := osascriptCmd.Start(); err != nil {
 			return &Error{
@@ -47,6 +47,7 @@ func (m *WindowsManager) ExecuteInTerminal(ctx context.Context, cmd *exec.Cmd, o
 		} else if cmd.Dir != "" {
 			wtCmd.Dir = cmd.Dir
 		}
+		wtCmd.Env = cmd.Env
 
 		return wtCmd.Start()
 	}
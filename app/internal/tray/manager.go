@@ -23,10 +23,25 @@ type Manager struct {
 
 // MenuItem represents a tray menu item
 type MenuItem struct {
-	Title   string
-	Tooltip string
-	Handler func()
-	item    *systray.MenuItem
+	Title     string
+	Tooltip   string
+	Handler   func()
+	Checkable bool
+	Checked   bool
+	item      *systray.MenuItem
+}
+
+// SetChecked updates the check mark of a checkable item
+func (mi *MenuItem) SetChecked(checked bool) {
+	mi.Checked = checked
+	if mi.item == nil {
+		return
+	}
+	if checked {
+		mi.item.Check()
+	} else {
+		mi.item.Uncheck()
+	}
 }
 
 // Config represents tray configuration
@@ -59,6 +74,14 @@ func (m *Manager) AddMenuItem(title, tooltip string, handler func()) *MenuItem {
 	return item
 }
 
+// AddCheckboxItem adds a menu item with a check mark to the tray
+func (m *Manager) AddCheckboxItem(title, tooltip string, checked bool, handler func()) *MenuItem {
+	item := m.AddMenuItem(title, tooltip, handler)
+	item.Checkable = true
+	item.Checked = checked
+	return item
+}
+
 // AddSeparator adds a separator to the menu
 func (m *Manager) AddSeparator() {
 	// Add a nil item to represent separator
@@ -100,7 +123,12 @@ func (m *Manager) setupMenu() {
 			continue
 		}
 
-		menuItem := systray.AddMenuItem(item.Title, item.Tooltip)
+		var menuItem *systray.MenuItem
+		if item.Checkable {
+			menuItem = systray.AddMenuItemCheckbox(item.Title, item.Tooltip, item.Checked)
+		} else {
+			menuItem = systray.AddMenuItem(item.Title, item.Tooltip)
+		}
 		item.item = menuItem
 
 		// Handle click in goroutine
@@ -18,9 +18,16 @@ type Manager struct {
 
 // MenuItem represents a tray menu item
 type MenuItem struct {
-	Title   string
-	Tooltip string
-	Handler func()
+	Title     string
+	Tooltip   string
+	Handler   func()
+	Checkable bool
+	Checked   bool
+}
+
+// SetChecked updates the check mark of a checkable item
+func (mi *MenuItem) SetChecked(checked bool) {
+	mi.Checked = checked
 }
 
 // Config represents tray configuration
@@ -54,6 +61,14 @@ func (m *Manager) AddMenuItem(title, tooltip string, handler func()) *MenuItem {
 	return item
 }
 
+// AddCheckboxItem adds a menu item with a check mark to the tray
+func (m *Manager) AddCheckboxItem(title, tooltip string, checked bool, handler func()) *MenuItem {
+	item := m.AddMenuItem(title, tooltip, handler)
+	item.Checkable = true
+	item.Checked = checked
+	return item
+}
+
 // AddSeparator adds a separator to the menu
 func (m *Manager) AddSeparator() {
 	m.menuItems = append(m.menuItems, nil)